@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/tunnel"
 )
 
 func runCompletion(args []string) {
@@ -22,7 +24,7 @@ func runCompletion(args []string) {
 
 // runInternalComplete is called by shell completion scripts to get dynamic candidates.
 // It prints one entry per line and is intentionally silent on errors.
-func runInternalComplete(args []string, inv config.Inventory, knownHosts []string) {
+func runInternalComplete(args []string, inv config.Inventory, snippets []config.Snippet, knownHosts []string) {
 	if len(args) == 0 {
 		return
 	}
@@ -35,6 +37,15 @@ func runInternalComplete(args []string, inv config.Inventory, knownHosts []strin
 		for _, h := range knownHosts {
 			fmt.Println(h)
 		}
+	case "tunnels":
+		// Only the names are printed: no members are needed.
+		for _, t := range tunnel.List(inv, hosts.LoadResult{}) {
+			fmt.Println(t.Name)
+		}
+	case "snippets":
+		for _, s := range snippets {
+			fmt.Println(s.Name)
+		}
 	}
 	// unknown token → print nothing (graceful for completion scripts)
 }
@@ -51,19 +62,37 @@ _ssh_tui() {
   local cmd="${COMP_WORDS[1]}"
   local subcmd="${COMP_WORDS[2]}"
 
+  # Complete snippet names after -snippet
+  if [[ "${COMP_WORDS[COMP_CWORD-1]}" == -snippet || "${COMP_WORDS[COMP_CWORD-1]}" == --snippet ]]; then
+    COMPREPLY=($(compgen -W "$(ssh-tui __complete snippets 2>/dev/null)" -- "$cur"))
+    return
+  fi
+
   # Complete flags when the current word starts with -
   if [[ "$cur" == -* ]]; then
     local flags="-config -hosts -known-hosts -no-tmux -popup -debug"
     if [[ "$cmd" == list || "$cmd" == l ]]; then
       flags="$flags -json"
     fi
+    if [[ "$cmd" == import ]]; then
+      flags="$flags -dry-run -merge"
+    fi
+    if [[ "$cmd" == explain || "$cmd" == e ]]; then
+      flags="$flags -group"
+    fi
+    if [[ "$cmd" == connect || "$cmd" == c ]]; then
+      flags="$flags -skip-unreachable -snippet"
+    fi
+    if [[ "$cmd" == exec || "$cmd" == x ]]; then
+      flags="$flags -parallel -serial -batch -halt-on-error -pause -timeout -json"
+    fi
     COMPREPLY=($(compgen -W "$flags" -- "$cur"))
     return
   fi
 
   case $COMP_CWORD in
     1)
-      COMPREPLY=($(compgen -W "connect c list l completion" -- "$cur"))
+      COMPREPLY=($(compgen -W "connect c list l explain e exec x tunnel t import completion" -- "$cur"))
       ;;
     2)
       case $cmd in
@@ -73,11 +102,31 @@ _ssh_tui() {
         list|l)
           COMPREPLY=($(compgen -W "groups g hosts h" -- "$cur"))
           ;;
+        explain|e)
+          COMPREPLY=($(compgen -W "host h" -- "$cur"))
+          ;;
+        exec|x)
+          COMPREPLY=($(compgen -W "group g" -- "$cur"))
+          ;;
+        tunnel|t)
+          COMPREPLY=($(compgen -W "up down status" -- "$cur"))
+          ;;
+        import)
+          COMPREPLY=($(compgen -W "ansible" -- "$cur"))
+          ;;
         completion)
           COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
           ;;
       esac
       ;;
+    *)
+      case $cmd in
+        import)
+          COMPREPLY=($(compgen -f -- "$cur"))
+          return
+          ;;
+      esac
+      ;;&
     3)
       case $cmd in
         connect|c)
@@ -90,6 +139,15 @@ _ssh_tui() {
               ;;
           esac
           ;;
+        explain|e)
+          COMPREPLY=($(compgen -W "$(ssh-tui __complete hosts 2>/dev/null)" -- "$cur"))
+          ;;
+        exec|x)
+          COMPREPLY=($(compgen -W "$(ssh-tui __complete groups 2>/dev/null)" -- "$cur"))
+          ;;
+        tunnel|t)
+          COMPREPLY=($(compgen -W "$(ssh-tui __complete tunnels 2>/dev/null)" -- "$cur"))
+          ;;
       esac
       ;;
   esac
@@ -125,6 +183,18 @@ _ssh_tui() {
     if [[ "$cmd" == (list|l) ]]; then
       flags+=('-json[output as JSON]')
     fi
+    if [[ "$cmd" == (explain|e) ]]; then
+      flags+=('-group[resolve as a member of this group]:group:(${(f)"$(ssh-tui __complete groups 2>/dev/null)"})')
+    fi
+    if [[ "$cmd" == (connect|c) ]]; then
+      flags+=('-skip-unreachable[open only hosts that answer a TCP probe]' '-snippet[run a snippet on every host]:snippet:(${(f)"$(ssh-tui __complete snippets 2>/dev/null)"})')
+    fi
+    if [[ "$cmd" == (exec|x) ]]; then
+      flags+=('-parallel[hosts to run on at once]:count:' '-serial[one host at a time]' '-batch[hosts per batch]:count:' '-halt-on-error[stop after a failed batch]' '-pause[ask before every batch]' '-timeout[per-host timeout]:duration:' '-json[output as JSON]')
+    fi
+    if [[ "$cmd" == import ]]; then
+      flags+=('-dry-run[print changes without writing]' '-merge[existing entries]:strategy:(union replace skip)')
+    fi
     _describe 'flag' flags
     return
   fi
@@ -137,6 +207,13 @@ _ssh_tui() {
         'c:alias for connect'
         'list:list groups or hosts'
         'l:alias for list'
+        'explain:show effective settings of a host'
+        'e:alias for explain'
+        'exec:run a command on every host of a group'
+        'x:alias for exec'
+        'tunnel:start, stop or show configured tunnels'
+        't:alias for tunnel'
+        'import:import groups from another inventory'
         'completion:output shell completion script'
       )
       _describe 'command' cmds
@@ -163,6 +240,36 @@ _ssh_tui() {
           )
           _describe 'subcommand' sub
           ;;
+        explain|e)
+          local -a sub
+          sub=(
+            'host:explain a host'
+            'h:alias for host'
+          )
+          _describe 'subcommand' sub
+          ;;
+        exec|x)
+          local -a sub
+          sub=(
+            'group:run on all hosts in a group'
+            'g:alias for group'
+          )
+          _describe 'subcommand' sub
+          ;;
+        tunnel|t)
+          local -a sub
+          sub=(
+            'up:start a tunnel'
+            'down:stop a tunnel'
+            'status:show tunnel state'
+          )
+          _describe 'subcommand' sub
+          ;;
+        import)
+          local -a formats
+          formats=('ansible:Ansible INI or YAML inventory')
+          _describe 'format' formats
+          ;;
         completion)
           local -a shells
           shells=('bash:bash completion script' 'zsh:zsh completion script')
@@ -170,6 +277,12 @@ _ssh_tui() {
           ;;
       esac
       ;;
+    *)
+      if [[ "$cmd" == import ]]; then
+        _files
+        return
+      fi
+      ;|
     4)
       case $cmd in
         connect|c)
@@ -186,6 +299,21 @@ _ssh_tui() {
               ;;
           esac
           ;;
+        explain|e)
+          local -a hosts
+          hosts=(${(f)"$(ssh-tui __complete hosts 2>/dev/null)"})
+          _describe 'host' hosts
+          ;;
+        exec|x)
+          local -a groups
+          groups=(${(f)"$(ssh-tui __complete groups 2>/dev/null)"})
+          _describe 'group' groups
+          ;;
+        tunnel|t)
+          local -a tunnels
+          tunnels=(${(f)"$(ssh-tui __complete tunnels 2>/dev/null)"})
+          _describe 'tunnel' tunnels
+          ;;
       esac
       ;;
   esac
@@ -1,37 +1,64 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/mux"
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
 	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+	"github.com/al-bashkir/ssh-tui/internal/tmpl"
 	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
 )
 
-func runConnect(args []string, cfg config.Config, inv config.Inventory, noTmux bool) {
+const connectGroupUsage = "Usage: ssh-tui connect group NAME [--skip-unreachable] [--snippet NAME]"
+
+func runConnect(args []string, cfg config.Config, inv config.Inventory, snippets []config.Snippet, res hosts.LoadResult, noTmux bool) {
 	if len(args) == 0 {
 		fatal(fmt.Errorf("connect requires a subcommand: group|g or host|h\nUsage: ssh-tui connect group|host NAME"))
 	}
 	switch args[0] {
 	case "group", "g":
-		if len(args) < 2 {
-			fatal(fmt.Errorf("connect group requires a name\nUsage: ssh-tui connect group NAME"))
-		}
-		connectGroup(args[1], cfg, inv, noTmux)
+		connectGroup(args[1:], cfg, inv, snippets, res, noTmux)
 	case "host", "h":
 		if len(args) < 2 {
 			fatal(fmt.Errorf("connect host requires a name\nUsage: ssh-tui connect host NAME"))
 		}
-		connectHost(args[1], cfg, inv)
+		connectHost(args[1], cfg, inv, res.Sourced)
 	default:
 		fatal(fmt.Errorf("unknown connect subcommand %q: use group|g or host|h", args[0]))
 	}
 }
 
-func connectGroup(name string, cfg config.Config, inv config.Inventory, noTmux bool) {
+func connectGroup(args []string, cfg config.Config, inv config.Inventory, snippets []config.Snippet, res hosts.LoadResult, noTmux bool) {
+	fs := flag.NewFlagSet("connect group", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	skipDown := fs.Bool("skip-unreachable", false, "probe the hosts first and open only the reachable ones")
+	snippetName := fs.String("snippet", "", "run the named snippet on every host; the sessions stay open")
+	if err := fs.Parse(args); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() == 0 {
+		fatal(fmt.Errorf("connect group requires a name\n%s", connectGroupUsage))
+	}
+	name := fs.Arg(0)
+	// Allow flags after the group name as well.
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() > 0 {
+		fatal(fmt.Errorf("connect group: unexpected argument %q", fs.Arg(0)))
+	}
+
 	var group config.Group
 	found := false
 	for _, g := range inv.Groups {
@@ -44,118 +71,291 @@ func connectGroup(name string, cfg config.Config, inv config.Inventory, noTmux b
 	if !found {
 		fatal(fmt.Errorf("group %q not found", name))
 	}
+	// Chains must be resolved before group.Hosts is replaced by the
+	// flattened member list.
+	chains := hosts.MemberChains(inv, group, res)
+	group.Hosts = hosts.GroupMembers(inv, group, res)
 	if len(group.Hosts) == 0 {
 		fatal(fmt.Errorf("group %q has no hosts", name))
 	}
 
-	// Build SSH commands with the same precedence as the TUI:
-	// Defaults → per-host override → Group settings.
-	base := sshcmd.FromDefaults(cfg.Defaults)
-	sshCmds := make([][]string, 0, len(group.Hosts))
+	remoteCmd := ""
+	if *snippetName != "" {
+		remoteCmd = groupSnippet(inv, res, snippets, group, *snippetName)
+	}
+
+	// Same precedence as the TUI (see resolve.Resolve).
+	in := resolve.Input{Defaults: cfg.Defaults, Inventory: inv, Sourced: res.Sourced, Flags: resolve.Flags{NoTmux: noTmux}}
+	if *skipDown || strings.TrimSpace(group.Preflight) == config.PreflightTCP {
+		group.Hosts = preflightGroup(group, chains, in, res, *skipDown)
+	}
+	in.Chain = []config.Group{group}
+	win := resolve.Resolve(in, "").Window
+
+	var (
+		sshCmds        = make([][]string, 0, len(group.Hosts))
+		windows, panes []string
+		tags           []tmx.Tag
+		errs           tmpl.Errors
+	)
 	for _, h := range group.Hosts {
-		s := base
-		if hc, ok := sshcmd.FindHostConfig(inv.Hosts, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
+		in.Chain = chains.For(group, h)
+		s := resolve.Resolve(in, h).SSH
+		if remoteCmd != "" {
+			s.ForceTTY = true
+			s.RemoteCommand = sshcmd.KeepSessionOpen(remoteCmd)
+		}
+		window, pane, err := resolve.Names(in, h, &s, win)
+		if err != nil {
+			errs = append(errs, tmpl.HostError{Host: h, Err: err})
+			continue
 		}
-		s = sshcmd.ApplyGroup(s, group)
 		cmd, err := sshcmd.BuildCommand(h, s)
 		if err != nil {
-			fatal(fmt.Errorf("build ssh command for %s: %w", h, err))
+			errs = append(errs, tmpl.HostError{Host: h, Err: err})
+			continue
 		}
+		warnIgnored(h, s)
 		sshCmds = append(sshCmds, cmd)
+		windows = append(windows, defaultName(window, tmx.GroupWindowName([]string{h}, &group)))
+		panes = append(panes, defaultName(pane, h))
+		tags = append(tags, tmx.Tag{Host: h, Group: group.Name, Keep: cfg.Defaults.RemainOnExit})
 	}
+	if len(errs) > 0 {
+		hostErrors(errs)
+	}
+
+	mx := mux.Select(cfg.Defaults.Multiplexer)
+	mode := tmx.ResolveOpenMode(win.Tmux, win.OpenMode, mx.Inside(), len(sshCmds))
+	execConnect(sshCmds, windows, panes, tags, win.ReuseWindow, cfg.Defaults, &group, mode, mx)
+}
 
-	// Resolve effective tmux / open-mode settings (group overrides defaults).
-	tmuxSetting := cfg.Defaults.Tmux
-	if strings.TrimSpace(group.Tmux) != "" {
-		tmuxSetting = group.Tmux
+// defaultName returns name, or def when no template set it.
+func defaultName(name, def string) string {
+	if name == "" {
+		return def
 	}
-	openModeSetting := cfg.Defaults.OpenMode
-	if strings.TrimSpace(group.OpenMode) != "" {
-		openModeSetting = group.OpenMode
+	return name
+}
+
+// hostErrors reports the template and command errors of every host on
+// stderr and exits before anything is launched.
+func hostErrors(errs tmpl.Errors) {
+	_, _ = fmt.Fprintln(os.Stderr, "cannot connect:")
+	for _, e := range errs {
+		_, _ = fmt.Fprintf(os.Stderr, "  %s: %v\n", e.Host, e.Err)
 	}
-	if noTmux {
-		tmuxSetting = "never"
+	os.Exit(1)
+}
+
+// groupSnippet returns the command of the snippet named name; it must be
+// offered for group (global, or scoped to the group or a tag of it or of a
+// member).
+func groupSnippet(inv config.Inventory, res hosts.LoadResult, snippets []config.Snippet, group config.Group, name string) string {
+	sn, ok := config.FindSnippet(snippets, name)
+	if !ok {
+		fatal(fmt.Errorf("snippet %q not found", name))
 	}
+	if len(hosts.OfferedSnippets(inv, res.Sourced, []config.Snippet{sn}, &group, group.Hosts)) == 0 {
+		fatal(fmt.Errorf("snippet %q is not offered for group %q", name, group.Name))
+	}
+	return sn.Command
+}
 
-	inTmux := tmx.InTmux()
-	mode := tmx.ResolveOpenMode(tmuxSetting, openModeSetting, inTmux)
-	execConnect(group.Hosts, sshCmds, cfg.Defaults, &group, mode, inTmux)
+// preflightGroup TCP-probes the members of group and reports the
+// unreachable ones on stderr. It returns the hosts to open: the reachable
+// ones when skipDown is set, all of them otherwise.
+func preflightGroup(group config.Group, chains hosts.Chains, in resolve.Input, res hosts.LoadResult, skipDown bool) []string {
+	timeout, _, err := config.ParseProbe(in.Defaults)
+	if err != nil {
+		fatal(err)
+	}
+	addrs := make([]string, len(group.Hosts))
+	for i, h := range group.Hosts {
+		in.Chain = chains.For(group, h)
+		addrs[i], _ = probe.Target(h, resolve.Resolve(in, h).SSH, res.SSHConfig)
+	}
+	rep := probe.Preflight(context.Background(), group.Hosts, addrs, timeout)
+	_, _ = fmt.Fprintf(os.Stderr, "preflight: %d up, %d down\n", len(rep.Up), len(rep.Down))
+	for i, h := range rep.Down {
+		_, _ = fmt.Fprintf(os.Stderr, "  %s: %s\n", h, rep.Reasons[i])
+	}
+	if !skipDown {
+		return group.Hosts
+	}
+	if len(rep.Up) == 0 {
+		fatal(fmt.Errorf("group %q has no reachable hosts", group.Name))
+	}
+	return rep.Up
 }
 
-func connectHost(name string, cfg config.Config, inv config.Inventory) {
-	// Build SSH command with the same precedence as the TUI:
-	// Defaults → per-host override (no group).
-	base := sshcmd.FromDefaults(cfg.Defaults)
-	s := base
-	if hc, ok := sshcmd.FindHostConfig(inv.Hosts, name); ok {
-		s = sshcmd.ApplyHost(s, hc)
+func connectHost(name string, cfg config.Config, inv config.Inventory, sourced []sources.Host) {
+	// Same precedence as the TUI (see resolve.Resolve), without a group.
+	in := resolve.Input{Defaults: cfg.Defaults, Inventory: inv, Sourced: sourced}
+	r := resolve.Resolve(in, name)
+	window, pane, err := resolve.Names(in, name, &r.SSH, r.Window)
+	if err != nil {
+		hostErrors(tmpl.Errors{{Host: name, Err: err}})
 	}
-	cmd, err := sshcmd.BuildCommand(name, s)
+	cmd, err := sshcmd.BuildCommand(name, r.SSH)
 	if err != nil {
 		fatal(fmt.Errorf("build ssh command for %s: %w", name, err))
 	}
+	warnIgnored(name, r.SSH)
+
+	mx := mux.Select(cfg.Defaults.Multiplexer)
+	mode := tmx.ResolveOpenMode(r.Window.Tmux, r.Window.OpenMode, mx.Inside(), 1)
+	execConnect([][]string{cmd}, []string{defaultName(window, tmx.WindowName(name))}, []string{defaultName(pane, name)}, []tmx.Tag{{Host: name, Keep: cfg.Defaults.RemainOnExit}}, r.Window.ReuseWindow, cfg.Defaults, nil, mode, mx)
+}
 
-	inTmux := tmx.InTmux()
-	mode := tmx.ResolveOpenMode(cfg.Defaults.Tmux, cfg.Defaults.OpenMode, inTmux)
-	execConnect([]string{name}, [][]string{cmd}, cfg.Defaults, nil, mode, inTmux)
+// warnIgnored warns about the settings of host its backend does not pass on.
+func warnIgnored(host string, s sshcmd.Settings) {
+	if ignored := sshcmd.Ignored(s); len(ignored) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %s: %s ignores %s\n", host, s.Backend, strings.Join(ignored, ", "))
+	}
 }
 
-// execConnect dispatches SSH commands using the same logic as the TUI's dispatchConnect.
+// execConnect dispatches SSH commands using the same logic as the TUI's
+// dispatchConnect. windows and panes are the expanded names of each host;
+// a shared window takes the first. tags mark the panes for the Sessions tab.
+// mx opens the windows; only tmux also reuses them and works from outside.
 func execConnect(
-	hosts []string,
 	sshCmds [][]string,
+	windows, panes []string,
+	tags []tmx.Tag,
+	reuse string,
 	defaults config.Defaults,
 	group *config.Group,
 	mode tmx.OpenMode,
-	inTmux bool,
+	mx mux.Multiplexer,
 ) {
-	wName := tmx.GroupWindowName(hosts, group)
-
-	switch {
-	case mode == tmx.OpenCurrent:
+	if mode == tmx.OpenCurrent {
 		if len(sshCmds) > 1 {
-			fatal(fmt.Errorf("multi-host requires tmux (set open_mode to tmux-window or tmux-pane)"))
+			fatal(fmt.Errorf(`multi-host connect needs tmux: tmux is "never"`))
 		}
 		if err := execReplace(sshCmds[0]); err != nil {
 			fatal(err)
 		}
+		return
+	}
 
-	case !inTmux:
-		if len(sshCmds) > 1 {
-			fatal(fmt.Errorf("multi-host requires an active tmux session"))
+	oneWindow := mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1)
+	ps := tmx.ResolvePaneSettings(defaults, group, len(sshCmds))
+	windowCount := 1
+	if oneWindow {
+		windowCount = tmx.WindowCount(len(sshCmds), ps.MaxPanes)
+	}
+	inMux := mx.Inside()
+	var ignored []string
+	if !mux.IsTmux(mx) {
+		if !inMux {
+			fatal(mux.NotInside(mx))
 		}
-		// NewSessionCmd uses -A so it attaches to an existing session instead of failing.
-		if err := execReplace(tmx.NewSessionCmd(defaults.TmuxSession, sshCmds[0])); err != nil {
-			fatal(err)
+		if reuse = strings.TrimSpace(reuse); reuse == config.ReuseFocus || reuse == config.ReuseAsk {
+			ignored = append(ignored, "reuse_window")
 		}
-
-	case mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1):
-		// Open all hosts as panes in a single new tmux window.
-		ps := tmx.ResolvePaneSettings(defaults, group, len(sshCmds))
-		if err := tmx.OpenOneWindow(sshCmds, tmx.OneWindowOpts{
-			WindowName:       wName,
-			PaneTitles:       hosts,
+	} else if reuseOpen(tags, reuse, oneWindow && windowCount == 1, inMux) {
+		return
+	}
+	// Outside tmux the windows are built in the tmux_session, started
+	// detached if needed, and the terminal then attaches to it.
+	session := ""
+	if !inMux {
+		session = tmx.SessionName(defaults.TmuxSession)
+	}
+	if oneWindow {
+		// Open all hosts as panes in a single new window (or
+		// max_panes_per_window windows).
+		opts := tmx.OneWindowOpts{
+			Session:          session,
+			WindowName:       windows[0],
+			PaneTitles:       panes,
+			Tags:             tags,
 			SplitFlag:        ps.SplitFlag,
 			Layout:           ps.Layout,
 			SyncPanes:        ps.SyncPanes,
 			PaneBorderFormat: ps.BorderFormat,
 			PaneBorderStatus: ps.BorderStatus,
-		}); err != nil {
+			MaxPanes:         ps.MaxPanes,
+		}
+		if err := mx.OpenPanes(sshCmds, opts); err != nil {
 			fatal(err)
 		}
-		_, _ = fmt.Fprintf(os.Stderr, "opened %d in one window\n", len(sshCmds))
-
-	default:
-		// OpenWindow: one tmux window per host.
+		ignored = append(mx.Unsupported(len(sshCmds), opts), ignored...)
+		if slices.Contains(ignored, "max_panes_per_window") {
+			windowCount = 1
+		}
+	} else {
+		// OpenWindow: one window per host.
 		for i, sshCmd := range sshCmds {
-			name := tmx.GroupWindowName(hosts[i:i+1], group)
-			tmuxCmd := tmx.NewWindowCmd(name, sshCmd)
-			// #nosec G204 -- tmux argv is constructed (no shell) from known host/group settings.
-			if err := exec.Command(tmuxCmd[0], tmuxCmd[1:]...).Run(); err != nil {
-				fatal(fmt.Errorf("tmux new-window: %w", err))
+			if err := mx.NewWindow(session, windows[i], sshCmd, tags[i]); err != nil {
+				fatal(err)
 			}
 		}
+	}
+	if len(ignored) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %s ignores %s\n", mx.Name(), strings.Join(ignored, ", "))
+	}
+	if !inMux {
+		if err := execReplace(tmx.AttachSessionCmd(session)); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	switch {
+	case windowCount > 1:
+		_, _ = fmt.Fprintf(os.Stderr, "opened %d in %d windows\n", len(sshCmds), windowCount)
+	case oneWindow:
+		_, _ = fmt.Fprintf(os.Stderr, "opened %d in one window\n", len(sshCmds))
+	default:
 		_, _ = fmt.Fprintf(os.Stderr, "opened %d\n", len(sshCmds))
 	}
 }
+
+// reuseOpen focuses the pane already open for the hosts of tags when
+// reuse_window is "focus", or "ask" and the user agrees. It reports whether
+// it did; outside tmux it attaches to the session of the pane instead.
+func reuseOpen(tags []tmx.Tag, reuse string, oneWindow, inTmux bool) bool {
+	reuse = strings.TrimSpace(reuse)
+	if len(tags) == 0 || (reuse != config.ReuseFocus && reuse != config.ReuseAsk) {
+		return false
+	}
+	panes, err := tmx.ListPanes()
+	if err != nil {
+		return false
+	}
+	hostNames := make([]string, len(tags))
+	for i, t := range tags {
+		hostNames[i] = t.Host
+	}
+	p, ok := tmx.FindOpen(panes, hostNames, tags[0].Group, oneWindow)
+	if !ok {
+		return false
+	}
+	if reuse == config.ReuseAsk {
+		_, _ = fmt.Fprintf(os.Stderr, "%s is already open in %s. Focus it? [Y/n] ", p.Host, p.Target())
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && answer == "" {
+			return false
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y", "yes":
+		default:
+			return false
+		}
+	}
+	if err := tmx.SelectPane(p); err != nil {
+		fatal(err)
+	}
+	if !inTmux {
+		if err := execReplace(tmx.AttachCmd(p)); err != nil {
+			fatal(err)
+		}
+		return true
+	}
+	if err := tmx.SwitchClient(p); err != nil {
+		fatal(err)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "focused %s (%s)\n", p.Host, p.Target())
+	return true
+}
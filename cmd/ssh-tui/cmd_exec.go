@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/runner"
+)
+
+const execUsage = "Usage: ssh-tui exec group NAME [--parallel N | --serial | --batch N [--halt-on-error] [--pause]] [--timeout D] [--json] -- COMMAND"
+
+type execReport struct {
+	Command string           `json:"command"`
+	Group   string           `json:"group"`
+	Results []execHostReport `json:"results"`
+}
+
+type execHostReport struct {
+	Host       string        `json:"host"`
+	Status     runner.Status `json:"status"`
+	ExitCode   int           `json:"exit_code"`
+	Stdout     string        `json:"stdout"`
+	Stderr     string        `json:"stderr"`
+	Error      string        `json:"error,omitempty"`
+	DurationMS int64         `json:"duration_ms"`
+}
+
+func runExec(args []string, cfg config.Config, inv config.Inventory, res hosts.LoadResult) {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	parallel := fs.Int("parallel", cfg.Defaults.ExecParallel, "hosts to run on at once")
+	timeoutFlag := fs.String("timeout", cfg.Defaults.ExecTimeout, `per-host timeout ("0" = none)`)
+	asJSON := fs.Bool("json", false, "print a JSON report")
+	serial := fs.Bool("serial", false, "run on one host at a time (same as --batch 1)")
+	batch := fs.Int("batch", 0, "run in batches of N hosts, in group order")
+	halt := fs.Bool("halt-on-error", false, "skip the remaining batches after a host failed")
+	pause := fs.Bool("pause", false, "ask before every batch after the first")
+	if err := fs.Parse(args); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() < 2 || (fs.Arg(0) != "group" && fs.Arg(0) != "g") {
+		fatal(fmt.Errorf("exec requires a group\n%s", execUsage))
+	}
+	name := fs.Arg(1)
+	// Flags may also follow the group name; everything after them (or
+	// after "--") is the command.
+	if err := fs.Parse(fs.Args()[2:]); err != nil {
+		fatal(err)
+	}
+	command := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if command == "" {
+		fatal(fmt.Errorf("exec requires a command\n%s", execUsage))
+	}
+	if *parallel < 1 {
+		fatal(fmt.Errorf("exec: --parallel must be at least 1"))
+	}
+	if *serial {
+		if *batch > 1 {
+			fatal(fmt.Errorf("exec: --serial and --batch %d conflict", *batch))
+		}
+		*batch = 1
+	}
+	if *batch < 0 {
+		fatal(fmt.Errorf("exec: --batch must be at least 1"))
+	}
+	if (*halt || *pause) && *batch == 0 {
+		fatal(fmt.Errorf("exec: --halt-on-error and --pause need --serial or --batch N"))
+	}
+	timeout, err := config.ParseExecTimeout(*timeoutFlag)
+	if err != nil {
+		fatal(err)
+	}
+
+	var group config.Group
+	found := false
+	for _, g := range inv.Groups {
+		if strings.EqualFold(g.Name, name) {
+			group = g
+			found = true
+			break
+		}
+	}
+	if !found {
+		fatal(fmt.Errorf("group %q not found", name))
+	}
+	chains := hosts.MemberChains(inv, group, res)
+	members := hosts.GroupMembers(inv, group, res)
+	if len(members) == 0 {
+		fatal(fmt.Errorf("group %q has no hosts", name))
+	}
+
+	in := resolve.Input{Defaults: cfg.Defaults, Inventory: inv, Sourced: res.Sourced}
+	targets := make([]runner.Target, 0, len(members))
+	for _, h := range members {
+		in.Chain = chains.For(group, h)
+		s := resolve.Resolve(in, h).SSH
+		argv, err := runner.Command(h, s, command)
+		if err != nil {
+			fatal(fmt.Errorf("build ssh command for %s: %w", h, err))
+		}
+		warnIgnored(h, s)
+		targets = append(targets, runner.Target{Host: h, Argv: argv})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ropts := runner.Options{Parallel: *parallel, Timeout: timeout, Batch: *batch, HaltOnError: *halt}
+	var update func(int, runner.Result)
+	if *batch > 0 && !*asJSON {
+		// Rolling runs can take long: print every host as it finishes.
+		update = func(_ int, r runner.Result) {
+			if r.Status.Done() {
+				printExecResult(r)
+			}
+		}
+	}
+	if *pause {
+		stdin := bufio.NewReader(os.Stdin)
+		ropts.BeforeBatch = func(start, end int) bool {
+			return confirmBatch(stdin, targets, start, end)
+		}
+	}
+	results := runner.Run(ctx, targets, ropts, update)
+
+	switch {
+	case *asJSON:
+		printExecJSON(group.Name, command, results)
+	case update != nil:
+		printExecSummary(results)
+	default:
+		for _, r := range results {
+			printExecResult(r)
+		}
+		printExecSummary(results)
+	}
+	for _, r := range results {
+		if r.Status != runner.OK {
+			stop()
+			os.Exit(1)
+		}
+	}
+}
+
+func printExecJSON(group, command string, results []runner.Result) {
+	rep := execReport{Command: command, Group: group, Results: make([]execHostReport, 0, len(results))}
+	for _, r := range results {
+		rep.Results = append(rep.Results, execHostReport{
+			Host:       r.Host,
+			Status:     r.Status,
+			ExitCode:   r.ExitCode,
+			Stdout:     r.Stdout,
+			Stderr:     r.Stderr,
+			Error:      r.Err,
+			DurationMS: r.Duration.Milliseconds(),
+		})
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		fatal(err)
+	}
+}
+
+// confirmBatch asks on the terminal whether to run targets[start:end].
+func confirmBatch(stdin *bufio.Reader, targets []runner.Target, start, end int) bool {
+	hosts := make([]string, 0, end-start)
+	for _, t := range targets[start:end] {
+		hosts = append(hosts, t.Host)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "-- next: %s (%d-%d of %d). Continue? [Y/n] ", strings.Join(hosts, ", "), start+1, end, len(targets))
+	answer, err := stdin.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func printExecResult(r runner.Result) {
+	fmt.Printf("== %s (%s)\n", r.Host, execResultSummary(r))
+	if r.Stdout != "" {
+		fmt.Print(withNewline(r.Stdout))
+	}
+	if r.Stderr != "" {
+		_, _ = fmt.Fprint(os.Stderr, withNewline(r.Stderr))
+	}
+}
+
+func printExecSummary(results []runner.Result) {
+	counts := map[runner.Status]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	var parts []string
+	for _, s := range []runner.Status{runner.OK, runner.Failed, runner.Unreachable, runner.TimedOut, runner.Canceled, runner.Skipped} {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	fmt.Printf("-- %d hosts: %s\n", len(results), strings.Join(parts, ", "))
+}
+
+func execResultSummary(r runner.Result) string {
+	s := r.Status.String()
+	if r.ExitCode >= 0 {
+		s += fmt.Sprintf(", exit %d", r.ExitCode)
+	}
+	if r.Err != "" {
+		s += ": " + r.Err
+	}
+	if r.Status == runner.Skipped {
+		return s
+	}
+	return s + ", " + r.Duration.Round(10*time.Millisecond).String()
+}
+
+func withNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+const explainUsage = "Usage: ssh-tui explain host NAME [--group G]"
+
+func runExplain(args []string, cfg config.Config, inv config.Inventory, res hosts.LoadResult, noTmux bool) {
+	if len(args) == 0 {
+		fatal(fmt.Errorf("explain requires a subcommand: host|h\n%s", explainUsage))
+	}
+	switch args[0] {
+	case "host", "h":
+		explainHost(args[1:], cfg, inv, res, noTmux)
+	default:
+		fatal(fmt.Errorf("unknown explain subcommand %q: use host|h", args[0]))
+	}
+}
+
+func explainHost(args []string, cfg config.Config, inv config.Inventory, res hosts.LoadResult, noTmux bool) {
+	fs := flag.NewFlagSet("explain host", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	groupName := fs.String("group", "", "resolve as a member of this group")
+	if err := fs.Parse(args); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() == 0 {
+		fatal(fmt.Errorf("explain host requires a name\n%s", explainUsage))
+	}
+	host := fs.Arg(0)
+	// Allow flags after the host name as well.
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() > 0 {
+		fatal(fmt.Errorf("explain host: unexpected argument %q", fs.Arg(0)))
+	}
+
+	in := resolve.Input{Defaults: cfg.Defaults, Inventory: inv, Sourced: res.Sourced, Flags: resolve.Flags{NoTmux: noTmux}}
+	title := host
+	if *groupName != "" {
+		var group config.Group
+		found := false
+		for _, g := range inv.Groups {
+			if strings.EqualFold(g.Name, *groupName) {
+				group = g
+				found = true
+				break
+			}
+		}
+		if !found {
+			fatal(fmt.Errorf("group %q not found", *groupName))
+		}
+		chains := hosts.MemberChains(inv, group, res)
+		if _, ok := chains[host]; !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s is not a member of group %q\n", host, group.Name)
+		}
+		in.Chain = chains.For(group, host)
+		names := make([]string, 0, len(in.Chain))
+		for _, g := range in.Chain {
+			names = append(names, g.Name)
+		}
+		title += " (group " + strings.Join(names, " > ") + ")"
+	}
+
+	r := resolve.Resolve(in, host)
+	fmt.Println(title)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, f := range r.Fields() {
+		value, origin := f.Value, string(f.Origin)
+		if value == "" {
+			value = "-"
+		}
+		if origin == "" {
+			origin = "-"
+		}
+		// Origin before value: values such as pane_border_format are long.
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\n", f.Name, origin, value)
+	}
+	_ = tw.Flush()
+
+	cmd, err := sshcmd.BuildCommand(host, r.SSH)
+	if err != nil {
+		fatal(fmt.Errorf("build ssh command for %s: %w", host, err))
+	}
+	fmt.Printf("command: %s\n", strings.Join(cmd, " "))
+	warnIgnored(host, r.SSH)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/al-bashkir/ssh-tui/internal/ansible"
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func runImport(args []string, inv config.Inventory, invPath string) {
+	if len(args) == 0 {
+		fatal(fmt.Errorf("import requires a format: ansible\nUsage: ssh-tui import ansible [--dry-run] [--merge union|replace|skip] FILE"))
+	}
+	switch args[0] {
+	case "ansible":
+		importAnsible(args[1:], inv, invPath)
+	default:
+		fatal(fmt.Errorf("unknown import format %q: use ansible", args[0]))
+	}
+}
+
+func importAnsible(args []string, inv config.Inventory, invPath string) {
+	fs := flag.NewFlagSet("import ansible", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	dryRun := fs.Bool("dry-run", false, "print the changes without writing hosts.toml")
+	mergeFlag := fs.String("merge", "union", "existing groups and hosts: union|replace|skip")
+	if err := fs.Parse(args); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() == 0 {
+		fatal(fmt.Errorf("import ansible requires a file\nUsage: ssh-tui import ansible [--dry-run] [--merge union|replace|skip] FILE"))
+	}
+	path := fs.Arg(0)
+	// Allow flags after the file name as well.
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		fatal(err)
+	}
+	if fs.NArg() > 0 {
+		fatal(fmt.Errorf("import ansible: unexpected argument %q", fs.Arg(0)))
+	}
+
+	strategy, err := config.ParseMergeStrategy(*mergeFlag)
+	if err != nil {
+		fatal(err)
+	}
+
+	parsed, err := ansible.Load(path)
+	if err != nil {
+		fatal(fmt.Errorf("%s: %w", path, err))
+	}
+	imported, warnings := ansible.ToInventory(parsed)
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	merged, changes := config.MergeInventory(inv, imported, strategy)
+	changed := 0
+	for _, c := range changes {
+		fmt.Println(c.String())
+		if c.Op != "=" {
+			changed++
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("dry run: %d of %d entries would change in %s\n", changed, len(changes), invPath)
+		return
+	}
+	if changed == 0 {
+		fmt.Println("nothing to import")
+		return
+	}
+	if _, err := config.SaveInventory(invPath, merged); err != nil {
+		fatal(err)
+	}
+	fmt.Printf("imported %d entries into %s\n", changed, invPath)
+}
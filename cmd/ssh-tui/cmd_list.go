@@ -7,9 +7,11 @@ import (
 	"os"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
 )
 
-func runList(args []string, inv config.Inventory, knownHosts []string) {
+func runList(args []string, inv config.Inventory, res hosts.LoadResult) {
 	if len(args) == 0 {
 		fatal(fmt.Errorf("list requires a subcommand: groups|g or hosts|h\nUsage: ssh-tui list groups|hosts [--json]"))
 	}
@@ -24,46 +26,62 @@ func runList(args []string, inv config.Inventory, knownHosts []string) {
 
 	switch sub {
 	case "groups", "g":
-		listGroups(inv, *jsonOut)
+		listGroups(inv, res, *jsonOut)
 	case "hosts", "h":
-		listHosts(knownHosts, *jsonOut)
+		listHosts(inv, res, *jsonOut)
 	default:
 		fatal(fmt.Errorf("unknown list subcommand %q: use groups|g or hosts|h", sub))
 	}
 }
 
-func listGroups(inv config.Inventory, asJSON bool) {
+func listGroups(inv config.Inventory, res hosts.LoadResult, asJSON bool) {
 	if asJSON {
 		type groupJSON struct {
-			Name  string   `json:"name"`
-			Hosts []string `json:"hosts"`
+			Name        string            `json:"name"`
+			Hosts       []string          `json:"hosts"`
+			Includes    []string          `json:"include_groups,omitempty"`
+			Tags        []string          `json:"tags,omitempty"`
+			Description string            `json:"description,omitempty"`
+			Meta        map[string]string `json:"meta,omitempty"`
 		}
 		out := make([]groupJSON, 0, len(inv.Groups))
 		for _, g := range inv.Groups {
-			hosts := g.Hosts
-			if hosts == nil {
-				hosts = []string{}
-			}
-			out = append(out, groupJSON{Name: g.Name, Hosts: hosts})
+			out = append(out, groupJSON{
+				Name:        g.Name,
+				Hosts:       hosts.GroupMembers(inv, g, res),
+				Includes:    g.IncludeGroups,
+				Tags:        config.NormalizeTags(g.Tags),
+				Description: g.Description,
+				Meta:        g.Meta,
+			})
 		}
 		printJSON(out)
 		return
 	}
 	for _, g := range inv.Groups {
-		fmt.Printf("%s (%d hosts)\n", g.Name, len(g.Hosts))
+		fmt.Printf("%s (%d hosts)\n", g.Name, len(hosts.GroupMembers(inv, g, res)))
 	}
 }
 
-func listHosts(hosts []string, asJSON bool) {
+func listHosts(inv config.Inventory, res hosts.LoadResult, asJSON bool) {
 	if asJSON {
-		out := hosts
-		if out == nil {
-			out = []string{}
+		type hostJSON struct {
+			Host string `json:"host"`
+			Kind string `json:"kind,omitempty"` // docker or k8s; empty for ssh hosts
+			hosts.Info
+		}
+		out := make([]hostJSON, 0, len(res.Hosts))
+		for _, h := range res.Hosts {
+			hj := hostJSON{Host: h, Info: hosts.HostInfo(inv, res.Sourced, h)}
+			if t, ok := container.Parse(h); ok {
+				hj.Kind = t.Kind
+			}
+			out = append(out, hj)
 		}
 		printJSON(out)
 		return
 	}
-	for _, h := range hosts {
+	for _, h := range res.Hosts {
 		fmt.Println(h)
 	}
 }
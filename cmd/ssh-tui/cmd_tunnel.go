@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/tunnel"
+)
+
+const tunnelUsage = "Usage: ssh-tui tunnel up|down NAME, ssh-tui tunnel status [NAME]"
+
+func runTunnel(args []string, cfg config.Config, inv config.Inventory, res hosts.LoadResult) {
+	if len(args) == 0 {
+		fatal(fmt.Errorf("tunnel requires a subcommand: up|down|status\n%s", tunnelUsage))
+	}
+	dir, err := config.DefaultTunnelStateDir()
+	if err != nil {
+		fatal(err)
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "up", "down":
+		if len(rest) != 1 {
+			fatal(fmt.Errorf("tunnel %s requires a name\n%s", sub, tunnelUsage))
+		}
+		spec, ok := tunnel.Find(inv, res, rest[0])
+		if !ok {
+			fatal(fmt.Errorf("tunnel %q not found", rest[0]))
+		}
+		if sub == "down" {
+			if err := tunnel.Stop(dir, spec.Name); err != nil {
+				fatal(err)
+			}
+			fmt.Printf("stopped %s\n", spec.Name)
+			return
+		}
+		in := resolve.Input{Defaults: cfg.Defaults, Inventory: inv, Sourced: res.Sourced, Chain: spec.Chain(inv)}
+		argv, err := tunnel.Command(spec, resolve.Resolve(in, spec.Host).SSH)
+		if err != nil {
+			fatal(err)
+		}
+		st, err := tunnel.Start(dir, spec.Name, argv)
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Printf("started %s (%s via %s, pid %d)\n", spec.Name, spec.Describe(), spec.Host, st.PID)
+	case "status":
+		specs := tunnel.List(inv, res)
+		if len(rest) > 0 {
+			spec, ok := tunnel.Find(inv, res, rest[0])
+			if !ok {
+				fatal(fmt.Errorf("tunnel %q not found", rest[0]))
+			}
+			specs = []tunnel.Spec{spec}
+		}
+		printTunnelStatus(dir, specs)
+	default:
+		fatal(fmt.Errorf("unknown tunnel subcommand %q: use up|down|status", sub))
+	}
+}
+
+func printTunnelStatus(dir string, specs []tunnel.Spec) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, s := range specs {
+		info := tunnel.Check(dir, s.Name)
+		status := info.Status.String()
+		switch info.Status {
+		case tunnel.Running:
+			status += fmt.Sprintf(" (pid %d)", info.State.PID)
+		case tunnel.Failed:
+			status += ": " + info.Err
+		}
+		via := s.Host
+		if s.Group != "" {
+			via += " (group " + s.Group + ")"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Name, strings.TrimSpace(s.Describe()), via, status)
+	}
+	_ = tw.Flush()
+}
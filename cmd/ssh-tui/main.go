@@ -10,7 +10,9 @@ import (
 	"syscall"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/history"
 	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
 	"github.com/al-bashkir/ssh-tui/internal/ui"
 )
 
@@ -61,45 +63,92 @@ func main() {
 	if err != nil {
 		fatal(err)
 	}
+	if err := config.CheckBackendRefs(cfg, inv); err != nil {
+		fatal(err)
+	}
+	if err := sshcmd.RegisterBackends(cfg.Backends); err != nil {
+		fatal(err)
+	}
+
+	snippets, err := config.LoadSnippets(config.SnippetsPathFromConfigPath(cfgPathUsed))
+	if err != nil {
+		fatal(err)
+	}
+
+	var customHistory []string
+	if p, err := config.DefaultCustomHostHistoryPath(); err == nil {
+		customHistory, _ = history.Load(p)
+	}
+	var commandHistory []string
+	if p, err := config.DefaultCommandHistoryPath(); err == nil {
+		commandHistory, _ = history.Load(p)
+	}
 
 	knownPaths := []string(knownHosts)
-	res := hosts.LoadResult{}
-	var loadErrs []hosts.PathError
-	if cfg.Defaults.LoadKnownHosts {
-		res, loadErrs = hosts.LoadKnownHosts(knownPaths)
-	} else {
+	if !cfg.Defaults.LoadKnownHosts {
 		knownPaths = nil
-		res.Hosts = config.ConfigHosts(inv)
+	}
+	// loadHosts builds the host list. It runs the [[sources]] commands whose
+	// cache expired, so only the TUI and the subcommands taking hosts call
+	// it; cacheOnly reads the caches instead (shell completion).
+	loadHosts := func(cacheOnly bool) (hosts.LoadResult, []hosts.PathError) {
+		sourcesCache, _ := config.DefaultSourcesCacheDir()
+		return hosts.Load(hosts.Sources{
+			Defaults:   cfg.Defaults,
+			Inventory:  inv,
+			KnownHosts: knownPaths,
+			History:    customHistory,
+			Dynamic:    cfg.Sources,
+			CacheDir:   sourcesCache,
+			CacheOnly:  cacheOnly,
+		})
 	}
 
 	args := flag.Args()
 	if len(args) == 0 {
-		runTUI(ui.Options{
+		opts := ui.Options{
 			ConfigPath:    cfgPathUsed,
 			Config:        cfg,
 			InventoryPath: invPathUsed,
 			Inventory:     inv,
 			KnownHosts:    knownPaths,
-			Hosts:         res.Hosts,
-			SkippedLines:  res.SkippedLines,
-			LoadErrors:    loadErrs,
 			Debug:         debug,
 			Popup:         popup,
-		})
+
+			CustomHostHistory: customHistory,
+			Snippets:          snippets.Snippets,
+			CommandHistory:    commandHistory,
+		}
+		opts.SetLoadResult(loadHosts(false))
+		runTUI(opts)
 		return
 	}
 
 	switch args[0] {
 	case "connect", "c":
-		runConnect(args[1:], cfg, inv, noTmux)
+		res, _ := loadHosts(false)
+		runConnect(args[1:], cfg, inv, snippets.Snippets, res, noTmux)
 	case "list", "l":
-		runList(args[1:], inv, res.Hosts)
+		res, _ := loadHosts(false)
+		runList(args[1:], inv, res)
+	case "explain", "e":
+		res, _ := loadHosts(false)
+		runExplain(args[1:], cfg, inv, res, noTmux)
+	case "exec", "x":
+		res, _ := loadHosts(false)
+		runExec(args[1:], cfg, inv, res)
+	case "tunnel", "t":
+		res, _ := loadHosts(false)
+		runTunnel(args[1:], cfg, inv, res)
+	case "import":
+		runImport(args[1:], inv, invPathUsed)
 	case "completion", "comp":
 		runCompletion(args[1:])
 	case "__complete":
-		runInternalComplete(args[1:], inv, res.Hosts)
+		res, _ := loadHosts(true)
+		runInternalComplete(args[1:], inv, snippets.Snippets, res.Hosts)
 	default:
-		fatal(fmt.Errorf("unknown command %q\nUsage: ssh-tui [flags] [connect|list|completion] ...", args[0]))
+		fatal(fmt.Errorf("unknown command %q\nUsage: ssh-tui [flags] [connect|list|explain|exec|tunnel|import|completion] ...", args[0]))
 	}
 }
 
@@ -126,11 +175,22 @@ Usage:
   ssh-tui [flags]                        launch interactive TUI
   ssh-tui [flags] connect host NAME      connect to a host
   ssh-tui [flags] connect group NAME     connect to all hosts in a group
+                                         (--skip-unreachable, --snippet NAME)
   ssh-tui [flags] list hosts             print known hosts
   ssh-tui [flags] list groups            print configured groups
+  ssh-tui [flags] explain host NAME      show effective settings and their origin
+                                         (--group G)
+  ssh-tui [flags] exec group NAME -- CMD run CMD on every host and collect output
+                                         (--parallel N, --serial, --batch N,
+                                         --halt-on-error, --pause, --timeout D,
+                                         --json)
+  ssh-tui [flags] tunnel up|down NAME    start or stop a configured tunnel
+  ssh-tui [flags] tunnel status [NAME]   show tunnel state
+  ssh-tui [flags] import ansible FILE    import groups from an Ansible inventory
+                                         (--dry-run, --merge union|replace|skip)
   ssh-tui completion bash|zsh            print shell completion script
 
-Subcommand aliases:  connect=c  list=l  host=h  group=g  hosts=h  groups=g
+Subcommand aliases:  connect=c  list=l  explain=e  exec=x  tunnel=t  host=h  group=g  hosts=h  groups=g
 
 Flags:
 `)
@@ -0,0 +1,154 @@
+package ansible
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+var invalidGroupChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// Connection vars understood by the importer. Older ansible_ssh_* spellings
+// are accepted as fallbacks.
+var (
+	varHost = []string{"ansible_host", "ansible_ssh_host"}
+	varUser = []string{"ansible_user", "ansible_ssh_user"}
+	varPort = []string{"ansible_port", "ansible_ssh_port"}
+	varKey  = []string{"ansible_ssh_private_key_file", "ansible_private_key_file"}
+)
+
+func lookup(vars map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(vars[k]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// GroupName maps an Ansible group name onto the ssh-tui group name rules
+// (letters, digits, - and _).
+func GroupName(name string) string {
+	return strings.Trim(invalidGroupChars.ReplaceAllString(name, "_"), "_")
+}
+
+// ToInventory converts a parsed Ansible inventory into ssh-tui groups and host
+// overrides. Every group except "all" and "ungrouped" becomes a config.Group
+// whose hosts include those of its children. Group connection vars are taken
+// from the group itself, then its nearest ancestors (including "all").
+// ansible_host is kept as "-o HostName=..." so the inventory alias stays the
+// name shown in the list. Warnings describe vars or names that were dropped.
+func ToInventory(inv *Inventory) (config.Inventory, []string) {
+	out := config.Inventory{Version: 1}
+	var warnings []string
+
+	parents := make(map[string][]string)
+	for _, name := range inv.GroupNames() {
+		for _, c := range inv.Groups[name].Children {
+			parents[c] = append(parents[c], name)
+		}
+	}
+	if all, ok := inv.Groups["all"]; ok {
+		for _, name := range inv.GroupNames() {
+			if name != "all" && len(parents[name]) == 0 {
+				parents[name] = append(parents[name], all.Name)
+			}
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, name := range inv.GroupNames() {
+		if name == "all" || name == "ungrouped" {
+			continue
+		}
+		gname := GroupName(name)
+		if err := config.ValidateGroupName(gname); err != nil {
+			warnings = append(warnings, fmt.Sprintf("group %q skipped: %v", name, err))
+			continue
+		}
+		if prev, ok := seen[gname]; ok {
+			warnings = append(warnings, fmt.Sprintf("group %q skipped: name %q already used by %q", name, gname, prev))
+			continue
+		}
+		seen[gname] = name
+		if gname != name {
+			warnings = append(warnings, fmt.Sprintf("group %q renamed to %q", name, gname))
+		}
+
+		vars := inheritedVars(inv, parents, name)
+		g := config.Group{
+			Name:         gname,
+			User:         lookup(vars, varUser),
+			IdentityFile: lookup(vars, varKey),
+			Hosts:        inv.Members(name),
+		}
+		if p := lookup(vars, varPort); p != "" {
+			port, err := strconv.Atoi(p)
+			if err != nil || port < 1 || port > 65535 {
+				warnings = append(warnings, fmt.Sprintf("group %q: invalid port %q ignored", name, p))
+			} else {
+				g.Port = port
+			}
+		}
+		out.Groups = append(out.Groups, g)
+	}
+
+	hostNames := make([]string, 0, len(inv.Hosts))
+	for n := range inv.Hosts {
+		hostNames = append(hostNames, n)
+	}
+	sort.Strings(hostNames)
+	for _, n := range hostNames {
+		vars := inv.Hosts[n].Vars
+		h := config.Host{
+			Host:         n,
+			User:         lookup(vars, varUser),
+			IdentityFile: lookup(vars, varKey),
+		}
+		if p := lookup(vars, varPort); p != "" {
+			port, err := strconv.Atoi(p)
+			if err != nil || port < 1 || port > 65535 {
+				warnings = append(warnings, fmt.Sprintf("host %q: invalid port %q ignored", n, p))
+			} else {
+				h.Port = port
+			}
+		}
+		if addr := lookup(vars, varHost); addr != "" && addr != n {
+			h.ExtraArgs = []string{"-o", "HostName=" + addr}
+		}
+		if h.User == "" && h.Port == 0 && h.IdentityFile == "" && len(h.ExtraArgs) == 0 {
+			continue
+		}
+		out.Hosts = append(out.Hosts, h)
+	}
+	return out, warnings
+}
+
+// inheritedVars returns the vars of a group merged over those of its
+// ancestors; the nearest definition wins.
+func inheritedVars(inv *Inventory, parents map[string][]string, name string) map[string]string {
+	out := make(map[string]string)
+	visited := make(map[string]bool)
+	queue := []string{name}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+		if g, ok := inv.Groups[n]; ok {
+			for k, v := range g.Vars {
+				if _, set := out[k]; !set {
+					out[k] = v
+				}
+			}
+		}
+		queue = append(queue, parents[n]...)
+	}
+	return out
+}
@@ -0,0 +1,466 @@
+package ansible
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Inventory is a parsed Ansible inventory (INI or YAML).
+type Inventory struct {
+	Groups map[string]*Group
+	Hosts  map[string]*Host
+}
+
+// Group is an Ansible group with its direct hosts, child groups and vars.
+type Group struct {
+	Name     string
+	Hosts    []string
+	Children []string
+	Vars     map[string]string
+}
+
+// Host is an Ansible host and its vars (merged over every appearance).
+type Host struct {
+	Name string
+	Vars map[string]string
+}
+
+func newInventory() *Inventory {
+	return &Inventory{Groups: make(map[string]*Group), Hosts: make(map[string]*Host)}
+}
+
+func (inv *Inventory) group(name string) *Group {
+	g, ok := inv.Groups[name]
+	if !ok {
+		g = &Group{Name: name, Vars: make(map[string]string)}
+		inv.Groups[name] = g
+	}
+	return g
+}
+
+func (inv *Inventory) addHost(group, name string, vars map[string]string) {
+	h, ok := inv.Hosts[name]
+	if !ok {
+		h = &Host{Name: name, Vars: make(map[string]string)}
+		inv.Hosts[name] = h
+	}
+	for k, v := range vars {
+		h.Vars[k] = v
+	}
+	g := inv.group(group)
+	for _, existing := range g.Hosts {
+		if existing == name {
+			return
+		}
+	}
+	g.Hosts = append(g.Hosts, name)
+}
+
+func (inv *Inventory) addChild(parent, child string) {
+	g := inv.group(parent)
+	inv.group(child)
+	for _, c := range g.Children {
+		if c == child {
+			return
+		}
+	}
+	g.Children = append(g.Children, child)
+}
+
+// GroupNames returns the group names in sorted order.
+func (inv *Inventory) GroupNames() []string {
+	out := make([]string, 0, len(inv.Groups))
+	for n := range inv.Groups {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Members returns the hosts of a group including those of its children
+// (recursively), de-duplicated, in first-seen order.
+func (inv *Inventory) Members(name string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var walk func(string)
+	walk = func(n string) {
+		g, ok := inv.Groups[n]
+		if !ok || visiting[n] {
+			return
+		}
+		visiting[n] = true
+		for _, h := range g.Hosts {
+			if !seen[h] {
+				seen[h] = true
+				out = append(out, h)
+			}
+		}
+		for _, c := range g.Children {
+			walk(c)
+		}
+	}
+	walk(name)
+	return out
+}
+
+// Load reads an inventory file. Files ending in .yml/.yaml, or whose first
+// meaningful line is not an INI section or host line, are parsed as YAML.
+func Load(path string) (*Inventory, error) {
+	// #nosec G304 -- path is user-provided by design (CLI argument).
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return ParseYAML(bytes.NewReader(data))
+	case ".ini", ".cfg":
+		return ParseINI(bytes.NewReader(data))
+	}
+	if looksLikeYAML(data) {
+		return ParseYAML(bytes.NewReader(data))
+	}
+	return ParseINI(bytes.NewReader(data))
+}
+
+func looksLikeYAML(data []byte) bool {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if line == "---" {
+			return true
+		}
+		if strings.HasPrefix(line, "[") {
+			return false
+		}
+		f := strings.Fields(line)[0]
+		return strings.HasSuffix(f, ":") && !strings.Contains(f, "=")
+	}
+	return false
+}
+
+// ParseINI parses the Ansible INI inventory format: [group] sections with
+// "host key=value ..." lines, [group:children] and [group:vars].
+func ParseINI(r io.Reader) (*Inventory, error) {
+	inv := newInventory()
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	section, kind := "ungrouped", "hosts"
+	lineNo := 0
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section %q", lineNo, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			section, kind = name, "hosts"
+			if i := strings.LastIndex(name, ":"); i >= 0 {
+				section, kind = name[:i], name[i+1:]
+			}
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", lineNo)
+			}
+			switch kind {
+			case "hosts", "children", "vars":
+			default:
+				return nil, fmt.Errorf("line %d: unknown section type %q", lineNo, kind)
+			}
+			inv.group(section)
+			continue
+		}
+
+		fields, err := splitINIFields(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		switch kind {
+		case "children":
+			inv.addChild(section, fields[0])
+		case "vars":
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value", lineNo)
+			}
+			inv.group(section).Vars[strings.TrimSpace(k)] = unquote(strings.TrimSpace(v))
+		default:
+			vars := make(map[string]string)
+			for _, f := range fields[1:] {
+				k, v, ok := strings.Cut(f, "=")
+				if !ok {
+					return nil, fmt.Errorf("line %d: expected key=value, got %q", lineNo, f)
+				}
+				vars[k] = unquote(v)
+			}
+			names, err := ExpandHostPattern(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			for _, n := range names {
+				inv.addHost(section, n, vars)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// splitINIFields splits on whitespace, keeping quoted values together.
+func splitINIFields(line string) ([]string, error) {
+	var out []string
+	var b strings.Builder
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			b.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			b.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if b.Len() > 0 {
+				out = append(out, b.String())
+				b.Reset()
+			}
+		case r == '#' && b.Len() == 0:
+			// Inline comment.
+			return out, nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if b.Len() > 0 {
+		out = append(out, b.String())
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("empty line")
+	}
+	return out, nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ExpandHostPattern expands Ansible host ranges such as web[01:03].example
+// and db-[a:c]; names without a range are returned as is.
+func ExpandHostPattern(p string) ([]string, error) {
+	i := strings.Index(p, "[")
+	if i < 0 {
+		return []string{p}, nil
+	}
+	j := strings.Index(p[i:], "]")
+	if j < 0 {
+		return []string{p}, nil
+	}
+	j += i
+	spec := p[i+1 : j]
+	lo, hi, ok := strings.Cut(spec, ":")
+	if !ok {
+		// Not a range (e.g. an IPv6 literal); leave untouched.
+		return []string{p}, nil
+	}
+	step := 1
+	if h, st, ok := strings.Cut(hi, ":"); ok {
+		n, err := strconv.Atoi(st)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid range step in %q", p)
+		}
+		hi, step = h, n
+	}
+
+	rest, err := ExpandHostPattern(p[j+1:])
+	if err != nil {
+		return nil, err
+	}
+	prefix := p[:i]
+
+	var items []string
+	if a, errA := strconv.Atoi(lo); errA == nil {
+		b, errB := strconv.Atoi(hi)
+		if errB != nil || b < a {
+			return nil, fmt.Errorf("invalid range in %q", p)
+		}
+		width := 0
+		if len(lo) > 1 && lo[0] == '0' {
+			width = len(lo)
+		}
+		for n := a; n <= b; n += step {
+			items = append(items, fmt.Sprintf("%0*d", width, n))
+		}
+	} else if len(lo) == 1 && len(hi) == 1 && lo[0] <= hi[0] {
+		for c := lo[0]; c <= hi[0]; c += byte(step) {
+			items = append(items, string(c))
+			if int(c)+step > 255 {
+				break
+			}
+		}
+	} else {
+		return nil, fmt.Errorf("invalid range in %q", p)
+	}
+
+	out := make([]string, 0, len(items)*len(rest))
+	for _, it := range items {
+		for _, r := range rest {
+			out = append(out, prefix+it+r)
+		}
+	}
+	return out, nil
+}
+
+// ParseYAML parses the Ansible YAML inventory format:
+//
+//	all:
+//	  children:
+//	    web:
+//	      hosts:
+//	        web1.example.com:
+//	          ansible_port: 2222
+//	      vars:
+//	        ansible_user: deploy
+func ParseYAML(r io.Reader) (*Inventory, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return newInventory(), nil
+		}
+		return nil, err
+	}
+	inv := newInventory()
+	if len(doc.Content) == 0 {
+		return inv, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of groups", root.Line)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if err := inv.addYAMLGroup(root.Content[i].Value, root.Content[i+1], 0); err != nil {
+			return nil, err
+		}
+	}
+	return inv, nil
+}
+
+// addYAMLGroup walks a group node in document order so members keep the
+// order they were written in.
+func (inv *Inventory) addYAMLGroup(name string, n *yaml.Node, depth int) error {
+	if depth > 32 {
+		return fmt.Errorf("group %q: nesting too deep", name)
+	}
+	grp := inv.group(name)
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: group %q: expected a mapping", n.Line, name)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i].Value, n.Content[i+1]
+		switch key {
+		case "vars":
+			vars, err := yamlVars(val)
+			if err != nil {
+				return fmt.Errorf("group %q: %w", name, err)
+			}
+			for k, v := range vars {
+				grp.Vars[k] = v
+			}
+		case "hosts":
+			if err := inv.addYAMLHosts(name, val); err != nil {
+				return err
+			}
+		case "children":
+			if val.Kind == yaml.ScalarNode && val.Tag == "!!null" {
+				continue
+			}
+			if val.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: group %q: children must be a mapping", val.Line, name)
+			}
+			for j := 0; j+1 < len(val.Content); j += 2 {
+				child := val.Content[j].Value
+				inv.addChild(name, child)
+				if err := inv.addYAMLGroup(child, val.Content[j+1], depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (inv *Inventory) addYAMLHosts(group string, n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: group %q: hosts must be a mapping", n.Line, group)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		vars, err := yamlVars(n.Content[i+1])
+		if err != nil {
+			return fmt.Errorf("group %q: host %q: %w", group, n.Content[i].Value, err)
+		}
+		expanded, err := ExpandHostPattern(n.Content[i].Value)
+		if err != nil {
+			return fmt.Errorf("group %q: %w", group, err)
+		}
+		for _, e := range expanded {
+			inv.addHost(group, e, vars)
+		}
+	}
+	return nil
+}
+
+// yamlVars flattens a vars mapping; non-scalar values are kept as YAML text.
+func yamlVars(n *yaml.Node) (map[string]string, error) {
+	out := make(map[string]string)
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return out, nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: vars must be a mapping", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		v := n.Content[i+1]
+		if v.Kind == yaml.ScalarNode {
+			out[n.Content[i].Value] = v.Value
+			continue
+		}
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		out[n.Content[i].Value] = strings.TrimSpace(string(b))
+	}
+	return out, nil
+}
@@ -0,0 +1,153 @@
+package ansible
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+const iniInventory = `# comment
+mail.example.com
+
+[web]
+web[01:02].example.com ansible_user=deploy
+web3.example.com ansible_host=10.0.0.3 ansible_port=2222
+
+[db]
+db1 ansible_host=10.0.1.1 ansible_ssh_private_key_file="~/.ssh/db key"
+
+[prod:children]
+web
+db
+
+[prod:vars]
+ansible_user=ops
+ansible_port=2200
+
+[all:vars]
+ansible_ssh_private_key_file=~/.ssh/ops
+`
+
+const yamlInventory = `all:
+  hosts:
+    mail.example.com:
+  vars:
+    ansible_ssh_private_key_file: ~/.ssh/ops
+  children:
+    prod:
+      vars:
+        ansible_user: ops
+        ansible_port: 2200
+      children:
+        web:
+          hosts:
+            web[01:02].example.com:
+              ansible_user: deploy
+            web3.example.com:
+              ansible_host: 10.0.0.3
+              ansible_port: 2222
+        db:
+          hosts:
+            db1:
+              ansible_host: 10.0.1.1
+              ansible_ssh_private_key_file: "~/.ssh/db key"
+`
+
+func wantInventory() config.Inventory {
+	return config.Inventory{
+		Version: 1,
+		Groups: []config.Group{
+			{Name: "db", User: "ops", Port: 2200, IdentityFile: "~/.ssh/ops", Hosts: []string{"db1"}},
+			{Name: "prod", User: "ops", Port: 2200, IdentityFile: "~/.ssh/ops", Hosts: []string{"web01.example.com", "web02.example.com", "web3.example.com", "db1"}},
+			{Name: "web", User: "ops", Port: 2200, IdentityFile: "~/.ssh/ops", Hosts: []string{"web01.example.com", "web02.example.com", "web3.example.com"}},
+		},
+		Hosts: []config.Host{
+			{Host: "db1", IdentityFile: "~/.ssh/db key", ExtraArgs: []string{"-o", "HostName=10.0.1.1"}},
+			{Host: "web01.example.com", User: "deploy"},
+			{Host: "web02.example.com", User: "deploy"},
+			{Host: "web3.example.com", Port: 2222, ExtraArgs: []string{"-o", "HostName=10.0.0.3"}},
+		},
+	}
+}
+
+func TestToInventoryINIAndYAML(t *testing.T) {
+	d := t.TempDir()
+	for _, tc := range []struct{ file, data string }{
+		{"hosts", iniInventory},
+		{"hosts.yml", yamlInventory},
+		{"inventory", yamlInventory}, // detected by content
+	} {
+		p := filepath.Join(d, tc.file)
+		if err := os.WriteFile(p, []byte(tc.data), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		inv, err := Load(p)
+		if err != nil {
+			t.Fatalf("%s: Load: %v", tc.file, err)
+		}
+		got, warnings := ToInventory(inv)
+		if len(warnings) != 0 {
+			t.Fatalf("%s: warnings=%v", tc.file, warnings)
+		}
+		if want := wantInventory(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s:\ngot=%#v\nwant=%#v", tc.file, got, want)
+		}
+	}
+}
+
+func TestParseINIErrors(t *testing.T) {
+	for _, in := range []string{
+		"[web\nhost1\n",
+		"[web:bogus]\n",
+		"[web]\nhost1 ansible_user\n",
+		"[web:vars]\nnovalue\n",
+	} {
+		if _, err := ParseINI(strings.NewReader(in)); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestExpandHostPattern(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"plain.example", []string{"plain.example"}},
+		{"web[1:3]", []string{"web1", "web2", "web3"}},
+		{"web[08:10].x", []string{"web08.x", "web09.x", "web10.x"}},
+		{"db-[a:c]", []string{"db-a", "db-b", "db-c"}},
+		{"n[0:4:2]", []string{"n0", "n2", "n4"}},
+		{"r[1:2]-[a:b]", []string{"r1-a", "r1-b", "r2-a", "r2-b"}},
+	}
+	for _, tt := range tests {
+		got, err := ExpandHostPattern(tt.in)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%q: got=%v want=%v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ExpandHostPattern("web[3:1]"); err == nil {
+		t.Fatalf("expected error for reversed range")
+	}
+}
+
+func TestGroupNameSanitized(t *testing.T) {
+	inv, err := ParseINI(strings.NewReader("[web.prod]\nh1\n[web_prod]\nh2\n"))
+	if err != nil {
+		t.Fatalf("ParseINI: %v", err)
+	}
+	got, warnings := ToInventory(inv)
+	if len(got.Groups) != 1 || got.Groups[0].Name != "web_prod" {
+		t.Fatalf("groups=%#v", got.Groups)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("warnings=%v", warnings)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Built-in connection backends, as in the backend field.
+const (
+	BackendSSH  = "ssh"  // OpenSSH (the default)
+	BackendMosh = "mosh" // mosh; ssh options go into --ssh
+	BackendET   = "et"   // Eternal Terminal
+	BackendTsh  = "tsh"  // Teleport `tsh ssh`
+)
+
+// BuiltinBackends lists the built-in backends in display order.
+var BuiltinBackends = []string{BackendSSH, BackendMosh, BackendET, BackendTsh}
+
+// Backend is a user-defined connection backend: a wrapper command that
+// takes the arguments of a built-in backend.
+// Example TOML:
+//
+//	[[backends]]
+//	name = "corp-ssh"
+//	command = ["/usr/local/bin/corp-ssh", "--audit"]
+//	like = "ssh"
+type Backend struct {
+	Name    string   `toml:"name"`    // letters, digits, - and _; must not be a built-in name
+	Command []string `toml:"command"` // argv prefix, run without a shell
+	Like    string   `toml:"like"`    // built-in backend whose argument conventions are used
+}
+
+// ValidateBackends checks the [[backends]] entries of config.toml.
+func ValidateBackends(defs []Backend) error {
+	seen := make(map[string]bool)
+	for _, b := range defs {
+		if err := ValidateBackendName(b.Name); err != nil {
+			return err
+		}
+		if slices.Contains(BuiltinBackends, b.Name) {
+			return fmt.Errorf("backend %q: name is a built-in backend", b.Name)
+		}
+		if seen[b.Name] {
+			return fmt.Errorf("backend %q: duplicate name", b.Name)
+		}
+		seen[b.Name] = true
+		if len(b.Command) == 0 || strings.TrimSpace(b.Command[0]) == "" {
+			return fmt.Errorf("backend %q: command required", b.Name)
+		}
+		if !slices.Contains(BuiltinBackends, strings.TrimSpace(b.Like)) {
+			return fmt.Errorf("backend %q: like %q is invalid: use one of %s", b.Name, b.Like, strings.Join(BuiltinBackends, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateBackendName checks the spelling of a backend name; empty means
+// inherit. Whether the name is defined is checked by CheckBackend.
+func ValidateBackendName(name string) error {
+	if name = strings.TrimSpace(name); name == "" {
+		return nil
+	}
+	if !validGroupName.MatchString(name) {
+		return fmt.Errorf("backend %q is invalid: only letters, digits, - and _ are allowed", name)
+	}
+	return nil
+}
+
+// CheckBackend reports an error when name is neither empty, a built-in
+// backend nor one of defs.
+func CheckBackend(name string, defs []Backend) error {
+	name = strings.TrimSpace(name)
+	if name == "" || slices.Contains(BuiltinBackends, name) {
+		return nil
+	}
+	for _, b := range defs {
+		if b.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("backend %q is not defined: use one of %s or a [[backends]] name", name, strings.Join(BuiltinBackends, ", "))
+}
+
+// CheckBackendRefs checks that every backend named in inv is defined in
+// cfg. It runs once both files are loaded.
+func CheckBackendRefs(cfg Config, inv Inventory) error {
+	for _, g := range inv.Groups {
+		if err := CheckBackend(g.Backend, cfg.Backends); err != nil {
+			return fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+	}
+	for _, h := range inv.Hosts {
+		if err := CheckBackend(h.Backend, cfg.Backends); err != nil {
+			return fmt.Errorf("hosts: host %q: %w", h.Host, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestValidateBackends(t *testing.T) {
+	ok := []Backend{{Name: "corp", Command: []string{"corp-ssh"}, Like: "ssh"}}
+	if err := ValidateBackends(ok); err != nil {
+		t.Fatal(err)
+	}
+	bad := [][]Backend{
+		{{Name: "ssh", Command: []string{"x"}, Like: "ssh"}},
+		{{Name: "a b", Command: []string{"x"}, Like: "ssh"}},
+		{{Name: "corp", Like: "ssh"}},
+		{{Name: "corp", Command: []string{"x"}, Like: "telnet"}},
+		{ok[0], ok[0]},
+	}
+	for _, defs := range bad {
+		if err := ValidateBackends(defs); err == nil {
+			t.Fatalf("ValidateBackends(%+v): want error", defs)
+		}
+	}
+	if err := CheckBackend("corp", ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckBackend("mosh", nil); err != nil {
+		t.Fatal(err)
+	}
+	inv := Inventory{Groups: []Group{{Name: "g", Backend: "corp"}}}
+	if err := CheckBackendRefs(Config{}, inv); err == nil {
+		t.Fatal("undefined group backend: want error")
+	}
+	if err := CheckBackendRefs(Config{Backends: ok}, inv); err != nil {
+		t.Fatal(err)
+	}
+}
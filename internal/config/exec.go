@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Defaults of the exec_parallel and exec_timeout settings.
+const (
+	DefaultExecParallel = 10
+	DefaultExecTimeout  = "60s"
+)
+
+// ParseExecTimeout parses exec_timeout: a Go duration, "0" for no timeout,
+// or empty for DefaultExecTimeout.
+func ParseExecTimeout(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		s = DefaultExecTimeout
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("exec timeout %q is invalid: use a duration such as 30s or 5m", s)
+	}
+	return d, nil
+}
+
+// ValidateExecParallel checks exec_parallel, which must run at least one
+// host at a time.
+func ValidateExecParallel(n int) error {
+	if n < 1 {
+		return fmt.Errorf("exec parallel %d is invalid: use a number >= 1", n)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExecTimeout(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"":      60 * time.Second,
+		"0":     0,
+		" 90s ": 90 * time.Second,
+		"5m":    5 * time.Minute,
+	} {
+		got, err := ParseExecTimeout(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseExecTimeout(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"30", "-1s", "soon"} {
+		if _, err := ParseExecTimeout(in); err == nil {
+			t.Fatalf("ParseExecTimeout(%q): want error", in)
+		}
+	}
+}
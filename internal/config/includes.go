@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindGroup returns the index of the group named name, or -1.
+func FindGroup(inv Inventory, name string) int {
+	name = strings.TrimSpace(name)
+	for i := range inv.Groups {
+		if strings.TrimSpace(inv.Groups[i].Name) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckIncludes verifies that every include_groups entry names an existing
+// group and that no group includes itself, directly or indirectly.
+func CheckIncludes(inv Inventory) error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(inv.Groups))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("group include cycle: %s", strings.Join(append(path, inv.Groups[i].Name), " -> "))
+		case done:
+			return nil
+		}
+		state[i] = visiting
+		path = append(path, inv.Groups[i].Name)
+		for _, child := range inv.Groups[i].IncludeGroups {
+			j := FindGroup(inv, child)
+			if j < 0 {
+				return fmt.Errorf("group %q: include_groups: unknown group %q", inv.Groups[i].Name, child)
+			}
+			if err := visit(j, path); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		return nil
+	}
+
+	for i := range inv.Groups {
+		if err := visit(i, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameGroupRefs replaces include_groups references to oldName with
+// newName (or drops them when newName is empty).
+func RenameGroupRefs(groups []Group, oldName, newName string) []Group {
+	out := make([]Group, len(groups))
+	for i, g := range groups {
+		out[i] = g
+		if len(g.IncludeGroups) == 0 {
+			continue
+		}
+		refs := make([]string, 0, len(g.IncludeGroups))
+		for _, ref := range g.IncludeGroups {
+			if strings.TrimSpace(ref) == oldName {
+				if newName == "" {
+					continue
+				}
+				ref = newName
+			}
+			refs = append(refs, ref)
+		}
+		out[i].IncludeGroups = refs
+	}
+	return out
+}
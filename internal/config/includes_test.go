@@ -0,0 +1,56 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckIncludes(t *testing.T) {
+	ok := Inventory{Groups: []Group{
+		{Name: "prod", IncludeGroups: []string{"prod-web", "prod-db"}},
+		{Name: "prod-web"},
+		{Name: "prod-db", IncludeGroups: []string{"prod-web"}},
+	}}
+	if err := CheckIncludes(ok); err != nil {
+		t.Fatalf("CheckIncludes: %v", err)
+	}
+
+	cycle := Inventory{Groups: []Group{
+		{Name: "a", IncludeGroups: []string{"b"}},
+		{Name: "b", IncludeGroups: []string{"c"}},
+		{Name: "c", IncludeGroups: []string{"a"}},
+	}}
+	err := CheckIncludes(cycle)
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Fatalf("err=%v, want cycle a -> b -> c -> a", err)
+	}
+
+	self := Inventory{Groups: []Group{{Name: "a", IncludeGroups: []string{"a"}}}}
+	if err := CheckIncludes(self); err == nil {
+		t.Fatalf("expected self-include error")
+	}
+
+	unknown := Inventory{Groups: []Group{{Name: "a", IncludeGroups: []string{"nope"}}}}
+	if err := CheckIncludes(unknown); err == nil || !strings.Contains(err.Error(), `unknown group "nope"`) {
+		t.Fatalf("err=%v, want unknown group", err)
+	}
+}
+
+func TestRenameGroupRefs(t *testing.T) {
+	groups := []Group{
+		{Name: "prod", IncludeGroups: []string{"web", "db"}},
+		{Name: "web"},
+	}
+	got := RenameGroupRefs(groups, "web", "frontend")
+	if want := []string{"frontend", "db"}; !reflect.DeepEqual(got[0].IncludeGroups, want) {
+		t.Fatalf("got=%v want %v", got[0].IncludeGroups, want)
+	}
+	if groups[0].IncludeGroups[0] != "web" {
+		t.Fatalf("input modified")
+	}
+	got = RenameGroupRefs(groups, "db", "")
+	if want := []string{"web"}; !reflect.DeepEqual(got[0].IncludeGroups, want) {
+		t.Fatalf("got=%v want %v", got[0].IncludeGroups, want)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/al-bashkir/ssh-tui/internal/match"
 )
 
 func configDir() (string, error) {
@@ -24,6 +26,76 @@ func configDir() (string, error) {
 	return filepath.Join(home, ".config", "ssh-tui"), nil
 }
 
+func stateDir() (string, error) {
+	if v := os.Getenv("XDG_STATE_HOME"); v != "" {
+		return filepath.Join(v, "ssh-tui"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if home == "" {
+		return "", errors.New("home directory not found")
+	}
+	return filepath.Join(home, ".local", "state", "ssh-tui"), nil
+}
+
+func cacheDir() (string, error) {
+	if v := os.Getenv("XDG_CACHE_HOME"); v != "" {
+		return filepath.Join(v, "ssh-tui"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if home == "" {
+		return "", errors.New("home directory not found")
+	}
+	return filepath.Join(home, ".cache", "ssh-tui"), nil
+}
+
+// DefaultSourcesCacheDir returns the directory holding cached [[sources]]
+// output.
+func DefaultSourcesCacheDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sources"), nil
+}
+
+// DefaultTunnelStateDir returns the directory holding the pid files of
+// running tunnels.
+func DefaultTunnelStateDir() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tunnels"), nil
+}
+
+// DefaultCustomHostHistoryPath returns the path of the file that remembers
+// hosts typed into the custom host prompt.
+func DefaultCustomHostHistoryPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "custom_hosts"), nil
+}
+
+// DefaultCommandHistoryPath returns the path of the file that remembers
+// commands typed into the connect-with-command prompt.
+func DefaultCommandHistoryPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "commands"), nil
+}
+
 func DefaultPath() (string, error) {
 	dir, err := configDir()
 	if err != nil {
@@ -76,6 +148,33 @@ func Load(path string) (Config, string, error) {
 	if cfg.Version == 0 {
 		cfg.Version = 1
 	}
+	if err := ValidateJump(cfg.Defaults.Jump); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if err := ValidateExecParallel(cfg.Defaults.ExecParallel); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if _, err := ParseExecTimeout(cfg.Defaults.ExecTimeout); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if _, _, err := ParseProbe(cfg.Defaults); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if err := ValidateBackends(cfg.Backends); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: %w", err)
+	}
+	if err := ValidateReuseWindow(cfg.Defaults.ReuseWindow); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if err := ValidateMaxPanes(cfg.Defaults.MaxPanesPerWindow); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if err := ValidateMultiplexer(cfg.Defaults.Multiplexer); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
+	if err := CheckBackend(cfg.Defaults.Backend, cfg.Backends); err != nil {
+		return DefaultConfig(), path, fmt.Errorf("config: defaults: %w", err)
+	}
 	return cfg, path, nil
 }
 
@@ -163,6 +262,38 @@ func LoadInventory(path string) (Inventory, string, error) {
 		if err := ValidateGroupName(g.Name); err != nil {
 			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
 		}
+		if _, err := match.CompileAll(g.Match); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+		if err := ValidateJump(g.Jump); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+		if err := ValidatePreflight(g.Preflight); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+		if err := ValidateBackendName(g.Backend); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+		if err := ValidateReuseWindow(g.ReuseWindow); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+		if err := ValidateMaxPanes(g.MaxPanesPerWindow); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: group %q: %w", g.Name, err)
+		}
+	}
+	for _, h := range inv.Hosts {
+		if err := ValidateJump(h.Jump); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: host %q: %w", h.Host, err)
+		}
+		if err := ValidateBackendName(h.Backend); err != nil {
+			return DefaultInventory(), path, fmt.Errorf("hosts: host %q: %w", h.Host, err)
+		}
+	}
+	if err := CheckIncludes(inv); err != nil {
+		return DefaultInventory(), path, fmt.Errorf("hosts: %w", err)
+	}
+	if err := ValidateTunnels(inv); err != nil {
+		return DefaultInventory(), path, fmt.Errorf("hosts: %w", err)
 	}
 	return inv, path, nil
 }
@@ -83,6 +83,9 @@ func TestSaveInventoryThenLoadRoundTrip(t *testing.T) {
 		Port:         2222,
 		IdentityFile: "~/.ssh/db1_ed25519",
 		ExtraArgs:    []string{"-o", "ServerAliveInterval=30"},
+		Description:  "primary database",
+		Tags:         []string{"db", "prod"},
+		Meta:         map[string]string{"owner": "dba", "env": "prod"},
 	}}
 	inv.Groups = []Group{{
 		Name:         "prod",
@@ -92,6 +95,8 @@ func TestSaveInventoryThenLoadRoundTrip(t *testing.T) {
 		ExtraArgs:    []string{"-o", "ServerAliveInterval=30"},
 		OpenMode:     "tmux-window",
 		Hosts:        []string{"db1.example", "[10.0.0.1]:2222"},
+		Tags:         []string{"prod"},
+		Meta:         map[string]string{"team": "ops"},
 	}}
 
 	if _, err := SaveInventory(p, inv); err != nil {
@@ -260,3 +265,138 @@ func searchSubstring(s, sub string) bool {
 	}
 	return false
 }
+
+func TestLoadInventoryRejectsBadMatch(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[groups]]\nname = \"prod\"\nmatch = [\"tag:db &&\"]\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), `group "prod"`) {
+		t.Fatalf("err=%v, want group match error", err)
+	}
+}
+
+func TestLoadInventoryRejectsIncludeCycle(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[groups]]\nname = \"a\"\ninclude_groups = [\"b\"]\n[[groups]]\nname = \"b\"\ninclude_groups = [\"a\"]\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), "a -> b -> a") {
+		t.Fatalf("err=%v, want include cycle error", err)
+	}
+}
+
+func TestLoadInventoryRejectsBadJump(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[hosts]]\nhost = \"db1\"\njump = [\"gw:0\"]\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), `host "db1"`) {
+		t.Fatalf("err=%v, want host jump error", err)
+	}
+}
+
+func TestLoadInventoryKeepsLiteralTemplates(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[groups]]\nname = \"prod\"\nremote_command = \"docker ps --format '{{.Names}}'\"\nwindow_name = \"{{.Group\"\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	inv, _, err := LoadInventory(p)
+	if err != nil {
+		t.Fatalf("LoadInventory: %v", err)
+	}
+	if got := inv.Groups[0].RemoteCommand; got != "docker ps --format '{{.Names}}'" {
+		t.Fatalf("remote_command = %q", got)
+	}
+}
+
+func TestLoadInventoryRejectsBadReuseWindow(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[groups]]\nname = \"prod\"\nreuse_window = \"always\"\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), "reuse_window") {
+		t.Fatalf("err=%v, want reuse_window error", err)
+	}
+}
+
+func TestLoadInventoryRejectsNegativeMaxPanes(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := "version = 1\n[[groups]]\nname = \"prod\"\nmax_panes_per_window = -1\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), "max_panes_per_window") {
+		t.Fatalf("err=%v, want max_panes_per_window error", err)
+	}
+}
+
+func TestLoadRejectsBadMultiplexer(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "config.toml")
+	data := "version = 1\n[defaults]\nmultiplexer = \"byobu\"\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := Load(p); err == nil || !contains(err.Error(), "multiplexer") {
+		t.Fatalf("err=%v, want multiplexer error", err)
+	}
+}
+
+func TestLoadRejectsBadExecParallel(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "config.toml")
+	data := "version = 1\n[defaults]\nexec_parallel = 0\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := Load(p); err == nil || !contains(err.Error(), "config: defaults: exec parallel 0") {
+		t.Fatalf("err=%v, want exec parallel error", err)
+	}
+}
+
+func TestLoadInventoryTunnels(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "hosts.toml")
+	data := `version = 1
+[[hosts]]
+host = "bastion"
+[[hosts.tunnels]]
+name = "pg"
+type = "local"
+listen = "127.0.0.1:5432"
+target = "db.internal:5432"
+
+[[groups]]
+name = "prod"
+hosts = ["web1"]
+[[groups.tunnels]]
+name = "socks"
+type = "dynamic"
+listen = "1080"
+`
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	inv, _, err := LoadInventory(p)
+	if err != nil {
+		t.Fatalf("LoadInventory: %v", err)
+	}
+	want := []Tunnel{{Name: "pg", Type: TunnelLocal, Listen: "127.0.0.1:5432", Target: "db.internal:5432"}}
+	if !reflect.DeepEqual(inv.Hosts[0].Tunnels, want) {
+		t.Fatalf("got=%#v\nwant=%#v", inv.Hosts[0].Tunnels, want)
+	}
+	if len(inv.Groups[0].Tunnels) != 1 || inv.Groups[0].Tunnels[0].Type != TunnelDynamic {
+		t.Fatalf("group tunnels=%#v", inv.Groups[0].Tunnels)
+	}
+
+	data += "[[groups.tunnels]]\nname = \"pg\"\ntype = \"dynamic\"\nlisten = \"1081\"\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := LoadInventory(p); err == nil || !contains(err.Error(), "duplicate") {
+		t.Fatalf("err=%v, want duplicate tunnel error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JumpNone as the only hop disables a jump list inherited from defaults or
+// a group.
+const JumpNone = "none"
+
+// ParseJump splits a comma and/or whitespace separated hop list and
+// validates it.
+func ParseJump(s string) ([]string, error) {
+	hops := NormalizeTags(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}))
+	return hops, ValidateJump(hops)
+}
+
+// ValidateJump checks that every hop is an inventory host name or
+// [user@]host[:port], where host may be bracketed ("[10.0.0.1]:2222").
+func ValidateJump(hops []string) error {
+	for _, hop := range hops {
+		hop = strings.TrimSpace(hop)
+		if hop == JumpNone {
+			if len(hops) != 1 {
+				return fmt.Errorf("jump: %q must be the only hop", JumpNone)
+			}
+			continue
+		}
+		if err := validateHop(hop); err != nil {
+			return fmt.Errorf("jump: hop %q: %w", hop, err)
+		}
+	}
+	return nil
+}
+
+func validateHop(hop string) error {
+	if hop == "" {
+		return fmt.Errorf("empty")
+	}
+	if strings.ContainsAny(hop, " \t,") {
+		return fmt.Errorf("must not contain spaces or commas")
+	}
+	rest := hop
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		if i == 0 {
+			return fmt.Errorf("empty user")
+		}
+		rest = rest[i+1:]
+	}
+	port := ""
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return fmt.Errorf("missing ]")
+		}
+		if end == 1 {
+			return fmt.Errorf("empty host")
+		}
+		switch after := rest[end+1:]; {
+		case after == "":
+		case strings.HasPrefix(after, ":"):
+			port = after[1:]
+		default:
+			return fmt.Errorf("unexpected %q after ]", after)
+		}
+	} else {
+		if i := strings.LastIndex(rest, ":"); i >= 0 {
+			rest, port = rest[:i], rest[i+1:]
+			if strings.Contains(rest, ":") {
+				return fmt.Errorf("IPv6 addresses must be bracketed")
+			}
+		}
+		if rest == "" {
+			return fmt.Errorf("empty host")
+		}
+	}
+	if port != "" {
+		if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+			return fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return nil
+}
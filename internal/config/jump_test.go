@@ -0,0 +1,39 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJump(t *testing.T) {
+	got, err := ParseJump("bastion, admin@gw.example.com:2222 [10.0.0.1]:22 ops@[fe80::1]:2200")
+	if err != nil {
+		t.Fatalf("ParseJump: %v", err)
+	}
+	want := []string{"bastion", "admin@gw.example.com:2222", "[10.0.0.1]:22", "ops@[fe80::1]:2200"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if got, err := ParseJump(""); err != nil || got != nil {
+		t.Fatalf("empty: got=%#v err=%v", got, err)
+	}
+}
+
+func TestValidateJumpRejects(t *testing.T) {
+	for _, hops := range [][]string{
+		{"@gw"},
+		{"gw:0"},
+		{"gw:ssh"},
+		{"[gw"},
+		{"[gw]x"},
+		{"fe80::1"},
+		{"none", "gw"},
+	} {
+		if err := ValidateJump(hops); err == nil {
+			t.Fatalf("ValidateJump(%q): want error", hops)
+		}
+	}
+	if err := ValidateJump([]string{"none"}); err != nil {
+		t.Fatalf("none: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeStrategy decides what happens to groups and host overrides that
+// already exist when merging an imported inventory.
+type MergeStrategy string
+
+const (
+	MergeUnion   MergeStrategy = "union"   // add missing hosts, fill empty settings
+	MergeReplace MergeStrategy = "replace" // imported hosts and connection settings win
+	MergeSkip    MergeStrategy = "skip"    // existing entries are left untouched
+)
+
+// ParseMergeStrategy validates a strategy name; empty means union.
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch MergeStrategy(strings.TrimSpace(s)) {
+	case "", MergeUnion:
+		return MergeUnion, nil
+	case MergeReplace:
+		return MergeReplace, nil
+	case MergeSkip:
+		return MergeSkip, nil
+	}
+	return "", fmt.Errorf("invalid merge strategy %q: use union|replace|skip", s)
+}
+
+// Change describes one entry of a merge result.
+type Change struct {
+	Op     string // "+" added, "~" changed, "=" unchanged
+	Kind   string // "group" or "host"
+	Name   string
+	Detail string
+}
+
+func (c Change) String() string {
+	s := c.Op + " " + c.Kind + " " + c.Name
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// MergeInventory merges the groups and host overrides of in into cur and
+// returns the result plus one Change per imported entry. cur is not modified.
+func MergeInventory(cur, in Inventory, strategy MergeStrategy) (Inventory, []Change) {
+	out := cur
+	out.Groups = append([]Group(nil), cur.Groups...)
+	out.Hosts = append([]Host(nil), cur.Hosts...)
+	var changes []Change
+
+	groupIdx := make(map[string]int, len(out.Groups))
+	for i, g := range out.Groups {
+		groupIdx[g.Name] = i
+	}
+	for _, g := range in.Groups {
+		i, ok := groupIdx[g.Name]
+		if !ok {
+			groupIdx[g.Name] = len(out.Groups)
+			out.Groups = append(out.Groups, g)
+			changes = append(changes, Change{Op: "+", Kind: "group", Name: g.Name, Detail: groupSummary(g)})
+			continue
+		}
+		old := out.Groups[i]
+		var merged Group
+		switch strategy {
+		case MergeSkip:
+			merged = old
+		case MergeReplace:
+			merged = old
+			merged.User, merged.Port, merged.IdentityFile = g.User, g.Port, g.IdentityFile
+			merged.Hosts = g.Hosts
+		default:
+			merged = unionGroup(old, g)
+		}
+		out.Groups[i] = merged
+		changes = append(changes, diffChange("group", g.Name, groupDiff(old, merged)))
+	}
+
+	hostIdx := make(map[string]int, len(out.Hosts))
+	for i, h := range out.Hosts {
+		hostIdx[h.Host] = i
+	}
+	for _, h := range in.Hosts {
+		i, ok := hostIdx[h.Host]
+		if !ok {
+			hostIdx[h.Host] = len(out.Hosts)
+			out.Hosts = append(out.Hosts, h)
+			changes = append(changes, Change{Op: "+", Kind: "host", Name: h.Host, Detail: strings.Join(hostDiff(Host{}, h), ", ")})
+			continue
+		}
+		old := out.Hosts[i]
+		var merged Host
+		switch strategy {
+		case MergeSkip:
+			merged = old
+		case MergeReplace:
+			// Only the connection fields come from the import; notes,
+			// tags, meta and the other settings are kept.
+			merged = old
+			merged.User, merged.Port, merged.IdentityFile = h.User, h.Port, h.IdentityFile
+			merged.ExtraArgs = h.ExtraArgs
+		default:
+			merged = unionHost(old, h)
+		}
+		out.Hosts[i] = merged
+		changes = append(changes, diffChange("host", h.Host, hostDiff(old, merged)))
+	}
+	return out, changes
+}
+
+func diffChange(kind, name string, diff []string) Change {
+	if len(diff) == 0 {
+		return Change{Op: "=", Kind: kind, Name: name}
+	}
+	return Change{Op: "~", Kind: kind, Name: name, Detail: strings.Join(diff, ", ")}
+}
+
+func unionGroup(old, in Group) Group {
+	g := old
+	g.User = firstNonEmpty(g.User, in.User)
+	if g.Port == 0 {
+		g.Port = in.Port
+	}
+	g.IdentityFile = firstNonEmpty(g.IdentityFile, in.IdentityFile)
+	if len(g.ExtraArgs) == 0 {
+		g.ExtraArgs = in.ExtraArgs
+	}
+	if len(g.Jump) == 0 {
+		g.Jump = in.Jump
+	}
+	g.Backend = firstNonEmpty(g.Backend, in.Backend)
+	g.Hosts = append([]string(nil), old.Hosts...)
+	have := make(map[string]bool, len(g.Hosts))
+	for _, h := range g.Hosts {
+		have[h] = true
+	}
+	for _, h := range in.Hosts {
+		if !have[h] {
+			have[h] = true
+			g.Hosts = append(g.Hosts, h)
+		}
+	}
+	return g
+}
+
+func unionHost(old, in Host) Host {
+	h := old
+	h.User = firstNonEmpty(h.User, in.User)
+	if h.Port == 0 {
+		h.Port = in.Port
+	}
+	h.IdentityFile = firstNonEmpty(h.IdentityFile, in.IdentityFile)
+	if len(h.ExtraArgs) == 0 {
+		h.ExtraArgs = in.ExtraArgs
+	}
+	if len(h.Jump) == 0 {
+		h.Jump = in.Jump
+	}
+	h.Backend = firstNonEmpty(h.Backend, in.Backend)
+	return h
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+func groupSummary(g Group) string {
+	parts := []string{fmt.Sprintf("%d hosts", len(g.Hosts))}
+	if g.User != "" {
+		parts = append(parts, "user="+g.User)
+	}
+	if g.Port != 0 {
+		parts = append(parts, fmt.Sprintf("port=%d", g.Port))
+	}
+	if g.IdentityFile != "" {
+		parts = append(parts, "identity_file="+g.IdentityFile)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func groupDiff(old, g Group) []string {
+	var out []string
+	out = appendFieldDiff(out, "user", old.User, g.User)
+	out = appendFieldDiff(out, "port", portString(old.Port), portString(g.Port))
+	out = appendFieldDiff(out, "identity_file", old.IdentityFile, g.IdentityFile)
+	out = appendFieldDiff(out, "extra_args", strings.Join(old.ExtraArgs, " "), strings.Join(g.ExtraArgs, " "))
+	out = appendFieldDiff(out, "jump", strings.Join(old.Jump, ","), strings.Join(g.Jump, ","))
+	out = appendFieldDiff(out, "backend", old.Backend, g.Backend)
+	out = appendFieldDiff(out, "remote_command", old.RemoteCommand, g.RemoteCommand)
+
+	oldSet := make(map[string]bool, len(old.Hosts))
+	for _, h := range old.Hosts {
+		oldSet[h] = true
+	}
+	newSet := make(map[string]bool, len(g.Hosts))
+	for _, h := range g.Hosts {
+		newSet[h] = true
+		if !oldSet[h] {
+			out = append(out, "+"+h)
+		}
+	}
+	for _, h := range old.Hosts {
+		if !newSet[h] {
+			out = append(out, "-"+h)
+		}
+	}
+	return out
+}
+
+func hostDiff(old, h Host) []string {
+	var out []string
+	out = appendFieldDiff(out, "user", old.User, h.User)
+	out = appendFieldDiff(out, "port", portString(old.Port), portString(h.Port))
+	out = appendFieldDiff(out, "identity_file", old.IdentityFile, h.IdentityFile)
+	out = appendFieldDiff(out, "extra_args", strings.Join(old.ExtraArgs, " "), strings.Join(h.ExtraArgs, " "))
+	out = appendFieldDiff(out, "jump", strings.Join(old.Jump, ","), strings.Join(h.Jump, ","))
+	out = appendFieldDiff(out, "backend", old.Backend, h.Backend)
+	out = appendFieldDiff(out, "description", old.Description, h.Description)
+	out = appendFieldDiff(out, "tags", strings.Join(old.Tags, ","), strings.Join(h.Tags, ","))
+	out = appendFieldDiff(out, "meta", metaString(old.Meta), metaString(h.Meta))
+	out = appendFieldDiff(out, "tunnels", tunnelNames(old.Tunnels), tunnelNames(h.Tunnels))
+	return out
+}
+
+func appendFieldDiff(out []string, field, old, cur string) []string {
+	switch {
+	case old == cur:
+		return out
+	case old == "":
+		return append(out, fmt.Sprintf("%s=%s", field, cur))
+	case cur == "":
+		return append(out, fmt.Sprintf("%s unset (was %s)", field, old))
+	}
+	return append(out, fmt.Sprintf("%s %s -> %s", field, old, cur))
+}
+
+// tunnelNames lists the names of tunnels.
+func tunnelNames(tunnels []Tunnel) string {
+	names := make([]string, len(tunnels))
+	for i, t := range tunnels {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// metaString renders meta as sorted key=value pairs.
+func metaString(meta map[string]string) string {
+	pairs := make([]string, 0, len(meta))
+	for k, v := range meta {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func portString(p int) string {
+	if p == 0 {
+		return ""
+	}
+	return fmt.Sprint(p)
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeInventoryStrategies(t *testing.T) {
+	cur := Inventory{
+		Version: 1,
+		Hosts:   []Host{{Host: "h1", User: "me", Hidden: true}},
+		Groups: []Group{
+			{Name: "web", User: "admin", PaneLayout: "tiled", Hosts: []string{"h1", "h2"}},
+		},
+	}
+	in := Inventory{
+		Hosts: []Host{{Host: "h1", User: "deploy", Port: 2222}, {Host: "h3", Port: 2200}},
+		Groups: []Group{
+			{Name: "web", User: "ops", Port: 2222, Hosts: []string{"h2", "h3"}},
+			{Name: "db", Hosts: []string{"d1"}},
+		},
+	}
+
+	tests := []struct {
+		strategy MergeStrategy
+		web      Group
+		h1       Host
+		ops      []string
+	}{
+		{
+			MergeUnion,
+			Group{Name: "web", User: "admin", Port: 2222, PaneLayout: "tiled", Hosts: []string{"h1", "h2", "h3"}},
+			Host{Host: "h1", User: "me", Port: 2222, Hidden: true},
+			[]string{"~", "+", "~", "+"},
+		},
+		{
+			MergeReplace,
+			Group{Name: "web", User: "ops", Port: 2222, PaneLayout: "tiled", Hosts: []string{"h2", "h3"}},
+			Host{Host: "h1", User: "deploy", Port: 2222, Hidden: true},
+			[]string{"~", "+", "~", "+"},
+		},
+		{
+			MergeSkip,
+			cur.Groups[0],
+			cur.Hosts[0],
+			[]string{"=", "+", "=", "+"},
+		},
+	}
+	for _, tt := range tests {
+		got, changes := MergeInventory(cur, in, tt.strategy)
+		if !reflect.DeepEqual(got.Groups[0], tt.web) {
+			t.Fatalf("%s: web got=%#v\nwant=%#v", tt.strategy, got.Groups[0], tt.web)
+		}
+		if !reflect.DeepEqual(got.Hosts[0], tt.h1) {
+			t.Fatalf("%s: h1 got=%#v\nwant=%#v", tt.strategy, got.Hosts[0], tt.h1)
+		}
+		if len(got.Groups) != 2 || got.Groups[1].Name != "db" || len(got.Hosts) != 2 || got.Hosts[1].Host != "h3" {
+			t.Fatalf("%s: added entries missing: %#v", tt.strategy, got)
+		}
+		var ops []string
+		for _, c := range changes {
+			ops = append(ops, c.Op)
+		}
+		if !reflect.DeepEqual(ops, tt.ops) {
+			t.Fatalf("%s: ops=%v want %v (%v)", tt.strategy, ops, tt.ops, changes)
+		}
+	}
+	if cur.Groups[0].User != "admin" || len(cur.Groups[0].Hosts) != 2 {
+		t.Fatalf("input inventory modified: %#v", cur.Groups[0])
+	}
+}
+
+func TestMergeReplaceKeepsHostNotes(t *testing.T) {
+	old := Host{
+		Host: "h1", User: "me", Port: 2200, ExtraArgs: []string{"-A"},
+		Jump: []string{"gw"}, Backend: "mosh", Description: "primary db",
+		Tags: []string{"db"}, Meta: map[string]string{"dc": "fra"},
+		Tunnels: []Tunnel{{Name: "pg", Type: TunnelLocal, Listen: "5432", Target: "localhost:5432"}},
+	}
+	got, changes := MergeInventory(
+		Inventory{Hosts: []Host{old}},
+		Inventory{Hosts: []Host{{Host: "h1", User: "deploy", IdentityFile: "~/.ssh/k"}}},
+		MergeReplace,
+	)
+	want := old
+	want.User, want.Port, want.IdentityFile, want.ExtraArgs = "deploy", 0, "~/.ssh/k", nil
+	if !reflect.DeepEqual(got.Hosts[0], want) {
+		t.Fatalf("got=%#v\nwant=%#v", got.Hosts[0], want)
+	}
+	if got, want := changes[0].String(), "~ host h1: user me -> deploy, port unset (was 2200), identity_file=~/.ssh/k, extra_args unset (was -A)"; got != want {
+		t.Fatalf("change=%q want=%q", got, want)
+	}
+}
+
+func TestHostDiffReportsTunnels(t *testing.T) {
+	old := Host{Host: "h1", Tunnels: []Tunnel{{Name: "pg"}, {Name: "redis"}}}
+	cur := Host{Host: "h1", Tunnels: []Tunnel{{Name: "pg"}}}
+	if got, want := hostDiff(old, cur), []string{"tunnels pg,redis -> pg"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%q want=%q", got, want)
+	}
+	if got := hostDiff(old, Host{Host: "h1"}); !reflect.DeepEqual(got, []string{"tunnels unset (was pg,redis)"}) {
+		t.Fatalf("removed: %q", got)
+	}
+}
+
+func TestMergeChangeString(t *testing.T) {
+	_, changes := MergeInventory(
+		Inventory{Groups: []Group{{Name: "web", Hosts: []string{"a", "b"}}}},
+		Inventory{Groups: []Group{{Name: "web", User: "ops", Hosts: []string{"c"}}}},
+		MergeReplace,
+	)
+	if got, want := changes[0].String(), "~ group web: user=ops, +c, -a, -b"; got != want {
+		t.Fatalf("got=%q want=%q", got, want)
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	if s, err := ParseMergeStrategy(""); err != nil || s != MergeUnion {
+		t.Fatalf("empty: %v %v", s, err)
+	}
+	if _, err := ParseMergeStrategy("overwrite"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
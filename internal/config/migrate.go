@@ -37,8 +37,9 @@ func Migrate(configPath, hostsPath string) error {
 		return nil
 	}
 
-	// Read the old config.toml into the legacy struct that has all fields.
-	var legacy legacyConfig
+	// Read the old config.toml into the legacy struct that has all fields,
+	// keeping the defaults of settings it does not set.
+	legacy := legacyConfig{Defaults: DefaultConfig().Defaults}
 	if _, err := toml.DecodeFile(configPath, &legacy); err != nil {
 		return err
 	}
@@ -22,8 +22,26 @@ func ValidateGroupName(name string) error {
 }
 
 type Config struct {
-	Version  int      `toml:"version"`
-	Defaults Defaults `toml:"defaults"`
+	Version  int       `toml:"version"`
+	Defaults Defaults  `toml:"defaults"`
+	Sources  []Source  `toml:"sources,omitempty"`
+	Backends []Backend `toml:"backends,omitempty"`
+}
+
+// Source is a dynamic inventory command. It prints a JSON document of hosts
+// on stdout; results are cached under the XDG cache dir for TTL.
+// Example TOML:
+//
+//	[[sources]]
+//	name = "cmdb"
+//	command = ["cmdb-export", "--format", "json"]
+//	ttl = "10m"
+//	timeout = "30s"
+type Source struct {
+	Name    string   `toml:"name"`    // letters, digits, - and _ (used as cache file name)
+	Command []string `toml:"command"` // argv, run without a shell
+	TTL     string   `toml:"ttl"`     // Go duration; default 5m, "0" disables the cache
+	Timeout string   `toml:"timeout"` // Go duration; default 30s
 }
 
 // Inventory holds host and group data (hosts.toml).
@@ -50,16 +68,46 @@ type Host struct {
 	Port         int      `toml:"port"`
 	IdentityFile string   `toml:"identity_file"`
 	ExtraArgs    []string `toml:"extra_args"`
+	Jump         []string `toml:"jump,omitempty"`    // ProxyJump hops: inventory host or [user@]host[:port]
+	Backend      string   `toml:"backend,omitempty"` // ssh|mosh|et|tsh or a [[backends]] name
 	Hidden       bool     `toml:"hidden,omitempty"`
+
+	Description string            `toml:"description,omitempty"`
+	Tags        []string          `toml:"tags,omitempty"`
+	Meta        map[string]string `toml:"meta,omitempty"` // free-form key/value pairs ([hosts.meta])
+
+	Tunnels []Tunnel `toml:"tunnels,omitempty"` // named port forwards through this host ([[hosts.tunnels]])
+}
+
+// Tunnel is a named port forward, run as a background `ssh -N`.
+// Example TOML:
+//
+//	[[hosts.tunnels]]
+//	name = "pg"
+//	type = "local"
+//	listen = "5432"
+//	target = "db.internal:5432"
+type Tunnel struct {
+	Name   string `toml:"name"`             // letters, digits, - and _; unique in hosts.toml
+	Type   string `toml:"type"`             // local|remote|dynamic
+	Listen string `toml:"listen"`           // [bind_address:]port; on the remote side for remote tunnels
+	Target string `toml:"target,omitempty"` // host:port to forward to; not used by dynamic (SOCKS)
+	Host   string `toml:"host,omitempty"`   // group tunnels only: host to connect through (default: first group member)
 }
 
 type Defaults struct {
 	AccentColor             string   `toml:"accent_color"` // default UI accent color (preset name or color code)
 	LoadKnownHosts          bool     `toml:"load_known_hosts"`
+	LoadSSHConfig           bool     `toml:"load_ssh_config"`      // merge ~/.ssh/config Host aliases into the list
+	LoadDocker              bool     `toml:"load_docker"`          // list running containers (docker ps) as docker:NAME
+	LoadKubernetes          bool     `toml:"load_kubernetes"`      // list running pods (kubectl get pods) as k8s:NS/POD
+	KubernetesNamespace     string   `toml:"kubernetes_namespace"` // namespace to list pods from; empty = all namespaces
 	User                    string   `toml:"user"`
 	Port                    int      `toml:"port"`
 	IdentityFile            string   `toml:"identity_file"`
 	ExtraArgs               []string `toml:"extra_args"`
+	Jump                    []string `toml:"jump,omitempty"`      // ProxyJump hops for every host
+	Backend                 string   `toml:"backend,omitempty"`   // ssh|mosh|et|tsh or a [[backends]] name (default ssh)
 	PaneSplit               string   `toml:"pane_split"`          // horizontal|vertical
 	PaneLayout              string   `toml:"pane_layout"`         // auto|tiled|even-horizontal|even-vertical|main-horizontal|main-vertical
 	PaneSync                string   `toml:"pane_sync"`           // on|off
@@ -71,6 +119,20 @@ type Defaults struct {
 	TmuxSession             string   `toml:"tmux_session"`        // session name
 	ConfirmQuit             bool     `toml:"confirm_quit"`
 	ConnectConfirmThreshold int      `toml:"connect_confirm_threshold"`
+	ExecParallel            int      `toml:"exec_parallel"`  // hosts running `exec` at once
+	ExecTimeout             string   `toml:"exec_timeout"`   // per-host `exec` timeout (Go duration, "0" = none)
+	Probe                   bool     `toml:"probe"`          // TCP-probe the ssh port of the hosts shown in lists
+	ProbeTimeout            string   `toml:"probe_timeout"`  // per-dial timeout (Go duration)
+	ProbeInterval           string   `toml:"probe_interval"` // how long a probe result is reused (Go duration)
+
+	// Templates over tmpl.Vars; empty keeps the group name (or host) and the host.
+	WindowName string `toml:"window_name,omitempty"` // tmux window name
+	PaneTitle  string `toml:"pane_title,omitempty"`  // tmux pane title
+
+	ReuseWindow       string `toml:"reuse_window,omitempty"`         // new|focus|ask: what connecting to a host already open does (default new)
+	MaxPanesPerWindow int    `toml:"max_panes_per_window,omitempty"` // one-window connects: at most N panes per window, the rest in more windows (0 = no cap)
+	Multiplexer       string `toml:"multiplexer,omitempty"`          // auto|tmux|zellij|screen: where multi-host connects open (default auto)
+	RemainOnExit      bool   `toml:"remain_on_exit,omitempty"`       // keep tmux panes after ssh exits (remain-on-exit), to respawn them from the Sessions tab
 }
 
 type Group struct {
@@ -79,15 +141,31 @@ type Group struct {
 	Port          int      `toml:"port"`
 	IdentityFile  string   `toml:"identity_file"`
 	ExtraArgs     []string `toml:"extra_args"`
+	Jump          []string `toml:"jump,omitempty"`    // ProxyJump hops; ["none"] disables an inherited list
+	Backend       string   `toml:"backend,omitempty"` // ssh|mosh|et|tsh or a [[backends]] name
 	RemoteCommand string   `toml:"remote_command"`
 	PaneSplit     string   `toml:"pane_split"`
 	PaneLayout    string   `toml:"pane_layout"`
 	PaneSync      string   `toml:"pane_sync"`
 	PaneBorderFmt string   `toml:"pane_border_format"`
 	PaneBorderPos string   `toml:"pane_border_status"`
-	Tmux          string   `toml:"tmux"`      // optional override
-	OpenMode      string   `toml:"open_mode"` // optional override
+	Tmux          string   `toml:"tmux"`                   // optional override
+	OpenMode      string   `toml:"open_mode"`              // optional override
+	WindowName    string   `toml:"window_name,omitempty"`  // tmux window name template
+	PaneTitle     string   `toml:"pane_title,omitempty"`   // tmux pane title template
+	ReuseWindow   string   `toml:"reuse_window,omitempty"` // optional override
 	Hosts         []string `toml:"hosts"`
+	Match         []string `toml:"match,omitempty"`          // globs, /regex/ and tag expressions evaluated against the host list
+	IncludeGroups []string `toml:"include_groups,omitempty"` // member groups; their hosts are flattened into this one
+	Preflight     string   `toml:"preflight,omitempty"`      // "tcp": probe the members before connecting to all of them
+
+	MaxPanesPerWindow int `toml:"max_panes_per_window,omitempty"` // optional override; 0 inherits
+
+	Description string            `toml:"description,omitempty"`
+	Tags        []string          `toml:"tags,omitempty"`
+	Meta        map[string]string `toml:"meta,omitempty"` // free-form key/value pairs ([groups.meta])
+
+	Tunnels []Tunnel `toml:"tunnels,omitempty"` // named port forwards ([[groups.tunnels]])
 }
 
 func DefaultConfig() Config {
@@ -96,6 +174,7 @@ func DefaultConfig() Config {
 		Defaults: Defaults{
 			AccentColor:             "",
 			LoadKnownHosts:          true,
+			LoadSSHConfig:           false,
 			User:                    "",
 			Port:                    22,
 			IdentityFile:            "",
@@ -111,6 +190,10 @@ func DefaultConfig() Config {
 			TmuxSession:             "ssh-tui",
 			ConfirmQuit:             false,
 			ConnectConfirmThreshold: 5,
+			ExecParallel:            DefaultExecParallel,
+			ExecTimeout:             DefaultExecTimeout,
+			ProbeTimeout:            DefaultProbeTimeout,
+			ProbeInterval:           DefaultProbeInterval,
 		},
 	}
 }
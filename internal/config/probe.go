@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Defaults of the probe_timeout and probe_interval settings.
+const (
+	DefaultProbeTimeout  = "2s"
+	DefaultProbeInterval = "1m"
+)
+
+// PreflightTCP is the group preflight mode that TCP-dials every member
+// before connecting to the group.
+const PreflightTCP = "tcp"
+
+// ValidatePreflight checks a group preflight value: empty or "tcp".
+func ValidatePreflight(s string) error {
+	switch strings.TrimSpace(s) {
+	case "", PreflightTCP:
+		return nil
+	default:
+		return fmt.Errorf("preflight %q is invalid: use \"tcp\" or leave it empty", s)
+	}
+}
+
+// ParseProbe parses probe_timeout and probe_interval; empty values mean the
+// defaults. Both must be positive durations.
+func ParseProbe(d Defaults) (timeout, interval time.Duration, err error) {
+	parse := func(name, s, def string) (time.Duration, error) {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			s = def
+		}
+		v, err := time.ParseDuration(s)
+		if err != nil || v <= 0 {
+			return 0, fmt.Errorf("%s %q is invalid: use a duration such as 2s or 1m", name, s)
+		}
+		return v, nil
+	}
+	if timeout, err = parse("probe_timeout", d.ProbeTimeout, DefaultProbeTimeout); err != nil {
+		return 0, 0, err
+	}
+	if interval, err = parse("probe_interval", d.ProbeInterval, DefaultProbeInterval); err != nil {
+		return 0, 0, err
+	}
+	return timeout, interval, nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseProbe(t *testing.T) {
+	timeout, interval, err := ParseProbe(Defaults{})
+	if err != nil || timeout != 2*time.Second || interval != time.Minute {
+		t.Fatalf("defaults: %v %v %v", timeout, interval, err)
+	}
+	timeout, interval, err = ParseProbe(Defaults{ProbeTimeout: "500ms", ProbeInterval: " 5m "})
+	if err != nil || timeout != 500*time.Millisecond || interval != 5*time.Minute {
+		t.Fatalf("set: %v %v %v", timeout, interval, err)
+	}
+	if err := ValidatePreflight("tcp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidatePreflight("icmp"); err == nil {
+		t.Fatal("ValidatePreflight(icmp): want error")
+	}
+	for _, d := range []Defaults{{ProbeTimeout: "0"}, {ProbeInterval: "-1s"}, {ProbeTimeout: "2"}} {
+		if _, _, err := ParseProbe(d); err == nil {
+			t.Fatalf("ParseProbe(%+v): want error", d)
+		}
+	}
+}
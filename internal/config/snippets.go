@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Snippet is a named remote command of snippets.toml, offered by the
+// connect-with-command prompt and `connect group --snippet`.
+// Example TOML:
+//
+//	[[snippets]]
+//	name = "tail-logs"
+//	command = "tail -F /var/log/app/*.log"
+//	groups = ["prod"]
+type Snippet struct {
+	Name        string   `toml:"name"`                  // letters, digits, - and _
+	Command     string   `toml:"command"`               // run as the remote command; the session stays open
+	Description string   `toml:"description,omitempty"` // optional; shown in the picker
+	Groups      []string `toml:"groups,omitempty"`      // offered for connects through these groups
+	Tags        []string `toml:"tags,omitempty"`        // offered for hosts or groups with one of these tags
+}
+
+// Snippets is the content of snippets.toml.
+type Snippets struct {
+	Version  int       `toml:"version"`
+	Snippets []Snippet `toml:"snippets"`
+}
+
+// Global reports whether s is offered everywhere: it has no groups and no
+// tags.
+func (s Snippet) Global() bool {
+	return len(s.Groups) == 0 && len(NormalizeTags(s.Tags)) == 0
+}
+
+// Applies reports whether s is offered for a connect through group (empty
+// for none) to hosts carrying tags: global snippets always are, scoped ones
+// when group is one of Groups or one of tags is one of Tags.
+func (s Snippet) Applies(group string, tags []string) bool {
+	if s.Global() {
+		return true
+	}
+	if group != "" && slices.Contains(s.Groups, group) {
+		return true
+	}
+	for _, t := range NormalizeTags(s.Tags) {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSnippet returns the snippet named name.
+func FindSnippet(snippets []Snippet, name string) (Snippet, bool) {
+	name = strings.TrimSpace(name)
+	for _, s := range snippets {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Snippet{}, false
+}
+
+// ValidateSnippets checks names (unique, same syntax as group names) and
+// that every snippet has a command.
+func ValidateSnippets(snippets []Snippet) error {
+	seen := make(map[string]bool, len(snippets))
+	for _, s := range snippets {
+		if !validGroupName.MatchString(s.Name) {
+			return fmt.Errorf("snippet %q: name is invalid: only letters, digits, - and _ are allowed", s.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("snippet %q: duplicate name", s.Name)
+		}
+		seen[s.Name] = true
+		if strings.TrimSpace(s.Command) == "" {
+			return fmt.Errorf("snippet %q: command required", s.Name)
+		}
+	}
+	return nil
+}
+
+// SnippetsPathFromConfigPath returns the snippets.toml path next to a
+// config.toml path.
+func SnippetsPathFromConfigPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "snippets.toml")
+}
+
+// LoadSnippets reads snippets.toml. A missing file is not an error.
+func LoadSnippets(path string) (Snippets, error) {
+	path = filepath.Clean(path)
+	st, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Snippets{Version: 1}, nil
+		}
+		return Snippets{}, err
+	}
+	if st.IsDir() {
+		return Snippets{}, fmt.Errorf("snippets path is a directory: %s", path)
+	}
+	var out Snippets
+	if _, err := toml.DecodeFile(path, &out); err != nil {
+		return Snippets{}, err
+	}
+	if out.Version == 0 {
+		out.Version = 1
+	}
+	if err := ValidateSnippets(out.Snippets); err != nil {
+		return Snippets{}, fmt.Errorf("snippets: %w", err)
+	}
+	return out, nil
+}
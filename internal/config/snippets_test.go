@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSnippets(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "snippets.toml")
+	got, err := LoadSnippets(p)
+	if err != nil || len(got.Snippets) != 0 {
+		t.Fatalf("missing file: got=%#v err=%v", got, err)
+	}
+
+	data := "version = 1\n[[snippets]]\nname = \"tail-logs\"\ncommand = \"tail -F /var/log/app.log\"\ngroups = [\"prod\"]\n" +
+		"[[snippets]]\nname = \"uptime\"\ncommand = \"uptime\"\n"
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err = LoadSnippets(p)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s, ok := FindSnippet(got.Snippets, "tail-logs")
+	if !ok || s.Command != "tail -F /var/log/app.log" {
+		t.Fatalf("tail-logs: got=%#v ok=%v", s, ok)
+	}
+	if SnippetsPathFromConfigPath(filepath.Join(dir, "config.toml")) != p {
+		t.Fatalf("path=%q", SnippetsPathFromConfigPath(filepath.Join(dir, "config.toml")))
+	}
+}
+
+func TestLoadSnippetsRejectsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"bad name":  "[[snippets]]\nname = \"tail logs\"\ncommand = \"x\"\n",
+		"duplicate": "[[snippets]]\nname = \"a\"\ncommand = \"x\"\n[[snippets]]\nname = \"a\"\ncommand = \"y\"\n",
+		"command":   "[[snippets]]\nname = \"a\"\ncommand = \" \"\n",
+	}
+	for name, data := range cases {
+		p := filepath.Join(t.TempDir(), "snippets.toml")
+		if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := LoadSnippets(p); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSnippetApplies(t *testing.T) {
+	global := Snippet{Name: "g", Command: "x"}
+	byGroup := Snippet{Name: "p", Command: "x", Groups: []string{"prod"}}
+	byTag := Snippet{Name: "d", Command: "x", Tags: []string{"db"}}
+
+	if !global.Applies("", nil) {
+		t.Fatalf("global snippet should always apply")
+	}
+	if !byGroup.Applies("prod", nil) || byGroup.Applies("dev", nil) || byGroup.Applies("", []string{"prod"}) {
+		t.Fatalf("group scope mismatch")
+	}
+	if !byTag.Applies("", []string{"web", "db"}) || byTag.Applies("db", nil) {
+		t.Fatalf("tag scope mismatch")
+	}
+}
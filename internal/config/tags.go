@@ -0,0 +1,29 @@
+package config
+
+import "strings"
+
+// ParseTags splits a comma and/or whitespace separated tag list.
+func ParseTags(s string) []string {
+	return NormalizeTags(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}))
+}
+
+// NormalizeTags trims tags and drops empty and duplicate entries, keeping
+// the first occurrence order. It returns nil for an empty result.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
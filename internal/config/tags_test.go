@@ -0,0 +1,17 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" db, prod  web,db ,,")
+	want := []string{"db", "prod", "web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if got := ParseTags("  , "); got != nil {
+		t.Fatalf("got=%#v, want nil", got)
+	}
+}
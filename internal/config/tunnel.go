@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tunnel types, as in the type field.
+const (
+	TunnelLocal   = "local"   // ssh -L
+	TunnelRemote  = "remote"  // ssh -R
+	TunnelDynamic = "dynamic" // ssh -D (SOCKS)
+)
+
+// ValidateTunnels checks every [[hosts.tunnels]] and [[groups.tunnels]]
+// entry and that tunnel names are unique across the inventory.
+func ValidateTunnels(inv Inventory) error {
+	seen := make(map[string]bool)
+	check := func(owner string, tunnels []Tunnel) error {
+		for _, t := range tunnels {
+			if err := ValidateTunnel(t); err != nil {
+				return fmt.Errorf("%s: tunnel %q: %w", owner, t.Name, err)
+			}
+			if seen[t.Name] {
+				return fmt.Errorf("%s: tunnel %q: duplicate name", owner, t.Name)
+			}
+			seen[t.Name] = true
+		}
+		return nil
+	}
+	for _, h := range inv.Hosts {
+		if err := check(fmt.Sprintf("host %q", h.Host), h.Tunnels); err != nil {
+			return err
+		}
+	}
+	for _, g := range inv.Groups {
+		if err := check(fmt.Sprintf("group %q", g.Name), g.Tunnels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTunnel checks the name, type, listen address and target of t.
+func ValidateTunnel(t Tunnel) error {
+	if t.Name == "" {
+		return fmt.Errorf("name required")
+	}
+	if !validGroupName.MatchString(t.Name) {
+		return fmt.Errorf("name %q is invalid: only letters, digits, - and _ are allowed", t.Name)
+	}
+	if _, err := TunnelListenPort(t.Listen); err != nil {
+		return err
+	}
+	switch t.Type {
+	case TunnelLocal, TunnelRemote:
+		if _, _, err := splitHostPort(t.Target); err != nil {
+			return fmt.Errorf("target %q: %w", t.Target, err)
+		}
+	case TunnelDynamic:
+		if strings.TrimSpace(t.Target) != "" {
+			return fmt.Errorf("target is not used by %s tunnels", TunnelDynamic)
+		}
+	default:
+		return fmt.Errorf("type %q is invalid: use %s|%s|%s", t.Type, TunnelLocal, TunnelRemote, TunnelDynamic)
+	}
+	return nil
+}
+
+// TunnelListenPort returns the port of a [bind_address:]port listen value.
+func TunnelListenPort(listen string) (int, error) {
+	listen = strings.TrimSpace(listen)
+	if listen == "" {
+		return 0, fmt.Errorf("listen required")
+	}
+	port := listen
+	if strings.ContainsAny(listen, ":]") {
+		_, p, err := splitHostPort(listen)
+		if err != nil {
+			return 0, fmt.Errorf("listen %q: %w", listen, err)
+		}
+		port = p
+	}
+	p, err := parsePort(port)
+	if err != nil {
+		return 0, fmt.Errorf("listen %q: %w", listen, err)
+	}
+	return p, nil
+}
+
+// splitHostPort splits host:port or [host]:port; both parts are required.
+func splitHostPort(s string) (host, port string, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		end := strings.Index(s, "]:")
+		if end < 0 {
+			return "", "", fmt.Errorf("want [host]:port")
+		}
+		host, port = s[1:end], s[end+2:]
+	} else {
+		i := strings.LastIndex(s, ":")
+		if i < 0 {
+			return "", "", fmt.Errorf("want host:port")
+		}
+		host, port = s[:i], s[i+1:]
+		if strings.Contains(host, ":") {
+			return "", "", fmt.Errorf("IPv6 addresses must be bracketed")
+		}
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("empty host")
+	}
+	if _, err := parsePort(port); err != nil {
+		return "", "", err
+	}
+	return host, port, nil
+}
+
+func parsePort(s string) (int, error) {
+	p, err := strconv.Atoi(s)
+	if err != nil || p <= 0 || p > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return p, nil
+}
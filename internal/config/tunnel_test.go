@@ -0,0 +1,42 @@
+package config
+
+import "testing"
+
+func TestValidateTunnel(t *testing.T) {
+	valid := []Tunnel{
+		{Name: "pg", Type: TunnelLocal, Listen: "5432", Target: "db:5432"},
+		{Name: "web", Type: TunnelRemote, Listen: "0.0.0.0:8080", Target: "localhost:3000"},
+		{Name: "v6", Type: TunnelLocal, Listen: "[::1]:5432", Target: "[fe80::1]:5432"},
+		{Name: "socks", Type: TunnelDynamic, Listen: "*:1080"},
+	}
+	for _, tn := range valid {
+		if err := ValidateTunnel(tn); err != nil {
+			t.Fatalf("%s: %v", tn.Name, err)
+		}
+	}
+
+	invalid := []Tunnel{
+		{Type: TunnelDynamic, Listen: "1080"},
+		{Name: "a b", Type: TunnelDynamic, Listen: "1080"},
+		{Name: "x", Type: "forward", Listen: "1080"},
+		{Name: "x", Type: TunnelDynamic, Listen: ""},
+		{Name: "x", Type: TunnelDynamic, Listen: "70000"},
+		{Name: "x", Type: TunnelDynamic, Listen: "1080", Target: "db:1"},
+		{Name: "x", Type: TunnelLocal, Listen: "5432"},
+		{Name: "x", Type: TunnelLocal, Listen: "5432", Target: "db"},
+		{Name: "x", Type: TunnelLocal, Listen: "5432", Target: "fe80::1:5432"},
+	}
+	for _, tn := range invalid {
+		if err := ValidateTunnel(tn); err == nil {
+			t.Fatalf("%#v: want error", tn)
+		}
+	}
+}
+
+func TestTunnelListenPort(t *testing.T) {
+	for listen, want := range map[string]int{"5432": 5432, "127.0.0.1:8080": 8080, "[::1]:1080": 1080} {
+		if got, err := TunnelListenPort(listen); err != nil || got != want {
+			t.Fatalf("%s: got=%d err=%v, want %d", listen, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reuse_window values: what connecting to a host (or group) that already has
+// a tagged tmux pane does.
+const (
+	ReuseNew   = "new"   // open another window (default)
+	ReuseFocus = "focus" // select the open pane instead
+	ReuseAsk   = "ask"   // ask which of the two
+)
+
+// ValidateReuseWindow checks a reuse_window value: empty, "new", "focus" or
+// "ask".
+func ValidateReuseWindow(s string) error {
+	switch strings.TrimSpace(s) {
+	case "", ReuseNew, ReuseFocus, ReuseAsk:
+		return nil
+	default:
+		return fmt.Errorf("reuse_window %q is invalid: use \"new\", \"focus\" or \"ask\"", s)
+	}
+}
+
+// ValidateMaxPanes checks a max_panes_per_window value: 0 (no cap) or
+// positive.
+func ValidateMaxPanes(n int) error {
+	if n < 0 {
+		return fmt.Errorf("max_panes_per_window %d is invalid: use a positive number, or 0 for no limit", n)
+	}
+	return nil
+}
+
+// multiplexer values: where connects that need windows or panes open.
+const (
+	MuxAuto   = "auto"   // the one ssh-tui runs inside, tmux otherwise (default)
+	MuxTmux   = "tmux"   // tmux
+	MuxZellij = "zellij" // zellij tabs and panes
+	MuxScreen = "screen" // GNU screen windows and regions
+)
+
+// ValidateMultiplexer checks a multiplexer value: empty, "auto", "tmux",
+// "zellij" or "screen".
+func ValidateMultiplexer(s string) error {
+	switch strings.TrimSpace(s) {
+	case "", MuxAuto, MuxTmux, MuxZellij, MuxScreen:
+		return nil
+	default:
+		return fmt.Errorf("multiplexer %q is invalid: use \"auto\", \"tmux\", \"zellij\" or \"screen\"", s)
+	}
+}
@@ -0,0 +1,204 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Target kinds, as in the prefix of a target name.
+const (
+	KindDocker = "docker" // docker:CONTAINER
+	KindKube   = "k8s"    // k8s:NAMESPACE/POD
+)
+
+// Shell is the command started in a container.
+const Shell = "sh"
+
+// ListTimeout bounds `docker ps` and `kubectl get pods`.
+const ListTimeout = 10 * time.Second
+
+// Target is a container or pod to exec into.
+type Target struct {
+	Kind      string
+	Namespace string // k8s only; empty uses the kubectl context's namespace
+	Name      string
+}
+
+// Parse recognizes the target names "docker:NAME", "k8s:NAMESPACE/POD" and
+// "k8s:POD"; ok is false for ssh hosts.
+func Parse(host string) (t Target, ok bool) {
+	kind, rest, found := strings.Cut(strings.TrimSpace(host), ":")
+	if !found || rest == "" {
+		return Target{}, false
+	}
+	switch kind {
+	case KindDocker:
+		return Target{Kind: KindDocker, Name: rest}, true
+	case KindKube:
+		ns, pod, hasNS := strings.Cut(rest, "/")
+		if !hasNS {
+			return Target{Kind: KindKube, Name: rest}, true
+		}
+		if ns == "" || pod == "" {
+			return Target{}, false
+		}
+		return Target{Kind: KindKube, Namespace: ns, Name: pod}, true
+	}
+	return Target{}, false
+}
+
+// IsTarget reports whether host names a container or pod.
+func IsTarget(host string) bool {
+	_, ok := Parse(host)
+	return ok
+}
+
+// String returns the target name Parse accepts.
+func (t Target) String() string {
+	if t.Kind == KindKube && t.Namespace != "" {
+		return KindKube + ":" + t.Namespace + "/" + t.Name
+	}
+	return t.Kind + ":" + t.Name
+}
+
+// Command returns the argv that opens a shell in t, or runs remote through
+// it when set. tty allocates a terminal (-it); without it only stdin is
+// left closed, for commands whose output is collected.
+func (t Target) Command(tty bool, remote string) []string {
+	shell := []string{Shell}
+	if rc := strings.TrimSpace(remote); rc != "" {
+		shell = append(shell, "-c", rc)
+	}
+	var cmd []string
+	switch t.Kind {
+	case KindKube:
+		cmd = []string{"kubectl", "exec"}
+		if tty {
+			cmd = append(cmd, "-it")
+		}
+		cmd = append(cmd, t.Name)
+		if t.Namespace != "" {
+			cmd = append(cmd, "-n", t.Namespace)
+		}
+		cmd = append(cmd, "--")
+	default:
+		cmd = []string{"docker", "exec"}
+		if tty {
+			cmd = append(cmd, "-it")
+		}
+		cmd = append(cmd, t.Name)
+	}
+	return append(cmd, shell...)
+}
+
+// ParseDockerPS parses `docker ps --format json`: one JSON object per line,
+// with a comma-separated Names field. It returns "docker:NAME" targets.
+func ParseDockerPS(data []byte) ([]string, error) {
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var c struct {
+			Names string `json:"Names"`
+		}
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, err
+		}
+		// A container with several names is listed under the first.
+		name, _, _ := strings.Cut(c.Names, ",")
+		if name = strings.TrimSpace(name); name != "" {
+			out = append(out, Target{Kind: KindDocker, Name: name}.String())
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ParsePods parses `kubectl get pods -o json` and returns the running pods
+// as "k8s:NAMESPACE/POD" targets.
+func ParsePods(data []byte) ([]string, error) {
+	var doc struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Status struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, p := range doc.Items {
+		if p.Metadata.Name == "" || p.Status.Phase != "Running" {
+			continue
+		}
+		out = append(out, Target{Kind: KindKube, Namespace: p.Metadata.Namespace, Name: p.Metadata.Name}.String())
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Docker lists the running local containers.
+func Docker() ([]string, error) {
+	data, err := run([]string{"docker", "ps", "--format", "json"})
+	if err != nil {
+		return nil, err
+	}
+	return ParseDockerPS(data)
+}
+
+// Pods lists the running pods of namespace, or of every namespace when it
+// is empty, in the current kubectl context.
+func Pods(namespace string) ([]string, error) {
+	argv := []string{"kubectl", "get", "pods", "-o", "json"}
+	if ns := strings.TrimSpace(namespace); ns != "" {
+		argv = append(argv, "-n", ns)
+	} else {
+		argv = append(argv, "--all-namespaces")
+	}
+	data, err := run(argv)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePods(data)
+}
+
+func run(argv []string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ListTimeout)
+	defer cancel()
+
+	// #nosec G204 -- fixed argv; only the namespace comes from config.toml.
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", ListTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			msg, _, _ = strings.Cut(msg, "\n")
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
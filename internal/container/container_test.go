@@ -0,0 +1,74 @@
+package container
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Target
+		ok   bool
+	}{
+		{"docker:web", Target{Kind: KindDocker, Name: "web"}, true},
+		{"k8s:prod/api-0", Target{Kind: KindKube, Namespace: "prod", Name: "api-0"}, true},
+		{"k8s:api-0", Target{Kind: KindKube, Name: "api-0"}, true},
+		{"k8s:/api-0", Target{}, false},
+		{"docker:", Target{}, false},
+		{"db1", Target{}, false},
+		{"[db1]:2222", Target{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Fatalf("Parse(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+		if ok && got.String() != tt.in {
+			t.Fatalf("String() = %q, want %q", got.String(), tt.in)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	d := Target{Kind: KindDocker, Name: "web"}
+	if got, want := d.Command(true, ""), []string{"docker", "exec", "-it", "web", "sh"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+	k := Target{Kind: KindKube, Namespace: "prod", Name: "api-0"}
+	if got, want := k.Command(false, "uptime"), []string{"kubectl", "exec", "api-0", "-n", "prod", "--", "sh", "-c", "uptime"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
+
+func TestParseDockerPS(t *testing.T) {
+	data := []byte(`{"ID":"1","Names":"web","Image":"nginx"}
+{"ID":"2","Names":"db,db-alias","Image":"postgres"}
+
+`)
+	got, err := ParseDockerPS(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"docker:db", "docker:web"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+	if _, err := ParseDockerPS([]byte("not json")); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestParsePods(t *testing.T) {
+	data := []byte(`{"items": [
+		{"metadata": {"name": "api-0", "namespace": "prod"}, "status": {"phase": "Running"}},
+		{"metadata": {"name": "job-1", "namespace": "prod"}, "status": {"phase": "Succeeded"}},
+		{"metadata": {"name": "coredns", "namespace": "kube-system"}, "status": {"phase": "Running"}}
+	]}`)
+	got, err := ParsePods(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"k8s:kube-system/coredns", "k8s:prod/api-0"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
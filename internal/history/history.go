@@ -0,0 +1,109 @@
+package history
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Load reads a history file, one entry per line, oldest first.
+// A missing file is not an error.
+func Load(path string) ([]string, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	// #nosec G304 -- path is derived from the XDG state dir.
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []string
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, s.Err()
+}
+
+// Add appends entries to the history at path and keeps at most limit of the
+// newest ones. An entry that is already present moves to the end instead of
+// being duplicated. The updated history is returned.
+func Add(path string, entries []string, limit int) ([]string, error) {
+	cur, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	next := Merge(cur, entries, limit)
+
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return next, err
+	}
+	tmp, err := os.CreateTemp(dir, ".history.*")
+	if err != nil {
+		return next, err
+	}
+	tmpPath := filepath.Clean(tmp.Name())
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	w := bufio.NewWriter(tmp)
+	for _, e := range next {
+		_, _ = w.WriteString(e)
+		_ = w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return next, err
+	}
+	if err := tmp.Close(); err != nil {
+		return next, err
+	}
+	// #nosec G703 -- path is sanitized via filepath.Clean above.
+	if err := os.Rename(tmpPath, path); err != nil {
+		return next, err
+	}
+	return next, os.Chmod(path, 0o600)
+}
+
+// Merge returns cur with entries appended (moving existing ones to the end)
+// and trimmed to the newest limit entries. A limit <= 0 keeps everything.
+func Merge(cur []string, entries []string, limit int) []string {
+	drop := make(map[string]struct{}, len(entries))
+	var add []string
+	for _, e := range entries {
+		e = strings.TrimSpace(strings.ReplaceAll(e, "\n", " "))
+		if e == "" {
+			continue
+		}
+		if _, ok := drop[e]; ok {
+			continue
+		}
+		drop[e] = struct{}{}
+		add = append(add, e)
+	}
+
+	out := make([]string, 0, len(cur)+len(add))
+	for _, e := range cur {
+		if _, ok := drop[e]; ok {
+			continue
+		}
+		out = append(out, e)
+	}
+	out = append(out, add...)
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	got := Merge([]string{"a", "b", "c"}, []string{"b", "d", "d", " "}, 3)
+	want := []string{"c", "b", "d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+}
+
+func TestAddThenLoad(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "state", "history")
+
+	if got, err := Load(p); err != nil || len(got) != 0 {
+		t.Fatalf("Load missing: got=%v err=%v", got, err)
+	}
+	if _, err := Add(p, []string{"web1", "root@db1"}, 0); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := Add(p, []string{"web1"}, 0); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	got, err := Load(p)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{"root@db1", "web1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package hosts
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+// Candidates returns the names worth matching against hashed known_hosts
+// entries: every [[hosts]] entry and group member of the inventory plus any
+// extra names (ssh_config aliases, custom-host history). "user@" prefixes are
+// stripped. When a host resolves to a non-22 port, the "[host]:port" form ssh
+// hashes for it is included as well.
+func Candidates(d config.Defaults, inv config.Inventory, extra ...[]string) []string {
+	ports := make(map[string]int, len(inv.Hosts))
+	for _, h := range inv.Hosts {
+		if h.Port > 0 {
+			ports[strings.TrimSpace(h.Host)] = h.Port
+		}
+	}
+
+	set := make(map[string]struct{})
+	add := func(name string, groupPort int) {
+		name = strings.TrimSpace(name)
+		if i := strings.LastIndex(name, "@"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "" {
+			return
+		}
+		set[name] = struct{}{}
+		if strings.HasPrefix(name, "[") {
+			return
+		}
+		port := d.Port
+		if groupPort > 0 {
+			port = groupPort
+		}
+		if p, ok := ports[name]; ok {
+			port = p
+		}
+		if port > 0 && port != 22 {
+			set["["+name+"]:"+strconv.Itoa(port)] = struct{}{}
+		}
+	}
+
+	for _, h := range inv.Hosts {
+		add(h.Host, 0)
+	}
+	for _, g := range inv.Groups {
+		for _, h := range g.Hosts {
+			add(h, g.Port)
+		}
+	}
+	for _, names := range extra {
+		for _, n := range names {
+			add(n, 0)
+		}
+	}
+
+	out := make([]string, 0, len(set))
+	for n := range set {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,27 @@
+package hosts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func TestCandidates(t *testing.T) {
+	d := config.DefaultConfig().Defaults
+	inv := config.Inventory{
+		Hosts: []config.Host{
+			{Host: "db1", Port: 2222},
+			{Host: "web1"},
+		},
+		Groups: []config.Group{
+			{Name: "alt", Port: 2200, Hosts: []string{"app1", "db1"}},
+		},
+	}
+
+	got := Candidates(d, inv, []string{"root@bastion", ""})
+	want := []string{"[app1]:2200", "[db1]:2222", "app1", "bastion", "db1", "web1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package hosts
+
+import (
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/match"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+)
+
+// Member is a resolved group member.
+type Member struct {
+	Host string
+	// Chain lists the groups the host was reached through, starting with
+	// the resolved group and ending with the group that lists it.
+	Chain []config.Group
+	// Dynamic is set when the last group of Chain gets the host from
+	// [[sources]] or match patterns rather than its hosts list.
+	Dynamic bool
+}
+
+// ResolveMembers flattens g: its own members (static hosts, [[sources]]
+// members, match results), then the members of each include_groups entry,
+// depth-first. A host reached more than once keeps its first chain.
+func ResolveMembers(inv config.Inventory, g config.Group, res LoadResult) []Member {
+	var out []Member
+	seen := make(map[string]struct{})
+	onPath := make(map[string]bool)
+
+	var walk func(g config.Group, chain []config.Group)
+	walk = func(g config.Group, chain []config.Group) {
+		if onPath[g.Name] {
+			return // cycle; LoadInventory rejects these
+		}
+		onPath[g.Name] = true
+		defer delete(onPath, g.Name)
+
+		chain = append(chain[:len(chain):len(chain)], g)
+		for _, h := range directMembers(g, res) {
+			if _, ok := seen[h]; ok {
+				continue
+			}
+			seen[h] = struct{}{}
+			out = append(out, Member{Host: h, Chain: chain, Dynamic: !IsStaticMember(g, h)})
+		}
+		for _, name := range g.IncludeGroups {
+			if i := config.FindGroup(inv, name); i >= 0 {
+				walk(inv.Groups[i], chain)
+			}
+		}
+	}
+	walk(g, nil)
+	return out
+}
+
+// GroupMembers returns the flattened host list of g (see ResolveMembers).
+func GroupMembers(inv config.Inventory, g config.Group, res LoadResult) []string {
+	members := ResolveMembers(inv, g, res)
+	out := make([]string, 0, len(members))
+	for _, m := range members {
+		out = append(out, m.Host)
+	}
+	return out
+}
+
+// Chains maps resolved members to the group chain they were reached through.
+type Chains map[string][]config.Group
+
+// MemberChains resolves g (see ResolveMembers) and returns each member's chain.
+func MemberChains(inv config.Inventory, g config.Group, res LoadResult) Chains {
+	members := ResolveMembers(inv, g, res)
+	out := make(Chains, len(members))
+	for _, m := range members {
+		out[m.Host] = m.Chain
+	}
+	return out
+}
+
+// For returns the chain of host, or just g when host is not a member (e.g.
+// a custom host connected through the group).
+func (c Chains) For(g config.Group, host string) []config.Group {
+	if chain, ok := c[strings.TrimSpace(host)]; ok {
+		return chain
+	}
+	return []config.Group{g}
+}
+
+// directMembers returns the hosts of g itself: its static hosts in order,
+// followed by the [[sources]] hosts that declare membership in g and the
+// hosts its match patterns resolved to in res.
+func directMembers(g config.Group, res LoadResult) []string {
+	out := make([]string, 0, len(g.Hosts))
+	seen := make(map[string]struct{}, len(g.Hosts))
+	add := func(h string) {
+		if _, ok := seen[h]; ok {
+			return
+		}
+		seen[h] = struct{}{}
+		out = append(out, h)
+	}
+	for _, h := range g.Hosts {
+		if h = strings.TrimSpace(h); h != "" {
+			add(h)
+		}
+	}
+	for _, h := range res.Sourced {
+		for _, gn := range h.Groups {
+			if strings.TrimSpace(gn) == g.Name {
+				add(h.Name)
+				break
+			}
+		}
+	}
+	for _, h := range res.Matched[g.Name] {
+		add(h)
+	}
+	return out
+}
+
+// IsStaticMember reports whether host is listed in g.Hosts. Other members
+// come from [[sources]] or match patterns and cannot be removed from g.
+func IsStaticMember(g config.Group, host string) bool {
+	host = strings.TrimSpace(host)
+	for _, h := range g.Hosts {
+		if strings.TrimSpace(h) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGroups evaluates the match patterns of every group against hostList
+// and returns the matching hosts per group name, in hostList order. Patterns
+// that fail to compile match nothing; LoadInventory rejects them.
+func MatchGroups(inv config.Inventory, hostList []string, sourced []sources.Host) map[string][]string {
+	var out map[string][]string
+	var targets []match.Target
+	for _, g := range inv.Groups {
+		if len(g.Match) == 0 {
+			continue
+		}
+		set, err := match.CompileAll(g.Match)
+		if err != nil {
+			continue
+		}
+		if targets == nil {
+			targets = make([]match.Target, len(hostList))
+			for i, h := range hostList {
+				info := HostInfo(inv, sourced, h)
+				targets[i] = match.Target{Name: h, Tags: info.Tags, Meta: info.Meta}
+			}
+		}
+		var hits []string
+		for _, t := range targets {
+			if set.Match(t) {
+				hits = append(hits, t.Name)
+			}
+		}
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[g.Name] = hits
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package hosts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func TestResolveMembersNested(t *testing.T) {
+	inv := config.Inventory{Groups: []config.Group{
+		{Name: "prod", User: "ops", Hosts: []string{"bastion"}, IncludeGroups: []string{"prod-web", "prod-db"}},
+		{Name: "prod-web", Hosts: []string{"web1", "web2"}},
+		{Name: "prod-db", Port: 2222, Hosts: []string{"db1", "web1"}, IncludeGroups: []string{"prod-web"}},
+	}}
+	res := LoadResult{Matched: map[string][]string{"prod-db": {"db2"}}}
+
+	got := ResolveMembers(inv, inv.Groups[0], res)
+	type flat struct {
+		Host    string
+		Chain   []string
+		Dynamic bool
+	}
+	var gotFlat []flat
+	for _, m := range got {
+		var chain []string
+		for _, g := range m.Chain {
+			chain = append(chain, g.Name)
+		}
+		gotFlat = append(gotFlat, flat{m.Host, chain, m.Dynamic})
+	}
+	want := []flat{
+		{"bastion", []string{"prod"}, false},
+		{"web1", []string{"prod", "prod-web"}, false},
+		{"web2", []string{"prod", "prod-web"}, false},
+		{"db1", []string{"prod", "prod-db"}, false},
+		{"db2", []string{"prod", "prod-db"}, true},
+	}
+	if !reflect.DeepEqual(gotFlat, want) {
+		t.Fatalf("got=%#v\nwant=%#v", gotFlat, want)
+	}
+
+	names := GroupMembers(inv, inv.Groups[2], res)
+	if want := []string{"db1", "web1", "db2", "web2"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("members=%v, want %v", names, want)
+	}
+}
+
+func TestResolveMembersStopsOnCycle(t *testing.T) {
+	inv := config.Inventory{Groups: []config.Group{
+		{Name: "a", Hosts: []string{"h1"}, IncludeGroups: []string{"b"}},
+		{Name: "b", Hosts: []string{"h2"}, IncludeGroups: []string{"a"}},
+	}}
+	got := GroupMembers(inv, inv.Groups[0], LoadResult{})
+	if want := []string{"h1", "h2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%v want %v", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package hosts
+
+import (
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+// Info is the descriptive metadata of a host.
+type Info struct {
+	Tags        []string          `json:"tags,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Meta        map[string]string `json:"meta,omitempty"`
+}
+
+// HostInfo returns the metadata of host: the tags of its [[sources]] entry
+// and its hosts.toml override (unioned), plus the hosts.toml description
+// and meta.
+func HostInfo(inv config.Inventory, sourced []sources.Host, host string) Info {
+	var info Info
+	var tags []string
+	if sh, ok := FindSourced(sourced, host); ok {
+		tags = append(tags, sh.Tags...)
+	}
+	if hc, ok := sshcmd.FindHostConfig(inv.Hosts, host); ok {
+		tags = append(tags, hc.Tags...)
+		info.Description = hc.Description
+		info.Meta = hc.Meta
+	}
+	info.Tags = config.NormalizeTags(tags)
+	return info
+}
+
+// OfferedSnippets returns the snippets that apply to a connect through
+// group (nil for none) to members: global ones and those scoped to the
+// group, one of its tags or a tag of one of the members.
+func OfferedSnippets(inv config.Inventory, sourced []sources.Host, snippets []config.Snippet, group *config.Group, members []string) []config.Snippet {
+	name := ""
+	var tags []string
+	if group != nil {
+		name = group.Name
+		tags = append(tags, group.Tags...)
+	}
+	for _, h := range members {
+		tags = append(tags, HostInfo(inv, sourced, h).Tags...)
+	}
+	tags = config.NormalizeTags(tags)
+	var out []config.Snippet
+	for _, s := range snippets {
+		if s.Applies(name, tags) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package hosts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func TestOfferedSnippets(t *testing.T) {
+	inv := config.Inventory{Hosts: []config.Host{{Host: "db1", Tags: []string{"db"}}}}
+	snippets := []config.Snippet{
+		{Name: "uptime", Command: "uptime"},
+		{Name: "tail-logs", Command: "tail -F app.log", Groups: []string{"prod"}},
+		{Name: "psql", Command: "psql", Tags: []string{"db"}},
+		{Name: "deploy", Command: "deploy", Tags: []string{"web"}},
+	}
+	names := func(ss []config.Snippet) []string {
+		var out []string
+		for _, s := range ss {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+
+	got := names(OfferedSnippets(inv, nil, snippets, nil, []string{"web1"}))
+	if want := []string{"uptime"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("no scope: got=%v, want %v", got, want)
+	}
+	g := config.Group{Name: "prod", Tags: []string{"web"}}
+	got = names(OfferedSnippets(inv, nil, snippets, &g, []string{"db1", "web1"}))
+	if want := []string{"uptime", "tail-logs", "psql", "deploy"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("group: got=%v, want %v", got, want)
+	}
+}
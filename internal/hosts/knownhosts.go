@@ -2,16 +2,135 @@ package hosts
 
 import (
 	"bufio"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- known_hosts hashing is defined as HMAC-SHA1.
+	"encoding/base64"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshconfig"
 )
 
 type LoadResult struct {
 	Hosts        []string
 	SkippedLines int
+
+	// Hashed holds the hashed (|1|salt|hash) entries found while loading.
+	Hashed []HashedEntry
+	// HashedHosts lists the names that were only found through a hashed
+	// entry (see ResolveHashed). It is sorted.
+	HashedHosts []string
+	// UnresolvedHashed counts hashed entries no candidate name matched.
+	UnresolvedHashed int
+
+	// SSHConfig holds the ~/.ssh/config aliases merged into Hosts, sorted
+	// by alias.
+	SSHConfig []sshconfig.Host
+	// Sourced holds the [[sources]] hosts merged into Hosts, sorted by name.
+	Sourced []sources.Host
+	// Matched maps a group name to the Hosts matched by its match patterns.
+	Matched map[string][]string
+}
+
+// HashedEntry is a hashed known_hosts hostname (HashKnownHosts=yes). The
+// original name cannot be recovered; it can only be matched against
+// candidate names.
+type HashedEntry struct {
+	salt []byte
+	hash []byte
+}
+
+func parseHashedEntry(s string) (HashedEntry, bool) {
+	// Format: |1|base64(salt)|base64(hmac_sha1(salt, host))
+	parts := strings.Split(s, "|")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != "1" {
+		return HashedEntry{}, false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(salt) == 0 {
+		return HashedEntry{}, false
+	}
+	hash, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(hash) != sha1.Size {
+		return HashedEntry{}, false
+	}
+	return HashedEntry{salt: salt, hash: hash}, true
+}
+
+// Matches reports whether name hashes to this entry. Non-default ports must
+// be passed in the "[host]:port" form, as ssh does when hashing.
+func (e HashedEntry) Matches(name string) bool {
+	mac := hmac.New(sha1.New, e.salt)
+	_, _ = mac.Write([]byte(name))
+	return hmac.Equal(mac.Sum(nil), e.hash)
+}
+
+func (e HashedEntry) key() string {
+	return string(e.salt) + "|" + string(e.hash)
+}
+
+// ResolveHashed matches the hashed entries against candidate host names.
+// Every matched name is added to Hosts; names that were not already present
+// as plain entries are recorded in HashedHosts.
+func (r *LoadResult) ResolveHashed(candidates []string) {
+	r.HashedHosts = nil
+	r.UnresolvedHashed = 0
+	if len(r.Hashed) == 0 {
+		return
+	}
+
+	plain := make(map[string]struct{}, len(r.Hosts))
+	for _, h := range r.Hosts {
+		plain[h] = struct{}{}
+	}
+
+	names := make([]string, 0, len(candidates))
+	seen := make(map[string]struct{}, len(candidates))
+	for _, c := range candidates {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		names = append(names, c)
+	}
+
+	found := make(map[string]struct{})
+	for _, e := range r.Hashed {
+		matched := false
+		for _, n := range names {
+			if e.Matches(n) {
+				matched = true
+				found[n] = struct{}{}
+			}
+		}
+		if !matched {
+			r.UnresolvedHashed++
+		}
+	}
+
+	for h := range found {
+		if _, ok := plain[h]; ok {
+			continue
+		}
+		r.Hosts = append(r.Hosts, h)
+		r.HashedHosts = append(r.HashedHosts, h)
+	}
+	sort.Strings(r.Hosts)
+	sort.Strings(r.HashedHosts)
+}
+
+// IsHashed reports whether host was only found through a hashed entry.
+func (r LoadResult) IsHashed(host string) bool {
+	i := sort.SearchStrings(r.HashedHosts, host)
+	return i < len(r.HashedHosts) && r.HashedHosts[i] == host
 }
 
 type PathError struct {
@@ -40,6 +159,8 @@ func LoadKnownHosts(paths []string) (LoadResult, []PathError) {
 	}
 
 	set := make(map[string]struct{})
+	var hashed []HashedEntry
+	hashedSeen := make(map[string]struct{})
 	var skipped int
 	var errs []PathError
 
@@ -55,7 +176,7 @@ func LoadKnownHosts(paths []string) (LoadResult, []PathError) {
 			continue
 		}
 
-		hosts, sk, perr := ParseKnownHosts(f)
+		hosts, hs, sk, perr := ParseKnownHosts(f)
 		_ = f.Close()
 		skipped += sk
 		if perr != nil {
@@ -66,6 +187,13 @@ func LoadKnownHosts(paths []string) (LoadResult, []PathError) {
 		for _, h := range hosts {
 			set[h] = struct{}{}
 		}
+		for _, e := range hs {
+			if _, ok := hashedSeen[e.key()]; ok {
+				continue
+			}
+			hashedSeen[e.key()] = struct{}{}
+			hashed = append(hashed, e)
+		}
 	}
 
 	out := make([]string, 0, len(set))
@@ -74,15 +202,18 @@ func LoadKnownHosts(paths []string) (LoadResult, []PathError) {
 	}
 	sort.Strings(out)
 
-	return LoadResult{Hosts: out, SkippedLines: skipped}, errs
+	return LoadResult{Hosts: out, SkippedLines: skipped, Hashed: hashed}, errs
 }
 
-func ParseKnownHosts(r io.Reader) ([]string, int, error) {
+// ParseKnownHosts returns the plain host names and the hashed entries of a
+// known_hosts file, plus the number of lines that carried neither.
+func ParseKnownHosts(r io.Reader) ([]string, []HashedEntry, int, error) {
 	s := bufio.NewScanner(r)
 	// known_hosts lines can be long because of key material; bump buffer.
 	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	set := make(map[string]struct{})
+	var hashed []HashedEntry
 	skipped := 0
 
 	for s.Scan() {
@@ -101,27 +232,30 @@ func ParseKnownHosts(r io.Reader) ([]string, int, error) {
 			skipped++
 			continue
 		}
-		first := fields[0]
-		if strings.HasPrefix(first, "|1|") {
-			skipped++
-			continue
-		}
-
-		for _, raw := range strings.Split(first, ",") {
+		used := false
+		for _, raw := range strings.Split(fields[0], ",") {
 			h := strings.TrimSpace(raw)
 			if h == "" {
 				continue
 			}
-			// Hashed hostnames are not displayable in MVP.
-			if strings.HasPrefix(h, "|1|") {
+			// Hashed hostnames are kept opaque; see ResolveHashed.
+			if strings.HasPrefix(h, "|") {
+				if e, ok := parseHashedEntry(h); ok {
+					hashed = append(hashed, e)
+					used = true
+				}
 				continue
 			}
 			set[h] = struct{}{}
+			used = true
+		}
+		if !used {
+			skipped++
 		}
 	}
 
 	if err := s.Err(); err != nil {
-		return nil, skipped, err
+		return nil, nil, skipped, err
 	}
 
 	hosts := make([]string, 0, len(set))
@@ -130,5 +264,5 @@ func ParseKnownHosts(r io.Reader) ([]string, int, error) {
 	}
 	sort.Strings(hosts)
 
-	return hosts, skipped, nil
+	return hosts, hashed, skipped, nil
 }
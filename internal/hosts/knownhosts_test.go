@@ -1,6 +1,9 @@
 package hosts
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -14,18 +17,23 @@ func TestParseKnownHosts(t *testing.T) {
 		"",
 		"@cert-authority example.com ssh-ed25519 AAAA...",
 		"|1|hashedhost ssh-ed25519 AAAA...",
+		hashHost("c2FsdHNhbHQ=", "hidden.example") + " ssh-ed25519 AAAA...",
 		"example.com,10.0.0.1 ssh-ed25519 AAAA...",
 		"[10.10.10.10]:2222 ssh-rsa AAAA...",
 		"example.com ssh-ed25519 AAAA...",
 	}, "\n")
 
-	hosts, skipped, err := ParseKnownHosts(strings.NewReader(in))
+	hosts, hashed, skipped, err := ParseKnownHosts(strings.NewReader(in))
 	if err != nil {
 		t.Fatalf("ParseKnownHosts error: %v", err)
 	}
+	// The malformed "|1|hashedhost" entry is skipped; the valid one is kept.
 	if skipped != 4 {
 		t.Fatalf("skipped=%d, want %d", skipped, 4)
 	}
+	if len(hashed) != 1 || !hashed[0].Matches("hidden.example") {
+		t.Fatalf("hashed=%v, want one entry matching hidden.example", hashed)
+	}
 
 	want := []string{"10.0.0.1", "[10.10.10.10]:2222", "example.com"}
 	if !reflect.DeepEqual(hosts, want) {
@@ -55,3 +63,50 @@ func TestLoadKnownHosts(t *testing.T) {
 		t.Fatalf("hosts=%v, want %v", res.Hosts, want)
 	}
 }
+
+func hashHost(saltB64, host string) string {
+	salt, _ := base64.StdEncoding.DecodeString(saltB64)
+	mac := hmac.New(sha1.New, salt)
+	_, _ = mac.Write([]byte(host))
+	return "|1|" + saltB64 + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestResolveHashed(t *testing.T) {
+	d := t.TempDir()
+	f := filepath.Join(d, "known_hosts")
+	in := strings.Join([]string{
+		"plain.example ssh-ed25519 AAAA...",
+		hashHost("MTIzNDU2Nzg5MA==", "web1.example") + " ssh-ed25519 AAAA...",
+		hashHost("YWJjZGVmZ2hpag==", "[db1.example]:2222") + " ssh-ed25519 AAAA...",
+		hashHost("a2xtbm9wcXJzdA==", "plain.example") + " ssh-rsa AAAA...",
+		hashHost("dXZ3eHl6MDEyMw==", "unknown.example") + " ssh-ed25519 AAAA...",
+	}, "\n")
+	if err := os.WriteFile(f, []byte(in+"\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	res, errs := LoadKnownHosts([]string{f})
+	if len(errs) != 0 {
+		t.Fatalf("errs=%v", errs)
+	}
+	if len(res.Hashed) != 4 {
+		t.Fatalf("hashed=%d, want 4", len(res.Hashed))
+	}
+
+	res.ResolveHashed([]string{"web1.example", "[db1.example]:2222", "plain.example", "other"})
+
+	wantHosts := []string{"[db1.example]:2222", "plain.example", "web1.example"}
+	if !reflect.DeepEqual(res.Hosts, wantHosts) {
+		t.Fatalf("hosts=%v, want %v", res.Hosts, wantHosts)
+	}
+	wantHashed := []string{"[db1.example]:2222", "web1.example"}
+	if !reflect.DeepEqual(res.HashedHosts, wantHashed) {
+		t.Fatalf("hashedHosts=%v, want %v", res.HashedHosts, wantHashed)
+	}
+	if res.UnresolvedHashed != 1 {
+		t.Fatalf("unresolved=%d, want 1", res.UnresolvedHashed)
+	}
+	if !res.IsHashed("web1.example") || res.IsHashed("plain.example") {
+		t.Fatalf("IsHashed mismatch: %v", res.HashedHosts)
+	}
+}
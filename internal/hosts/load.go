@@ -0,0 +1,159 @@
+package hosts
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshconfig"
+)
+
+// Sources describes where Load reads hosts from. Which sources are used is
+// decided by Defaults.LoadKnownHosts, LoadSSHConfig, LoadDocker and
+// LoadKubernetes.
+type Sources struct {
+	Defaults  config.Defaults
+	Inventory config.Inventory
+	// KnownHosts are known_hosts paths (empty: DefaultKnownHostsPaths).
+	KnownHosts []string
+	// SSHConfig is the ssh_config path (empty: sshconfig.DefaultPath).
+	SSHConfig string
+	// History holds custom-host history, used as hashed candidates.
+	History []string
+
+	// Dynamic are the [[sources]] commands to run.
+	Dynamic []config.Source
+	// CacheDir holds cached source output (empty: no caching).
+	CacheDir string
+	// Refresh re-runs sources even when their cache is fresh.
+	Refresh bool
+	// CacheOnly reads the cached output of the sources, however old,
+	// instead of running them (shell completion must not block).
+	CacheOnly bool
+}
+
+// Load builds the host list: known_hosts (or the inventory when
+// load_known_hosts is off) merged with ~/.ssh/config aliases, [[sources]]
+// output and running containers and pods, with hashed known_hosts entries
+// resolved against every name known to the sources.
+func Load(src Sources) (LoadResult, []PathError) {
+	var res LoadResult
+	var errs []PathError
+	if src.Defaults.LoadKnownHosts {
+		res, errs = LoadKnownHosts(src.KnownHosts)
+	} else {
+		res.Hosts = config.ConfigHosts(src.Inventory)
+	}
+
+	var aliases []string
+	if src.Defaults.LoadSSHConfig {
+		p := src.SSHConfig
+		if p == "" {
+			p = sshconfig.DefaultPath()
+		}
+		sc, err := sshconfig.Load(p)
+		if err != nil {
+			errs = append(errs, PathError{Path: p, Err: err})
+		}
+		res.SSHConfig = sc
+		for _, h := range sc {
+			aliases = append(aliases, h.Alias)
+			if h.HostName != "" {
+				// known_hosts records the resolved HostName, not the alias.
+				aliases = append(aliases, h.HostName)
+			}
+		}
+		res.Hosts = mergeHosts(res.Hosts, hostAliases(sc))
+	}
+
+	if len(src.Dynamic) > 0 {
+		var dyn []sources.Host
+		if src.CacheOnly {
+			dyn = sources.Cached(src.Dynamic, src.CacheDir)
+		} else {
+			var derrs []sources.Error
+			dyn, derrs = sources.Load(src.Dynamic, src.CacheDir, src.Refresh)
+			for _, e := range derrs {
+				errs = append(errs, PathError{Path: "source " + e.Source, Err: e.Err})
+			}
+		}
+		// Keep source order for equal names so the first source wins.
+		sort.SliceStable(dyn, func(i, j int) bool { return dyn[i].Name < dyn[j].Name })
+		res.Sourced = dyn
+		names := make([]string, 0, len(dyn))
+		for _, h := range dyn {
+			names = append(names, h.Name)
+			if h.Port > 0 && h.Port != 22 {
+				aliases = append(aliases, "["+h.Name+"]:"+strconv.Itoa(h.Port))
+			}
+		}
+		res.Hosts = mergeHosts(res.Hosts, names)
+		aliases = append(aliases, names...)
+	}
+
+	if src.Defaults.LoadDocker {
+		names, err := container.Docker()
+		if err != nil {
+			errs = append(errs, PathError{Path: "docker", Err: err})
+		}
+		res.Hosts = mergeHosts(res.Hosts, names)
+	}
+	if src.Defaults.LoadKubernetes {
+		names, err := container.Pods(src.Defaults.KubernetesNamespace)
+		if err != nil {
+			errs = append(errs, PathError{Path: "kubectl", Err: err})
+		}
+		res.Hosts = mergeHosts(res.Hosts, names)
+	}
+
+	res.ResolveHashed(Candidates(src.Defaults, src.Inventory, src.History, aliases))
+	res.Matched = MatchGroups(src.Inventory, res.Hosts, res.Sourced)
+	return res, errs
+}
+
+// FindSourced returns the first [[sources]] entry for name. hosts must be
+// sorted by name, as in LoadResult.Sourced.
+func FindSourced(hosts []sources.Host, name string) (sources.Host, bool) {
+	i := sort.Search(len(hosts), func(i int) bool { return hosts[i].Name >= name })
+	if i < len(hosts) && hosts[i].Name == name {
+		return hosts[i], true
+	}
+	return sources.Host{}, false
+}
+
+// FindSSHConfig returns the ssh_config entry for alias (hosts are sorted by
+// alias, as returned by sshconfig.Load).
+func FindSSHConfig(hosts []sshconfig.Host, alias string) (sshconfig.Host, bool) {
+	i := sort.Search(len(hosts), func(i int) bool { return hosts[i].Alias >= alias })
+	if i < len(hosts) && hosts[i].Alias == alias {
+		return hosts[i], true
+	}
+	return sshconfig.Host{}, false
+}
+
+func hostAliases(hosts []sshconfig.Host) []string {
+	out := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		out = append(out, h.Alias)
+	}
+	return out
+}
+
+// mergeHosts returns the sorted union of a and b.
+func mergeHosts(a, b []string) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for _, h := range a {
+		set[h] = struct{}{}
+	}
+	for _, h := range b {
+		set[h] = struct{}{}
+	}
+	out := make([]string, 0, len(set))
+	for h := range set {
+		out = append(out, h)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,141 @@
+package hosts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+)
+
+func TestLoadMergesSSHConfig(t *testing.T) {
+	d := t.TempDir()
+	kh := filepath.Join(d, "known_hosts")
+	sc := filepath.Join(d, "config")
+	in := "a.example ssh-ed25519 AAAA...\n" +
+		hashHost("c2FsdHNhbHQ=", "10.0.0.5") + " ssh-ed25519 AAAA...\n"
+	if err := os.WriteFile(kh, []byte(in), 0o600); err != nil {
+		t.Fatalf("write known_hosts: %v", err)
+	}
+	if err := os.WriteFile(sc, []byte("Host db-primary\n  HostName 10.0.0.5\nHost *\n  User x\n"), 0o600); err != nil {
+		t.Fatalf("write ssh config: %v", err)
+	}
+
+	src := Sources{
+		Defaults:   config.DefaultConfig().Defaults,
+		KnownHosts: []string{kh},
+		SSHConfig:  sc,
+	}
+	src.Defaults.LoadSSHConfig = true
+	res, errs := Load(src)
+	if len(errs) != 0 {
+		t.Fatalf("errs=%v", errs)
+	}
+	want := []string{"10.0.0.5", "a.example", "db-primary"}
+	if !reflect.DeepEqual(res.Hosts, want) {
+		t.Fatalf("hosts=%v, want %v", res.Hosts, want)
+	}
+	if _, ok := FindSSHConfig(res.SSHConfig, "db-primary"); !ok {
+		t.Fatalf("db-primary missing from ssh_config hosts: %#v", res.SSHConfig)
+	}
+	if !res.IsHashed("10.0.0.5") {
+		t.Fatalf("hashed HostName not resolved: %v", res.HashedHosts)
+	}
+
+	src.Defaults.LoadSSHConfig = false
+	src.Defaults.LoadKnownHosts = false
+	src.Inventory = config.Inventory{Hosts: []config.Host{{Host: "inv.example"}}}
+	res, _ = Load(src)
+	if want := []string{"inv.example"}; !reflect.DeepEqual(res.Hosts, want) {
+		t.Fatalf("hosts=%v, want %v", res.Hosts, want)
+	}
+}
+
+func TestLoadRunsSources(t *testing.T) {
+	d := config.DefaultConfig().Defaults
+	d.LoadKnownHosts = false
+	d.LoadSSHConfig = false
+	src := Sources{
+		Defaults:  d,
+		Inventory: config.Inventory{Groups: []config.Group{{Name: "prod", Hosts: []string{"static1", "web1"}}}},
+		Dynamic: []config.Source{
+			{Name: "cmdb", Command: []string{"sh", "-c", `echo '{"hosts":[{"name":"web2","groups":["prod"]},{"name":"web1","groups":["prod"]}]}'`}},
+			{Name: "broken", Command: []string{"sh", "-c", "exit 1"}},
+		},
+	}
+
+	res, errs := Load(src)
+	if len(errs) != 1 || errs[0].Path != "source broken" {
+		t.Fatalf("errs=%v, want one error for source broken", errs)
+	}
+	want := []string{"static1", "web1", "web2"}
+	if !reflect.DeepEqual(res.Hosts, want) {
+		t.Fatalf("hosts=%v, want %v", res.Hosts, want)
+	}
+	if h, ok := FindSourced(res.Sourced, "web2"); !ok || h.Source != "cmdb" {
+		t.Fatalf("FindSourced(web2)=%#v, %v", h, ok)
+	}
+
+	got := GroupMembers(src.Inventory, src.Inventory.Groups[0], res)
+	if want := []string{"static1", "web1", "web2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("members=%v, want %v", got, want)
+	}
+}
+
+func TestHostInfo(t *testing.T) {
+	inv := config.Inventory{Hosts: []config.Host{{
+		Host:        "db1",
+		Tags:        []string{"db", "prod"},
+		Description: "primary",
+		Meta:        map[string]string{"owner": "dba"},
+	}}}
+	sourced := []sources.Host{{Name: "db1", Tags: []string{"cmdb", "db"}, Source: "cmdb"}}
+
+	got := HostInfo(inv, sourced, "db1")
+	want := Info{
+		Tags:        []string{"cmdb", "db", "prod"},
+		Description: "primary",
+		Meta:        map[string]string{"owner": "dba"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if got := HostInfo(inv, sourced, "other"); !reflect.DeepEqual(got, Info{}) {
+		t.Fatalf("got=%#v, want empty", got)
+	}
+}
+
+func TestLoadMatchesGroups(t *testing.T) {
+	d := config.DefaultConfig().Defaults
+	d.LoadKnownHosts = false
+	d.LoadSSHConfig = false
+	inv := config.Inventory{
+		Hosts: []config.Host{
+			{Host: "db1", Tags: []string{"db"}, Meta: map[string]string{"env": "prod"}},
+			{Host: "db2", Tags: []string{"db"}, Meta: map[string]string{"env": "staging"}},
+		},
+		Groups: []config.Group{
+			{Name: "web", Hosts: []string{"web1.prod", "web2.stage"}},
+			{Name: "prod", Hosts: []string{"bastion", "db1"}, Match: []string{"web*.prod", "tag:db && env:prod"}},
+		},
+	}
+	res, errs := Load(Sources{Defaults: d, Inventory: inv})
+	if len(errs) != 0 {
+		t.Fatalf("errs=%v", errs)
+	}
+	if want := []string{"db1", "web1.prod"}; !reflect.DeepEqual(res.Matched["prod"], want) {
+		t.Fatalf("matched=%v, want %v", res.Matched["prod"], want)
+	}
+	got := GroupMembers(inv, inv.Groups[1], res)
+	if want := []string{"bastion", "db1", "web1.prod"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("members=%v, want %v", got, want)
+	}
+	if IsStaticMember(inv.Groups[1], "web1.prod") || !IsStaticMember(inv.Groups[1], "db1") {
+		t.Fatalf("IsStaticMember mismatch")
+	}
+	if _, ok := res.Matched["web"]; ok {
+		t.Fatalf("group without match patterns resolved: %v", res.Matched)
+	}
+}
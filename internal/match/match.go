@@ -0,0 +1,305 @@
+package match
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Target is what a pattern is evaluated against: a host name plus its tags
+// and metadata.
+type Target struct {
+	Name string
+	Tags []string
+	Meta map[string]string
+}
+
+// Expr is a compiled pattern.
+type Expr interface {
+	Match(t Target) bool
+}
+
+// Set is a list of patterns; a target matches the set when it matches any
+// of them.
+type Set []Expr
+
+// Match reports whether t matches any pattern of s.
+func (s Set) Match(t Target) bool {
+	for _, e := range s {
+		if e.Match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompileAll compiles every pattern; the first error is returned with the
+// offending pattern quoted.
+func CompileAll(patterns []string) (Set, error) {
+	out := make(Set, 0, len(patterns))
+	for _, p := range patterns {
+		e, err := Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("match %q: %w", p, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Compile parses one pattern. Terms are:
+//
+//	web*.example.com   glob on the host name (path.Match syntax)
+//	/^db-\d+$/         regular expression on the host name
+//	tag:db             the host has a tag matching db (glob or /regex/)
+//	env:prod           the host's meta "env" matches prod (glob or /regex/)
+//	k8s:prod/*         glob on a container target name (docker: and k8s:)
+//
+// Terms combine with &&, ||, ! and parentheses; && binds tighter than ||.
+func Compile(pattern string) (Expr, error) {
+	toks, err := tokenize(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	p := &parser{toks: toks}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	return e, nil
+}
+
+func isOpStart(c byte) bool {
+	return c == '&' || c == '|' || c == '!' || c == '(' || c == ')'
+}
+
+func tokenize(s string) ([]string, error) {
+	var out []string
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(s) || s[i+1] != c {
+				return nil, fmt.Errorf("expected %c%c at offset %d", c, c, i)
+			}
+			out = append(out, s[i:i+2])
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			out = append(out, string(c))
+			i++
+		default:
+			start := i
+			for i < len(s) && s[i] != ' ' && s[i] != '\t' && !isOpStart(s[i]) {
+				// A regex starts a term or follows "key:"; elsewhere / is
+				// literal, as in k8s:prod/api-*.
+				if s[i] == '/' && (i == start || s[i-1] == ':') {
+					end, err := regexEnd(s, i)
+					if err != nil {
+						return nil, err
+					}
+					i = end
+					continue
+				}
+				i++
+			}
+			out = append(out, s[start:i])
+		}
+	}
+	return out, nil
+}
+
+// regexEnd returns the offset just past the "/.../" segment starting at i.
+func regexEnd(s string, i int) (int, error) {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '/':
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated regex at offset %d", i)
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch tok := p.peek(); tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of pattern")
+	case "!":
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	case "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		p.pos++
+		return parseTerm(tok)
+	}
+}
+
+var metaKey = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_-]*):(.*)$`)
+
+// targetPrefixes name container targets ("docker:redis"); a term starting
+// with one is a glob on the whole host name rather than a meta key.
+var targetPrefixes = map[string]bool{"docker": true, "k8s": true}
+
+func parseTerm(tok string) (Expr, error) {
+	if strings.HasPrefix(tok, "/") {
+		v, err := compileValue(tok)
+		if err != nil {
+			return nil, err
+		}
+		return nameExpr{v}, nil
+	}
+	if m := metaKey.FindStringSubmatch(tok); m != nil && !targetPrefixes[m[1]] {
+		if m[2] == "" {
+			return nil, fmt.Errorf("%s: value required", m[1])
+		}
+		v, err := compileValue(m[2])
+		if err != nil {
+			return nil, err
+		}
+		if m[1] == "tag" {
+			return tagExpr{v}, nil
+		}
+		return metaExpr{key: m[1], v: v}, nil
+	}
+	v, err := compileValue(tok)
+	if err != nil {
+		return nil, err
+	}
+	return nameExpr{v}, nil
+}
+
+// valueMatcher matches a single string with a glob or a regex.
+type valueMatcher struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func compileValue(s string) (valueMatcher, error) {
+	if len(s) >= 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/") {
+		re, err := regexp.Compile(s[1 : len(s)-1])
+		if err != nil {
+			return valueMatcher{}, err
+		}
+		return valueMatcher{re: re}, nil
+	}
+	if _, err := path.Match(s, ""); err != nil {
+		return valueMatcher{}, fmt.Errorf("bad glob %q", s)
+	}
+	return valueMatcher{glob: s}, nil
+}
+
+func (v valueMatcher) match(s string) bool {
+	if v.re != nil {
+		return v.re.MatchString(s)
+	}
+	ok, _ := path.Match(v.glob, s)
+	return ok
+}
+
+type nameExpr struct{ v valueMatcher }
+
+func (e nameExpr) Match(t Target) bool { return e.v.match(t.Name) }
+
+type tagExpr struct{ v valueMatcher }
+
+func (e tagExpr) Match(t Target) bool {
+	for _, tag := range t.Tags {
+		if e.v.match(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+type metaExpr struct {
+	key string
+	v   valueMatcher
+}
+
+func (e metaExpr) Match(t Target) bool {
+	val, ok := t.Meta[e.key]
+	return ok && e.v.match(val)
+}
+
+type andExpr struct{ l, r Expr }
+
+func (e andExpr) Match(t Target) bool { return e.l.Match(t) && e.r.Match(t) }
+
+type orExpr struct{ l, r Expr }
+
+func (e orExpr) Match(t Target) bool { return e.l.Match(t) || e.r.Match(t) }
+
+type notExpr struct{ e Expr }
+
+func (e notExpr) Match(t Target) bool { return !e.e.Match(t) }
@@ -0,0 +1,87 @@
+package match
+
+import "testing"
+
+func TestCompileAndMatch(t *testing.T) {
+	web := Target{Name: "web1.prod.example.com", Tags: []string{"web"}, Meta: map[string]string{"env": "prod"}}
+	db := Target{Name: "db-12", Tags: []string{"db", "primary"}, Meta: map[string]string{"env": "prod"}}
+	dbStage := Target{Name: "db-3", Tags: []string{"db"}, Meta: map[string]string{"env": "staging"}}
+
+	tests := []struct {
+		pattern string
+		want    []bool // web, db, dbStage
+	}{
+		{"web*.prod.example.com", []bool{true, false, false}},
+		{`/^db-\d+$/`, []bool{false, true, true}},
+		{"tag:db && env:prod", []bool{false, true, false}},
+		{"tag:db&&!env:prod", []bool{false, false, true}},
+		{"tag:web || (tag:db && env:stag*)", []bool{true, false, true}},
+		{"env:/^(prod|staging)$/ && !tag:primary", []bool{true, false, true}},
+		{"owner:*", []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.pattern, err)
+		}
+		for i, target := range []Target{web, db, dbStage} {
+			if got := e.Match(target); got != tt.want[i] {
+				t.Fatalf("%q on %s: got %v want %v", tt.pattern, target.Name, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestContainerTargets(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"k8s:prod/*", "k8s:prod/api-0", true},
+		{"k8s:prod/*", "k8s:dev/api-0", false},
+		{"docker:*", "docker:redis", true},
+		{"docker:*", "web1", false},
+		{"docker:* && !docker:redis", "docker:pg", true},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.pattern)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.pattern, err)
+		}
+		if got := e.Match(Target{Name: tt.name}); got != tt.want {
+			t.Fatalf("%q on %s: got %v want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, p := range []string{
+		"",
+		"tag:db &&",
+		"tag:db & env:prod",
+		"(tag:db",
+		"tag:db)",
+		"/unterminated",
+		"/(/",
+		"tag:",
+		"web[",
+	} {
+		if _, err := Compile(p); err == nil {
+			t.Fatalf("expected error for %q", p)
+		}
+	}
+}
+
+func TestSetMatchesAny(t *testing.T) {
+	s, err := CompileAll([]string{"a*", "tag:x"})
+	if err != nil {
+		t.Fatalf("CompileAll: %v", err)
+	}
+	if !s.Match(Target{Name: "b", Tags: []string{"x"}}) || s.Match(Target{Name: "b"}) {
+		t.Fatalf("set match mismatch")
+	}
+	if _, err := CompileAll([]string{"ok", "bad["}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,139 @@
+package mux
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/tmux"
+)
+
+// Multiplexer opens connections in windows and panes of a terminal
+// multiplexer. Window and pane settings use the tmux vocabulary of
+// tmux.OneWindowOpts; other multiplexers translate what they can.
+type Multiplexer interface {
+	// Name is the multiplexer setting selecting it.
+	Name() string
+	// Inside reports whether ssh-tui runs inside the multiplexer.
+	Inside() bool
+	// NewWindow opens a window (a zellij tab) named name running cmd.
+	// session and tag are tmux only, see tmux.NewWindowIn.
+	NewWindow(session, name string, cmd []string, tag tmux.Tag) error
+	// OpenPanes opens one window split into a pane per command, titled,
+	// laid out and synchronized as opts says.
+	OpenPanes(cmds [][]string, opts tmux.OneWindowOpts) error
+	// Unsupported names the settings of opts that OpenPanes ignores for
+	// paneCount panes.
+	Unsupported(paneCount int, opts tmux.OneWindowOpts) []string
+}
+
+// all is the detection order of "auto".
+var all = []Multiplexer{Tmux{}, Zellij{}, Screen{}}
+
+// Select returns the multiplexer of the multiplexer setting; "auto" (or
+// empty) detects it.
+func Select(setting string) Multiplexer {
+	switch strings.ToLower(strings.TrimSpace(setting)) {
+	case config.MuxTmux:
+		return Tmux{}
+	case config.MuxZellij:
+		return Zellij{}
+	case config.MuxScreen:
+		return Screen{}
+	}
+	return Detect()
+}
+
+// Detect returns the multiplexer ssh-tui runs inside, tmux when none.
+func Detect() Multiplexer {
+	for _, m := range all {
+		if m.Inside() {
+			return m
+		}
+	}
+	return Tmux{}
+}
+
+// IsTmux reports whether m is tmux, the only multiplexer with detached
+// sessions, pane tags and window reuse.
+func IsTmux(m Multiplexer) bool { return m.Name() == config.MuxTmux }
+
+// NotInside is the error of a connect needing m, which is not tmux, from
+// outside it: only tmux starts detached sessions.
+func NotInside(m Multiplexer) error {
+	return fmt.Errorf("multiplexer = %q: run ssh-tui inside %s, or use tmux", m.Name(), m.Name())
+}
+
+// Tmux is the tmux multiplexer of package tmux.
+type Tmux struct{}
+
+func (Tmux) Name() string { return config.MuxTmux }
+
+func (Tmux) Inside() bool { return tmux.InTmux() }
+
+func (Tmux) NewWindow(session, name string, cmd []string, tag tmux.Tag) error {
+	return tmux.NewWindowIn(session, name, cmd, tag)
+}
+
+func (Tmux) OpenPanes(cmds [][]string, opts tmux.OneWindowOpts) error {
+	return tmux.OpenOneWindow(cmds, opts)
+}
+
+func (Tmux) Unsupported(int, tmux.OneWindowOpts) []string { return nil }
+
+// borderSettings names the pane border settings of opts: zellij and screen
+// draw no tmux pane borders, so a border that is not "off" is ignored.
+func borderSettings(opts tmux.OneWindowOpts) []string {
+	status := strings.TrimSpace(opts.PaneBorderStatus)
+	if status == "" || status == "off" {
+		return nil
+	}
+	out := []string{"pane_border_status"}
+	if strings.TrimSpace(opts.PaneBorderFormat) != "" {
+		out = append(out, "pane_border_format")
+	}
+	return out
+}
+
+// run runs argv and reports its output as the error when it fails.
+func run(argv []string) error {
+	// #nosec G204 -- running the multiplexer with argv (no shell); args are constructed by the app.
+	out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("%s error: %s", argv[0], msg)
+	}
+	return nil
+}
+
+// windowName is name, "ssh" when empty.
+func windowName(name string) string {
+	if name = strings.TrimSpace(name); name == "" {
+		return "ssh"
+	}
+	return name
+}
+
+// paneTitle is the title of pane i, empty when opts has none.
+func paneTitle(opts tmux.OneWindowOpts, i int) string {
+	if i < 0 || i >= len(opts.PaneTitles) {
+		return ""
+	}
+	return strings.TrimSpace(opts.PaneTitles[i])
+}
+
+// sideBySide reports whether the panes of opts go next to each other rather
+// than stacked: the even-* layouts decide, the split direction otherwise.
+func sideBySide(opts tmux.OneWindowOpts) bool {
+	switch strings.TrimSpace(opts.Layout) {
+	case "even-horizontal":
+		return true
+	case "even-vertical":
+		return false
+	}
+	return strings.TrimSpace(opts.SplitFlag) != "-v"
+}
@@ -0,0 +1,129 @@
+package mux
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/tmux"
+)
+
+func TestSelect(t *testing.T) {
+	for _, tc := range []struct {
+		setting string
+		env     map[string]string
+		want    string
+	}{
+		{"", nil, "tmux"},
+		{"auto", map[string]string{"ZELLIJ": "0"}, "zellij"},
+		{"auto", map[string]string{"STY": "123.pts-0.box"}, "screen"},
+		{"", map[string]string{"TMUX": "/tmp/tmux-0/default,1,0", "STY": "123.pts-0.box"}, "tmux"},
+		{"screen", map[string]string{"TMUX": "/tmp/tmux-0/default,1,0"}, "screen"},
+		{" Zellij ", nil, "zellij"},
+		{"tmux", map[string]string{"ZELLIJ": "0"}, "tmux"},
+	} {
+		for _, k := range []string{"TMUX", "ZELLIJ", "STY"} {
+			t.Setenv(k, tc.env[k])
+		}
+		if got := Select(tc.setting).Name(); got != tc.want {
+			t.Fatalf("Select(%q) with %v = %q, want %q", tc.setting, tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestZellijLayout(t *testing.T) {
+	cmds := [][]string{{"ssh", "web1"}, {"ssh", "-p", "2222", "web2"}}
+	got := zellijLayout(cmds, []string{"web1", `we"b2`}, tmux.OneWindowOpts{Layout: "even-horizontal"})
+	want := `layout {
+    pane size=1 borderless=true {
+        plugin location="zellij:tab-bar"
+    }
+    pane split_direction="vertical" {
+        pane name="web1" command="ssh" {
+            args "web1"
+        }
+        pane name="we\"b2" command="ssh" {
+            args "-p" "2222" "web2"
+        }
+    }
+    pane size=2 borderless=true {
+        plugin location="zellij:status-bar"
+    }
+}
+`
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestZellijLayoutTiled(t *testing.T) {
+	cmds := [][]string{{"a"}, {"b"}, {"c"}}
+	got := zellijLayout(cmds, nil, tmux.OneWindowOpts{Layout: "tiled"})
+	want := `layout {
+    pane size=1 borderless=true {
+        plugin location="zellij:tab-bar"
+    }
+    pane split_direction="horizontal" {
+        pane split_direction="vertical" {
+            pane command="a"
+            pane command="b"
+        }
+        pane command="c"
+    }
+    pane size=2 borderless=true {
+        plugin location="zellij:status-bar"
+    }
+}
+`
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestScreenPaneCmds(t *testing.T) {
+	cmds := [][]string{{"ssh", "web1"}, {"ssh", "web2"}}
+	got := screenPaneCmds(cmds, tmux.OneWindowOpts{PaneTitles: []string{"web1", "web2"}, SplitFlag: "-v"})
+	want := [][]string{
+		{"screen", "-t", "web1", "ssh", "web1"},
+		{"split"},
+		{"focus"},
+		{"screen", "-t", "web2", "ssh", "web2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	got = screenPaneCmds(cmds, tmux.OneWindowOpts{Layout: "even-horizontal", SplitFlag: "-v"})
+	if !reflect.DeepEqual(got[1], []string{"split", "-v"}) {
+		t.Fatalf("even-horizontal split = %q, want side by side", got[1])
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	opts := tmux.OneWindowOpts{Layout: "tiled", SyncPanes: true, MaxPanes: 2}
+	if got := (Tmux{}).Unsupported(3, opts); got != nil {
+		t.Fatalf("tmux: %q", got)
+	}
+	if got := (Zellij{}).Unsupported(3, opts); got != nil {
+		t.Fatalf("zellij: %q", got)
+	}
+	want := []string{`pane_layout "tiled"`, "pane_sync", "max_panes_per_window"}
+	if got := (Screen{}).Unsupported(3, opts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("screen: %q, want %q", got, want)
+	}
+	if got := (Screen{}).Unsupported(1, opts); got != nil {
+		t.Fatalf("screen, one pane: %q", got)
+	}
+
+	opts.PaneBorderStatus, opts.PaneBorderFormat = "bottom", "#T"
+	border := []string{"pane_border_status", "pane_border_format"}
+	if got := (Zellij{}).Unsupported(3, opts); !reflect.DeepEqual(got, border) {
+		t.Fatalf("zellij, border: %q, want %q", got, border)
+	}
+	if got := (Screen{}).Unsupported(3, opts); !reflect.DeepEqual(got, append(want, border...)) {
+		t.Fatalf("screen, border: %q", got)
+	}
+	opts.PaneBorderStatus = "off"
+	if got := (Zellij{}).Unsupported(3, opts); got != nil {
+		t.Fatalf("zellij, border off: %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+package mux
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/tmux"
+)
+
+// Screen drives the GNU screen session ssh-tui runs in with `screen -X`.
+// Windows carry the pane titles; one-window connects split the display
+// into regions, each showing a new window. Regions cannot be synchronized
+// or tiled.
+type Screen struct{}
+
+func (Screen) Name() string { return config.MuxScreen }
+
+func (Screen) Inside() bool { return os.Getenv("STY") != "" }
+
+func (Screen) NewWindow(_, name string, cmd []string, _ tmux.Tag) error {
+	return run(screenX(screenWindow(windowName(name), cmd)))
+}
+
+func (Screen) OpenPanes(cmds [][]string, opts tmux.OneWindowOpts) error {
+	if len(cmds) == 0 {
+		return fmt.Errorf("no hosts selected")
+	}
+	for _, c := range screenPaneCmds(cmds, opts) {
+		if err := run(screenX(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (Screen) Unsupported(paneCount int, opts tmux.OneWindowOpts) []string {
+	if paneCount < 2 {
+		return nil
+	}
+	var out []string
+	switch l := strings.TrimSpace(opts.Layout); l {
+	case "", "even-horizontal", "even-vertical":
+	default:
+		out = append(out, fmt.Sprintf("pane_layout %q", l))
+	}
+	if opts.SyncPanes {
+		out = append(out, "pane_sync")
+	}
+	if opts.MaxPanes > 0 && paneCount > opts.MaxPanes {
+		out = append(out, "max_panes_per_window")
+	}
+	return append(out, borderSettings(opts)...)
+}
+
+// screenPaneCmds returns the screen commands splitting the display into a
+// region per command: the first window takes the current region, every
+// other one a region split from the last.
+func screenPaneCmds(cmds [][]string, opts tmux.OneWindowOpts) [][]string {
+	split := []string{"split"}
+	if sideBySide(opts) {
+		split = []string{"split", "-v"}
+	}
+	out := [][]string{screenWindow(paneTitle(opts, 0), cmds[0])}
+	for i := 1; i < len(cmds); i++ {
+		out = append(out, split, []string{"focus"}, screenWindow(paneTitle(opts, i), cmds[i]))
+	}
+	return out
+}
+
+// screenWindow is the screen command opening a window titled title that
+// runs cmd.
+func screenWindow(title string, cmd []string) []string {
+	out := []string{"screen"}
+	if title != "" {
+		out = append(out, "-t", title)
+	}
+	return append(out, cmd...)
+}
+
+// screenX returns the argv sending the screen command c to the current
+// session.
+func screenX(c []string) []string {
+	return append([]string{"screen", "-S", os.Getenv("STY"), "-X"}, c...)
+}
@@ -0,0 +1,182 @@
+package mux
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/tmux"
+)
+
+// Zellij opens tabs from generated layouts: `zellij action new-tab
+// --layout`. Every tmux layout maps to nested splits; pane_sync toggles the
+// sync of the new tab.
+type Zellij struct{}
+
+func (Zellij) Name() string { return config.MuxZellij }
+
+func (Zellij) Inside() bool { return os.Getenv("ZELLIJ") != "" }
+
+func (Zellij) NewWindow(_, name string, cmd []string, _ tmux.Tag) error {
+	name = windowName(name)
+	return zellijNewTab(name, zellijLayout([][]string{cmd}, []string{name}, tmux.OneWindowOpts{}))
+}
+
+// OpenPanes opens a tab, or one per max_panes_per_window hosts named like
+// tmux windows (NAME-1, NAME-2, ...).
+func (Zellij) OpenPanes(cmds [][]string, opts tmux.OneWindowOpts) error {
+	if len(cmds) == 0 {
+		return fmt.Errorf("no hosts selected")
+	}
+	name := windowName(opts.WindowName)
+	n := tmux.WindowCount(len(cmds), opts.MaxPanes)
+	size := len(cmds)
+	if n > 1 {
+		size = opts.MaxPanes
+	}
+	for w := 0; w < n; w++ {
+		start := w * size
+		end := min(start+size, len(cmds))
+		titles := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			titles = append(titles, paneTitle(opts, i))
+		}
+		tab := name
+		if n > 1 {
+			tab = fmt.Sprintf("%s-%d", name, w+1)
+		}
+		if err := zellijNewTab(tab, zellijLayout(cmds[start:end], titles, opts)); err != nil {
+			return err
+		}
+		if opts.SyncPanes && end-start > 1 {
+			if err := run([]string{"zellij", "action", "toggle-active-sync-tab"}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (Zellij) Unsupported(_ int, opts tmux.OneWindowOpts) []string {
+	var out []string
+	switch l := strings.TrimSpace(opts.Layout); l {
+	case "", "tiled", "even-horizontal", "even-vertical", "main-horizontal", "main-vertical":
+	default:
+		out = append(out, fmt.Sprintf("pane_layout %q", l))
+	}
+	return append(out, borderSettings(opts)...)
+}
+
+// zellijNewTab opens a tab named name with the KDL layout.
+func zellijNewTab(name, layout string) error {
+	f, err := os.CreateTemp("", "ssh-tui-*.kdl")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.WriteString(layout); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return run([]string{"zellij", "action", "new-tab", "--layout", f.Name(), "--name", name})
+}
+
+// zellijLayout returns the KDL layout of a tab with a pane per command,
+// named after titles, between the tab and status bars of the default
+// layout.
+func zellijLayout(cmds [][]string, titles []string, opts tmux.OneWindowOpts) string {
+	panes := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		title := ""
+		if i < len(titles) {
+			title = titles[i]
+		}
+		panes[i] = zellijPane(title, cmd)
+	}
+
+	var body string
+	switch {
+	case len(panes) == 1:
+		body = panes[0]
+	case opts.Layout == "tiled":
+		cols := int(math.Ceil(math.Sqrt(float64(len(panes)))))
+		var rows []string
+		for start := 0; start < len(panes); start += cols {
+			row := panes[start:min(start+cols, len(panes))]
+			rows = append(rows, zellijSplit("vertical", row))
+		}
+		body = zellijSplit("horizontal", rows)
+	case opts.Layout == "main-vertical":
+		body = zellijSplit("vertical", []string{panes[0], zellijSplit("horizontal", panes[1:])})
+	case opts.Layout == "main-horizontal":
+		body = zellijSplit("horizontal", []string{panes[0], zellijSplit("vertical", panes[1:])})
+	case sideBySide(opts):
+		body = zellijSplit("vertical", panes)
+	default:
+		body = zellijSplit("horizontal", panes)
+	}
+
+	return "layout {\n" +
+		indent("pane size=1 borderless=true {\n    plugin location=\"zellij:tab-bar\"\n}") +
+		indent(body) +
+		indent("pane size=2 borderless=true {\n    plugin location=\"zellij:status-bar\"\n}") +
+		"}\n"
+}
+
+// zellijPane is a command pane running cmd.
+func zellijPane(title string, cmd []string) string {
+	var b strings.Builder
+	b.WriteString("pane")
+	if title != "" {
+		b.WriteString(" name=" + kdlString(title))
+	}
+	if len(cmd) == 0 {
+		return b.String()
+	}
+	b.WriteString(" command=" + kdlString(cmd[0]))
+	if len(cmd) == 1 {
+		return b.String()
+	}
+	args := make([]string, len(cmd)-1)
+	for i, a := range cmd[1:] {
+		args[i] = kdlString(a)
+	}
+	b.WriteString(" {\n    args " + strings.Join(args, " ") + "\n}")
+	return b.String()
+}
+
+// zellijSplit is a pane holding children, next to each other ("vertical")
+// or stacked ("horizontal"). A single child is returned as is.
+func zellijSplit(direction string, children []string) string {
+	if len(children) == 1 {
+		return children[0]
+	}
+	var b strings.Builder
+	b.WriteString("pane split_direction=" + kdlString(direction) + " {\n")
+	for _, c := range children {
+		b.WriteString(indent(c))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// indent indents every line of node by four spaces and ends it with a
+// newline.
+func indent(node string) string {
+	lines := strings.Split(node, "\n")
+	for i, l := range lines {
+		lines[i] = "    " + l
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// kdlString quotes s as a KDL string.
+func kdlString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,287 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+	"github.com/al-bashkir/ssh-tui/internal/sshconfig"
+)
+
+// DefaultParallel is the number of dials running at once.
+const DefaultParallel = 16
+
+// State is the outcome of a probe.
+type State int
+
+const (
+	Unknown State = iota // not probed yet, or behind a jump host
+	Up
+	Down
+)
+
+func (s State) String() string {
+	switch s {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// Result is the last probe of an address.
+type Result struct {
+	State   State
+	Latency time.Duration // time to connect; set when Up
+	Err     string        // set when Down
+	At      time.Time
+}
+
+// Addr returns the host:port to dial for host with the given ssh port and
+// extra args: "[host]:port" names, `-p` and `-o HostName=`/`-o Port=` in
+// extraArgs are honored as ssh would, and the port defaults to 22.
+func Addr(host string, port int, extraArgs []string) string {
+	host = strings.TrimSpace(host)
+	if h, p, ok := sshcmd.SplitBracketHost(host); ok {
+		host, port = h, p
+	}
+	for i := 0; i < len(extraArgs); i++ {
+		a := extraArgs[i]
+		var opt string
+		switch {
+		case a == "-o" && i+1 < len(extraArgs):
+			i++
+			opt = extraArgs[i]
+		case strings.HasPrefix(a, "-o") && len(a) > 2:
+			opt = a[2:]
+		case a == "-p" && i+1 < len(extraArgs):
+			i++
+			if p, err := strconv.Atoi(extraArgs[i]); err == nil && p > 0 {
+				port = p
+			}
+			continue
+		default:
+			continue
+		}
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			k, v, ok = strings.Cut(opt, " ")
+		}
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "hostname":
+			if v != "" {
+				host = v
+			}
+		case "port":
+			if p, err := strconv.Atoi(v); err == nil && p > 0 {
+				port = p
+			}
+		}
+	}
+	if port <= 0 {
+		port = 22
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// Target returns the address to probe for host connected with s. As with
+// ssh, the ~/.ssh/config HostName applies, and its Port unless s sets a
+// port other than 22. Hosts reached through jump hosts or a proxied
+// backend (tsh), and containers, cannot be probed directly (ok is false).
+func Target(host string, s sshcmd.Settings, sshConfig []sshconfig.Host) (addr string, ok bool) {
+	if len(s.Jump) > 0 || container.IsTarget(host) {
+		return "", false
+	}
+	if b, found := sshcmd.LookupBackend(s.Backend); found && b.Proxied {
+		return "", false
+	}
+	name, port := host, s.Port
+	if sc, found := hosts.FindSSHConfig(sshConfig, host); found {
+		if sc.HostName != "" {
+			name = sc.HostName
+		}
+		if sc.Port != 0 && (port == 0 || port == 22) {
+			port = sc.Port
+		}
+	}
+	return Addr(name, port, s.ExtraArgs), true
+}
+
+// Dial connects to addr and closes the connection right away.
+func Dial(ctx context.Context, addr string, timeout time.Duration) Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var d net.Dialer
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	r := Result{At: time.Now()}
+	if err != nil {
+		r.State = Down
+		r.Err = dialError(err)
+		return r
+	}
+	_ = conn.Close()
+	r.State = Up
+	r.Latency = r.At.Sub(start)
+	return r
+}
+
+// dialError shortens a dial error to its cause ("connection refused",
+// "timeout", "no such host").
+func dialError(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return "no such host"
+	case isTimeout(err):
+		return "timeout"
+	}
+	s := err.Error()
+	if i := strings.LastIndex(s, ": "); i >= 0 {
+		s = s[i+2:]
+	}
+	return s
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// Cache holds the last result per address. It is safe for concurrent use.
+type Cache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	results  map[string]Result
+	inflight map[string]bool
+}
+
+// NewCache returns a cache whose results are probed again after ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, results: make(map[string]Result), inflight: make(map[string]bool)}
+}
+
+// Get returns the last result of addr; State is Unknown when there is none.
+func (c *Cache) Get(addr string) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.results[addr]
+}
+
+// Claim returns the addresses, deduplicated, that have no result younger
+// than the TTL and no probe in flight, and marks them in flight until Put.
+func (c *Cache) Claim(addrs []string, now time.Time) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []string
+	for _, a := range addrs {
+		if a == "" || c.inflight[a] {
+			continue
+		}
+		if r, ok := c.results[a]; ok && now.Sub(r.At) < c.ttl {
+			continue
+		}
+		c.inflight[a] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// Put stores the result of addr and ends its probe.
+func (c *Cache) Put(addr string, r Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[addr] = r
+	delete(c.inflight, addr)
+}
+
+// Check dials addrs with at most parallel at once and returns the results
+// in order. Empty addresses, and those not dialed because ctx was
+// canceled, are Unknown.
+func Check(ctx context.Context, addrs []string, parallel int, timeout time.Duration) []Result {
+	results := make([]Result, len(addrs))
+	sem := make(chan struct{}, max(1, parallel))
+	var wg sync.WaitGroup
+	for i, a := range addrs {
+		if a == "" {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if r := Dial(ctx, a, timeout); ctx.Err() == nil {
+				results[i] = r
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Report is the outcome of a pre-flight check of a group's hosts.
+type Report struct {
+	Up      []string // reachable, or behind a jump host and not checked
+	Down    []string
+	Reasons []string // why each Down host failed ("connection refused")
+}
+
+// Preflight checks hosts before connecting to them; addrs[i] is the
+// address of hosts[i] (see Target), empty when it cannot be probed.
+func Preflight(ctx context.Context, hosts, addrs []string, timeout time.Duration) Report {
+	var rep Report
+	for i, r := range Check(ctx, addrs, DefaultParallel, timeout) {
+		if r.State == Down {
+			rep.Down = append(rep.Down, hosts[i])
+			rep.Reasons = append(rep.Reasons, r.Err)
+			continue
+		}
+		rep.Up = append(rep.Up, hosts[i])
+	}
+	return rep
+}
+
+// Run checks addrs (see Check) and stores the results in c. Addresses left
+// Unknown are released unprobed.
+func Run(ctx context.Context, c *Cache, addrs []string, parallel int, timeout time.Duration) {
+	for i, r := range Check(ctx, addrs, parallel, timeout) {
+		if r.State == Unknown {
+			c.release(addrs[i])
+			continue
+		}
+		c.Put(addrs[i], r)
+	}
+}
+
+func (c *Cache) release(addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inflight, addr)
+}
@@ -0,0 +1,95 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddr(t *testing.T) {
+	for _, tc := range []struct {
+		host string
+		port int
+		args []string
+		want string
+	}{
+		{"web1", 0, nil, "web1:22"},
+		{"web1", 2222, nil, "web1:2222"},
+		{"[web1]:2200", 22, nil, "web1:2200"},
+		{"fe80::1", 0, nil, "[fe80::1]:22"},
+		{"db", 22, []string{"-o", "HostName=10.0.0.5"}, "10.0.0.5:22"},
+		{"db", 22, []string{"-oPort=2022", "-o", "ServerAliveInterval=30"}, "db:2022"},
+		{"db", 22, []string{"-p", "2023"}, "db:2023"},
+	} {
+		if got := Addr(tc.host, tc.port, tc.args); got != tc.want {
+			t.Fatalf("Addr(%q, %d, %q) = %q; want %q", tc.host, tc.port, tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	r := Dial(context.Background(), addr, time.Second)
+	if r.State != Up || r.Latency <= 0 {
+		t.Fatalf("listening: %#v", r)
+	}
+	_ = ln.Close()
+	r = Dial(context.Background(), addr, time.Second)
+	if r.State != Down || r.Err == "" {
+		t.Fatalf("closed: %#v", r)
+	}
+}
+
+func TestCache(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	up := ln.Addr().String()
+
+	c := NewCache(time.Minute)
+	now := time.Now()
+	claimed := c.Claim([]string{up, "", up}, now)
+	if !reflect.DeepEqual(claimed, []string{up}) {
+		t.Fatalf("claim: %v", claimed)
+	}
+	if again := c.Claim([]string{up}, now); len(again) != 0 {
+		t.Fatalf("in flight claimed again: %v", again)
+	}
+	Run(context.Background(), c, claimed, 2, time.Second)
+	if r := c.Get(up); r.State != Up {
+		t.Fatalf("get: %#v", r)
+	}
+	if again := c.Claim([]string{up}, now.Add(time.Second)); len(again) != 0 {
+		t.Fatalf("fresh result claimed: %v", again)
+	}
+	if again := c.Claim([]string{up}, now.Add(2*time.Minute)); len(again) != 1 {
+		t.Fatalf("stale result not claimed: %v", again)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := closed.Addr().String()
+	_ = closed.Close()
+
+	rep := Preflight(context.Background(), []string{"a", "b", "c"}, []string{ln.Addr().String(), down, ""}, time.Second)
+	if !reflect.DeepEqual(rep.Up, []string{"a", "c"}) || !reflect.DeepEqual(rep.Down, []string{"b"}) || rep.Reasons[0] == "" {
+		t.Fatalf("%#v", rep)
+	}
+}
@@ -0,0 +1,308 @@
+package resolve
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+	"github.com/al-bashkir/ssh-tui/internal/tmpl"
+)
+
+// Origin names where an effective value came from.
+type Origin string
+
+const (
+	OriginDefaults Origin = "defaults"
+	OriginHost     Origin = "host override"
+	OriginFlag     Origin = "CLI flag"
+)
+
+// OriginGroup is the origin of a value set by group name.
+func OriginGroup(name string) Origin { return Origin("group " + name) }
+
+// OriginSource is the origin of a value set by the [[sources]] entry name.
+func OriginSource(name string) Origin { return Origin("source " + name) }
+
+// Field names, as in the config files.
+const (
+	FieldUser             = "user"
+	FieldPort             = "port"
+	FieldIdentityFile     = "identity_file"
+	FieldExtraArgs        = "extra_args"
+	FieldJump             = "jump"
+	FieldBackend          = "backend"
+	FieldRemoteCommand    = "remote_command"
+	FieldTmux             = "tmux"
+	FieldOpenMode         = "open_mode"
+	FieldPaneSplit        = "pane_split"
+	FieldPaneLayout       = "pane_layout"
+	FieldPaneSync         = "pane_sync"
+	FieldPaneBorderStatus = "pane_border_status"
+	FieldPaneBorderFormat = "pane_border_format"
+	FieldWindowName       = "window_name"
+	FieldPaneTitle        = "pane_title"
+	FieldReuseWindow      = "reuse_window"
+	FieldMaxPanes         = "max_panes_per_window"
+)
+
+// Flags are command-line overrides; they win over every config value.
+type Flags struct {
+	NoTmux bool // -no-tmux: tmux = "never"
+}
+
+// Input is everything that contributes to the settings of a connection.
+type Input struct {
+	Defaults  config.Defaults
+	Inventory config.Inventory
+	Sourced   []sources.Host
+	// Chain is the group chain the host is reached through (see
+	// hosts.Chains.For), outermost group first. Empty for a direct connect.
+	Chain []config.Group
+	Flags Flags
+}
+
+// Window holds the tmux settings of a connect. They are per window, so only
+// the outermost group of the chain applies.
+type Window struct {
+	Tmux             string
+	OpenMode         string
+	PaneSplit        string
+	PaneLayout       string
+	PaneSync         string
+	PaneBorderStatus string
+	PaneBorderFormat string
+	WindowName       string // template; see Names
+	PaneTitle        string // template; see Names
+	ReuseWindow      string // new|focus|ask
+	MaxPanes         int    // panes per window of a one-window connect; 0 = no cap
+}
+
+// Settings are the effective settings of one connection.
+type Settings struct {
+	SSH    sshcmd.Settings
+	Window Window
+
+	origins map[string]Origin
+}
+
+// Field is one effective value and its origin, for display.
+type Field struct {
+	Name   string
+	Value  string
+	Origin Origin
+}
+
+// Resolve applies, lowest precedence first: defaults, the groups of
+// in.Chain (outermost first), the [[sources]] entry of host, its [[hosts]]
+// override and in.Flags. An empty host resolves the group and window
+// settings only.
+func Resolve(in Input, host string) Settings {
+	s := Settings{origins: make(map[string]Origin)}
+
+	d := in.Defaults
+	s.setSSH(OriginDefaults, d.User, d.Port, d.IdentityFile, d.ExtraArgs, d.Jump, d.Backend, "")
+	s.setWindow(OriginDefaults, Window{
+		Tmux:             d.Tmux,
+		OpenMode:         d.OpenMode,
+		PaneSplit:        d.PaneSplit,
+		PaneLayout:       d.PaneLayout,
+		PaneSync:         d.PaneSync,
+		PaneBorderStatus: d.PaneBorderPos,
+		PaneBorderFormat: d.PaneBorderFmt,
+		WindowName:       d.WindowName,
+		PaneTitle:        d.PaneTitle,
+		ReuseWindow:      d.ReuseWindow,
+		MaxPanes:         d.MaxPanesPerWindow,
+	})
+
+	for i, g := range in.Chain {
+		origin := OriginGroup(g.Name)
+		s.setSSH(origin, g.User, g.Port, g.IdentityFile, g.ExtraArgs, g.Jump, g.Backend, g.RemoteCommand)
+		if i == 0 {
+			s.setWindow(origin, Window{
+				Tmux:             g.Tmux,
+				OpenMode:         g.OpenMode,
+				PaneSplit:        g.PaneSplit,
+				PaneLayout:       g.PaneLayout,
+				PaneSync:         g.PaneSync,
+				PaneBorderStatus: g.PaneBorderPos,
+				PaneBorderFormat: g.PaneBorderFmt,
+				WindowName:       g.WindowName,
+				PaneTitle:        g.PaneTitle,
+				ReuseWindow:      g.ReuseWindow,
+				MaxPanes:         g.MaxPanesPerWindow,
+			})
+		}
+	}
+
+	if host = strings.TrimSpace(host); host != "" {
+		if sh, ok := hosts.FindSourced(in.Sourced, host); ok {
+			s.setSSH(OriginSource(sh.Source), sh.User, sh.Port, "", nil, nil, "", "")
+		}
+		if hc, ok := sshcmd.FindHostConfig(in.Inventory.Hosts, host); ok {
+			s.setSSH(OriginHost, hc.User, hc.Port, hc.IdentityFile, hc.ExtraArgs, hc.Jump, hc.Backend, "")
+		}
+	}
+	s.SSH.Jump = expandJump(in, s.SSH.Jump)
+
+	if in.Flags.NoTmux {
+		s.set(FieldTmux, OriginFlag, "never", &s.Window.Tmux)
+	}
+	return s
+}
+
+// Origin returns where the named field got its value; empty when no layer
+// set it.
+func (s Settings) Origin(field string) Origin { return s.origins[field] }
+
+// Fields lists every setting in display order.
+func (s Settings) Fields() []Field {
+	port := ""
+	if s.SSH.Port != 0 {
+		port = strconv.Itoa(s.SSH.Port)
+	}
+	maxPanes := ""
+	if s.Window.MaxPanes != 0 {
+		maxPanes = strconv.Itoa(s.Window.MaxPanes)
+	}
+	values := []struct{ name, value string }{
+		{FieldUser, s.SSH.User},
+		{FieldPort, port},
+		{FieldIdentityFile, s.SSH.IdentityFile},
+		{FieldExtraArgs, strings.Join(s.SSH.ExtraArgs, " ")},
+		{FieldJump, strings.Join(s.SSH.Jump, ",")},
+		{FieldBackend, s.SSH.Backend},
+		{FieldRemoteCommand, s.SSH.RemoteCommand},
+		{FieldTmux, s.Window.Tmux},
+		{FieldOpenMode, s.Window.OpenMode},
+		{FieldPaneSplit, s.Window.PaneSplit},
+		{FieldPaneLayout, s.Window.PaneLayout},
+		{FieldPaneSync, s.Window.PaneSync},
+		{FieldPaneBorderStatus, s.Window.PaneBorderStatus},
+		{FieldPaneBorderFormat, s.Window.PaneBorderFormat},
+		{FieldWindowName, s.Window.WindowName},
+		{FieldPaneTitle, s.Window.PaneTitle},
+		{FieldReuseWindow, s.Window.ReuseWindow},
+		{FieldMaxPanes, maxPanes},
+	}
+	out := make([]Field, 0, len(values))
+	for _, v := range values {
+		out = append(out, Field{Name: v.name, Value: v.value, Origin: s.origins[v.name]})
+	}
+	return out
+}
+
+func (s *Settings) setSSH(origin Origin, user string, port int, identity string, extra, jump []string, backend, remote string) {
+	s.set(FieldUser, origin, user, &s.SSH.User)
+	if port != 0 {
+		s.SSH.Port = port
+		s.origins[FieldPort] = origin
+	}
+	s.set(FieldIdentityFile, origin, identity, &s.SSH.IdentityFile)
+	if len(extra) != 0 {
+		s.SSH.ExtraArgs = extra
+		s.origins[FieldExtraArgs] = origin
+	}
+	if len(jump) != 0 {
+		s.SSH.Jump = jump
+		s.origins[FieldJump] = origin
+	}
+	s.set(FieldBackend, origin, backend, &s.SSH.Backend)
+	s.set(FieldRemoteCommand, origin, remote, &s.SSH.RemoteCommand)
+}
+
+func (s *Settings) setWindow(origin Origin, w Window) {
+	s.set(FieldTmux, origin, w.Tmux, &s.Window.Tmux)
+	s.set(FieldOpenMode, origin, w.OpenMode, &s.Window.OpenMode)
+	s.set(FieldPaneSplit, origin, w.PaneSplit, &s.Window.PaneSplit)
+	s.set(FieldPaneLayout, origin, w.PaneLayout, &s.Window.PaneLayout)
+	s.set(FieldPaneSync, origin, w.PaneSync, &s.Window.PaneSync)
+	s.set(FieldPaneBorderStatus, origin, w.PaneBorderStatus, &s.Window.PaneBorderStatus)
+	s.set(FieldPaneBorderFormat, origin, w.PaneBorderFormat, &s.Window.PaneBorderFormat)
+	s.set(FieldWindowName, origin, w.WindowName, &s.Window.WindowName)
+	s.set(FieldPaneTitle, origin, w.PaneTitle, &s.Window.PaneTitle)
+	s.set(FieldReuseWindow, origin, w.ReuseWindow, &s.Window.ReuseWindow)
+	if w.MaxPanes != 0 {
+		s.Window.MaxPanes = w.MaxPanes
+		s.origins[FieldMaxPanes] = origin
+	}
+}
+
+// set stores v in dst when it is not blank.
+func (s *Settings) set(field string, origin Origin, v string, dst *string) {
+	if v = strings.TrimSpace(v); v == "" {
+		return
+	}
+	*dst = v
+	s.origins[field] = origin
+}
+
+// Vars returns the template variables of a connect to host with the ssh
+// settings s: the group is the outermost of in.Chain, and the meta the
+// host's [hosts.meta] over that group's [groups.meta].
+func Vars(in Input, host string, s sshcmd.Settings) tmpl.Vars {
+	group := ""
+	meta := make(map[string]string)
+	if len(in.Chain) > 0 {
+		group = in.Chain[0].Name
+		maps.Copy(meta, in.Chain[0].Meta)
+	}
+	maps.Copy(meta, hosts.HostInfo(in.Inventory, in.Sourced, host).Meta)
+	return tmpl.NewVars(host, s.User, s.Port, group, meta)
+}
+
+// Names expands the templates of a connect to host: the remote command of
+// s in place, and the window name and pane title of w. Names left unset by
+// w are returned empty.
+func Names(in Input, host string, s *sshcmd.Settings, w Window) (window, pane string, err error) {
+	v := Vars(in, host, *s)
+	if s.RemoteCommand, err = tmpl.Expand(FieldRemoteCommand, s.RemoteCommand, v); err != nil {
+		return "", "", err
+	}
+	if window, err = tmpl.Expand(FieldWindowName, w.WindowName, v); err != nil {
+		return "", "", err
+	}
+	if pane, err = tmpl.Expand(FieldPaneTitle, w.PaneTitle, v); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(window), strings.TrimSpace(pane), nil
+}
+
+// expandJump fills in the user and port of hops that name an inventory host
+// ([[hosts]] override or [[sources]] entry) and leave them out. A hop's own
+// jump list is not followed. ["none"] resolves to no hops.
+func expandJump(in Input, hops []string) []string {
+	if len(hops) == 0 || (len(hops) == 1 && strings.TrimSpace(hops[0]) == config.JumpNone) {
+		return nil
+	}
+	out := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		user, host, port := sshcmd.SplitJumpHop(hop)
+		name := hop
+		if i := strings.LastIndex(name, "@"); i >= 0 {
+			name = name[i+1:]
+		}
+		if hc, ok := sshcmd.FindHostConfig(in.Inventory.Hosts, name); ok {
+			if user == "" {
+				user = strings.TrimSpace(hc.User)
+			}
+			if port == 0 {
+				port = hc.Port
+			}
+		}
+		if sh, ok := hosts.FindSourced(in.Sourced, name); ok {
+			if user == "" {
+				user = sh.User
+			}
+			if port == 0 {
+				port = sh.Port
+			}
+		}
+		out = append(out, sshcmd.FormatJumpHop(user, host, port))
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package resolve
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+func TestResolvePrecedence(t *testing.T) {
+	in := Input{
+		Defaults: config.Defaults{User: "me", Port: 22, IdentityFile: "~/.ssh/id", Tmux: "auto", OpenMode: "auto", PaneSync: "on"},
+		Inventory: config.Inventory{Hosts: []config.Host{
+			{Host: "db1", User: "dba", ExtraArgs: []string{"-o", "A=B"}},
+		}},
+		Sourced: []sources.Host{{Name: "db1", Source: "cmdb", User: "cmdb-user", Port: 2200}},
+		Chain: []config.Group{
+			{Name: "prod", User: "deploy", Port: 2222, RemoteCommand: "uptime", OpenMode: "tmux-window"},
+			{Name: "prod-db", IdentityFile: "~/.ssh/db", PaneSync: "off"},
+		},
+		Flags: Flags{NoTmux: true},
+	}
+	got := Resolve(in, "db1")
+
+	wantSSH := sshcmd.Settings{User: "dba", Port: 2200, IdentityFile: "~/.ssh/db", ExtraArgs: []string{"-o", "A=B"}, RemoteCommand: "uptime"}
+	if !reflect.DeepEqual(got.SSH, wantSSH) {
+		t.Fatalf("got=%#v\nwant=%#v", got.SSH, wantSSH)
+	}
+	// pane_sync of the nested group does not apply: window settings come
+	// from the outermost group only.
+	wantWin := Window{Tmux: "never", OpenMode: "tmux-window", PaneSync: "on"}
+	if !reflect.DeepEqual(got.Window, wantWin) {
+		t.Fatalf("got=%#v\nwant=%#v", got.Window, wantWin)
+	}
+
+	origins := map[string]Origin{
+		FieldUser:          OriginHost,
+		FieldPort:          OriginSource("cmdb"),
+		FieldIdentityFile:  OriginGroup("prod-db"),
+		FieldExtraArgs:     OriginHost,
+		FieldRemoteCommand: OriginGroup("prod"),
+		FieldTmux:          OriginFlag,
+		FieldOpenMode:      OriginGroup("prod"),
+		FieldPaneSync:      OriginDefaults,
+		FieldPaneSplit:     "",
+	}
+	for field, want := range origins {
+		if got := got.Origin(field); got != want {
+			t.Fatalf("%s: origin=%q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestResolveBracketHostOverride(t *testing.T) {
+	in := Input{Inventory: config.Inventory{Hosts: []config.Host{{Host: "db1", User: "dba"}}}}
+	got := Resolve(in, "[db1]:2222")
+	if got.SSH.User != "dba" || got.Origin(FieldUser) != OriginHost {
+		t.Fatalf("got=%#v origin=%q", got.SSH, got.Origin(FieldUser))
+	}
+}
+
+func TestResolveBackend(t *testing.T) {
+	in := Input{
+		Defaults:  config.Defaults{Backend: "mosh"},
+		Inventory: config.Inventory{Hosts: []config.Host{{Host: "db1", Backend: "tsh"}}},
+		Chain:     []config.Group{{Name: "prod", Backend: "et"}},
+	}
+	if got := Resolve(in, "web1"); got.SSH.Backend != "et" || got.Origin(FieldBackend) != OriginGroup("prod") {
+		t.Fatalf("web1: backend=%q origin=%q", got.SSH.Backend, got.Origin(FieldBackend))
+	}
+	if got := Resolve(in, "db1"); got.SSH.Backend != "tsh" || got.Origin(FieldBackend) != OriginHost {
+		t.Fatalf("db1: backend=%q origin=%q", got.SSH.Backend, got.Origin(FieldBackend))
+	}
+}
+
+func TestFieldsOrder(t *testing.T) {
+	got := Resolve(Input{Defaults: config.Defaults{Port: 22}}, "")
+	fields := got.Fields()
+	if len(fields) != 18 || fields[0].Name != FieldUser || fields[1] != (Field{Name: FieldPort, Value: "22", Origin: OriginDefaults}) {
+		t.Fatalf("fields=%#v", fields)
+	}
+}
+
+func TestResolveJumpExpandsInventoryHops(t *testing.T) {
+	in := Input{
+		Defaults: config.Defaults{Jump: []string{"gw"}},
+		Inventory: config.Inventory{Hosts: []config.Host{
+			{Host: "bastion", User: "ops", Port: 2222},
+			{Host: "db1", Jump: []string{"bastion", "admin@[10.0.0.1]:22"}},
+			{Host: "local", Jump: []string{"none"}},
+		}},
+	}
+	got := Resolve(in, "db1")
+	if want := []string{"ops@bastion:2222", "admin@10.0.0.1"}; !reflect.DeepEqual(got.SSH.Jump, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got.SSH.Jump, want)
+	}
+	if got.Origin(FieldJump) != OriginHost {
+		t.Fatalf("origin=%q", got.Origin(FieldJump))
+	}
+	if got := Resolve(in, "web1").SSH.Jump; !reflect.DeepEqual(got, []string{"gw"}) {
+		t.Fatalf("defaults: got=%#v", got)
+	}
+	if got := Resolve(in, "local").SSH.Jump; got != nil {
+		t.Fatalf("none: got=%#v", got)
+	}
+}
+
+func TestNames(t *testing.T) {
+	in := Input{
+		Defaults: config.Defaults{Port: 22, PaneTitle: "{{.ShortHost}}:{{.Port}}"},
+		Inventory: config.Inventory{Hosts: []config.Host{
+			{Host: "web1.example.com", User: "ops", Meta: map[string]string{"service": "nginx"}},
+		}},
+		Chain: []config.Group{{Name: "prod", RemoteCommand: "journalctl -u {{.Meta.service}} -n {{.Meta.lines}}", WindowName: "{{.Group}}", Meta: map[string]string{"lines": "50", "service": "app"}}},
+	}
+	r := Resolve(in, "web1.example.com")
+	s := r.SSH
+	window, pane, err := Names(in, "web1.example.com", &s, r.Window)
+	if err != nil {
+		t.Fatalf("names: %v", err)
+	}
+	if s.RemoteCommand != "journalctl -u nginx -n 50" || window != "prod" || pane != "web1:22" {
+		t.Fatalf("remote=%q window=%q pane=%q", s.RemoteCommand, window, pane)
+	}
+
+	in.Chain[0].RemoteCommand = "cat {{.Meta.missing}}"
+	s = Resolve(in, "web1.example.com").SSH
+	if _, _, err := Names(in, "web1.example.com", &s, r.Window); err == nil {
+		t.Fatalf("expected missing meta error")
+	}
+}
@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxDiffCells bounds the LCS table of DiffLines; larger inputs are shown as
+// a full replacement.
+const maxDiffCells = 4 << 20
+
+// Bucket is a set of hosts whose command finished with the same status,
+// exit code and output.
+type Bucket struct {
+	Hosts    []string
+	Status   Status
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// Output is the bucket's stdout followed by its stderr.
+func (b Bucket) Output() string {
+	if b.Stderr == "" {
+		return b.Stdout
+	}
+	if b.Stdout != "" && !strings.HasSuffix(b.Stdout, "\n") {
+		return b.Stdout + "\n" + b.Stderr
+	}
+	return b.Stdout + b.Stderr
+}
+
+// Buckets groups the finished results by identical outcome, largest bucket
+// first; buckets of the same size keep the order of their first host.
+// Results that have not finished or were skipped are left out.
+func Buckets(results []Result) []Bucket {
+	type key struct {
+		status         Status
+		exitCode       int
+		stdout, stderr string
+	}
+	index := make(map[key]int)
+	var out []Bucket
+	for _, r := range results {
+		if !r.Status.Done() || r.Status == Skipped {
+			continue
+		}
+		k := key{r.Status, r.ExitCode, r.Stdout, r.Stderr}
+		i, ok := index[k]
+		if !ok {
+			i = len(out)
+			index[k] = i
+			out = append(out, Bucket{Status: r.Status, ExitCode: r.ExitCode, Stdout: r.Stdout, Stderr: r.Stderr})
+		}
+		out[i].Hosts = append(out[i].Hosts, r.Host)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i].Hosts) > len(out[j].Hosts) })
+	return out
+}
+
+// DiffOp marks a line of a diff.
+type DiffOp byte
+
+const (
+	DiffSame   DiffOp = ' '
+	DiffRemove DiffOp = '-' // only in the old text
+	DiffAdd    DiffOp = '+' // only in the new text
+)
+
+// DiffLine is one line of a line diff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// DiffLines returns a line diff turning from into to (longest common
+// subsequence, removals before additions).
+func DiffLines(from, to string) []DiffLine {
+	a, b := splitLines(from), splitLines(to)
+	if len(a)*len(b) > maxDiffCells {
+		out := make([]DiffLine, 0, len(a)+len(b))
+		for _, l := range a {
+			out = append(out, DiffLine{DiffRemove, l})
+		}
+		for _, l := range b {
+			out = append(out, DiffLine{DiffAdd, l})
+		}
+		return out
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, DiffLine{DiffSame, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{DiffRemove, a[i]})
+			i++
+		default:
+			out = append(out, DiffLine{DiffAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, DiffLine{DiffRemove, a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, DiffLine{DiffAdd, b[j]})
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuckets(t *testing.T) {
+	results := []Result{
+		{Host: "a", Status: OK, ExitCode: 0, Stdout: "5.15\n"},
+		{Host: "b", Status: OK, ExitCode: 0, Stdout: "6.1\n"},
+		{Host: "c", Status: OK, ExitCode: 0, Stdout: "5.15\n"},
+		{Host: "d", Status: Running, ExitCode: -1},
+		{Host: "e", Status: Failed, ExitCode: 1, Stdout: "5.15\n"},
+		{Host: "f", Status: OK, ExitCode: 0, Stdout: "6.1\n"},
+		{Host: "g", Status: OK, ExitCode: 0, Stdout: "5.15\n"},
+	}
+	var got [][]string
+	for _, b := range Buckets(results) {
+		got = append(got, b.Hosts)
+	}
+	want := [][]string{{"a", "c", "g"}, {"b", "f"}, {"e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if b := (Bucket{Stdout: "out", Stderr: "err\n"}); b.Output() != "out\nerr\n" {
+		t.Fatalf("Output: %q", b.Output())
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("a\nb\nc\nd\n", "a\nc\nx\nd\n")
+	want := []DiffLine{{DiffSame, "a"}, {DiffRemove, "b"}, {DiffSame, "c"}, {DiffAdd, "x"}, {DiffSame, "d"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if got := DiffLines("", "x"); !reflect.DeepEqual(got, []DiffLine{{DiffAdd, "x"}}) {
+		t.Fatalf("empty from: %#v", got)
+	}
+	if got := DiffLines("same\n", "same"); !reflect.DeepEqual(got, []DiffLine{{DiffSame, "same"}}) {
+		t.Fatalf("trailing newline: %#v", got)
+	}
+}
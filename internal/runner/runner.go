@@ -0,0 +1,278 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+// maxOutput caps the captured stdout and stderr of one host.
+const maxOutput = 1 << 20
+
+// Status is the state of one host's run.
+type Status int
+
+const (
+	Pending     Status = iota
+	Running            // ssh started
+	OK                 // exit status 0
+	Failed             // non-zero exit status of the command
+	Unreachable        // ssh itself failed (exit status 255 or not started)
+	TimedOut
+	Canceled
+	Skipped // not run: the run halted or was stopped before its batch
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case OK:
+		return "ok"
+	case Failed:
+		return "failed"
+	case Unreachable:
+		return "unreachable"
+	case TimedOut:
+		return "timeout"
+	case Canceled:
+		return "canceled"
+	case Skipped:
+		return "skipped"
+	default:
+		return "pending"
+	}
+}
+
+// MarshalText encodes the status as its name in JSON reports.
+func (s Status) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// Done reports whether the host has finished, successfully or not.
+func (s Status) Done() bool { return s >= OK }
+
+// Target is one host and the full ssh command to run for it.
+type Target struct {
+	Host string
+	Argv []string
+}
+
+// Result is the outcome of one target.
+type Result struct {
+	Host     string
+	Status   Status
+	ExitCode int // -1 when the command has no exit status
+	Stdout   string
+	Stderr   string
+	Err      string // why ssh did not run or was stopped
+	Duration time.Duration
+}
+
+// Failed reports whether the host ran and did not succeed.
+func (s Status) Failed() bool { return s.Done() && s != OK && s != Skipped }
+
+// Options control a run.
+type Options struct {
+	Parallel int           // hosts running at once; <= 0 means 1
+	Timeout  time.Duration // per host; 0 means none
+
+	// Batch > 0 runs the targets in order in batches of Batch hosts, all
+	// hosts of a batch at once (Parallel is ignored), each batch finishing
+	// before the next starts.
+	Batch int
+	// HaltOnError skips the remaining batches once a host has failed.
+	HaltOnError bool
+	// BeforeBatch, when set, is called before every batch but the first
+	// with the target range of the batch; returning false skips the
+	// remaining batches.
+	BeforeBatch func(start, end int) bool
+}
+
+// Command returns the non-interactive ssh command that runs command on
+// host: no TTY, and no password prompts (BatchMode). Backends that only
+// run terminal sessions (mosh, et) fall back to ssh. Containers and pods
+// run it through `docker exec` or `kubectl exec` without a TTY.
+func Command(host string, s sshcmd.Settings, command string) ([]string, error) {
+	if t, ok := container.Parse(host); ok {
+		return t.Command(false, command), nil
+	}
+	s, n := sshcmd.ForBatch(s)
+	s.RemoteCommand = command
+	cmd, err := sshcmd.BuildCommand(host, s)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(cmd[:n:n], "-T", "-o", "BatchMode=yes"), cmd[n:]...), nil
+}
+
+// CopyCommand returns the non-interactive scp command that runs t on host
+// (BatchMode, so a password prompt fails instead of hanging).
+func CopyCommand(host string, s sshcmd.Settings, t sshcmd.Transfer) ([]string, error) {
+	cmd, err := sshcmd.SCPCommand(host, s, t)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(cmd[:1:1], "-o", "BatchMode=yes"), cmd[1:]...), nil
+}
+
+// Run runs every target with at most opts.Parallel at once, or in batches
+// (see Options), and returns the results in target order. update, when set,
+// is called with the index and state of a target when it starts and when it
+// finishes; calls are serialized. Canceling ctx stops running hosts and
+// cancels pending ones.
+func Run(ctx context.Context, targets []Target, opts Options, update func(i int, r Result)) []Result {
+	results := make([]Result, len(targets))
+	for i, t := range targets {
+		results[i] = Result{Host: t.Host, ExitCode: -1}
+	}
+
+	var mu sync.Mutex
+	report := func(i int, r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = r
+		if update != nil {
+			update(i, r)
+		}
+	}
+
+	// runRange runs targets[start:end] with at most parallel at once.
+	runRange := func(start, end, parallel int) {
+		sem := make(chan struct{}, parallel)
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			t := targets[i]
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				report(i, Result{Host: t.Host, Status: Canceled, ExitCode: -1, Err: "canceled"})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				report(i, Result{Host: t.Host, Status: Running, ExitCode: -1})
+				report(i, runOne(ctx, t, opts.Timeout))
+			}()
+		}
+		wg.Wait()
+	}
+
+	if opts.Batch <= 0 {
+		runRange(0, len(targets), max(1, opts.Parallel))
+		return results
+	}
+	for start := 0; start < len(targets); start += opts.Batch {
+		end := min(start+opts.Batch, len(targets))
+		if start > 0 && ctx.Err() == nil {
+			reason := ""
+			if failed := firstFailed(results[:start]); opts.HaltOnError && failed != "" {
+				reason = "halted: " + failed + " failed"
+			} else if opts.BeforeBatch != nil && !opts.BeforeBatch(start, end) {
+				reason = "stopped before this batch"
+			}
+			if reason != "" {
+				for i := start; i < len(targets); i++ {
+					report(i, Result{Host: targets[i].Host, Status: Skipped, ExitCode: -1, Err: reason})
+				}
+				break
+			}
+		}
+		runRange(start, end, end-start)
+	}
+	return results
+}
+
+// firstFailed returns the host of the first failed result, or "".
+func firstFailed(results []Result) string {
+	for _, r := range results {
+		if r.Status.Failed() {
+			return r.Host
+		}
+	}
+	return ""
+}
+
+func runOne(ctx context.Context, t Target, timeout time.Duration) Result {
+	r := Result{Host: t.Host, ExitCode: -1}
+	if len(t.Argv) == 0 {
+		r.Status = Unreachable
+		r.Err = "empty command"
+		return r
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// #nosec G204 -- argv is an ssh command built from the user's own config.
+	cmd := exec.CommandContext(ctx, t.Argv[0], t.Argv[1:]...)
+	// Do not hang on remote children that keep the output pipes open.
+	cmd.WaitDelay = 2 * time.Second
+	var stdout, stderr cappedBuffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	r.Duration = time.Since(start)
+	r.Stdout = stdout.String()
+	r.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		r.Status = TimedOut
+		r.Err = "timed out after " + timeout.String()
+	case ctx.Err() != nil:
+		r.Status = Canceled
+		r.Err = "canceled"
+	case err == nil:
+		r.Status = OK
+		r.ExitCode = 0
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		r.ExitCode = exitErr.ExitCode()
+		r.Status = Failed
+		if r.ExitCode == 255 {
+			// ssh reports its own errors (connect, auth) as 255.
+			r.Status = Unreachable
+		}
+	default:
+		r.Status = Unreachable
+		r.Err = err.Error()
+	}
+	return r
+}
+
+// cappedBuffer keeps the first maxOutput bytes written to it.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[output truncated]\n"
+	}
+	return b.buf.String()
+}
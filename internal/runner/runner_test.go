@@ -0,0 +1,182 @@
+package runner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+func sh(host, script string) Target {
+	return Target{Host: host, Argv: []string{"sh", "-c", script}}
+}
+
+func TestRunCollectsResultsInOrder(t *testing.T) {
+	targets := []Target{
+		sh("a", "sleep 0.2; echo out-a"),
+		sh("b", "echo err-b >&2; exit 3"),
+		sh("c", "exit 255"),
+		{Host: "d", Argv: []string{"/nonexistent/ssh"}},
+	}
+	got := Run(context.Background(), targets, Options{Parallel: 4}, nil)
+
+	var summary []string
+	for _, r := range got {
+		summary = append(summary, r.Host+":"+r.Status.String())
+	}
+	want := []string{"a:ok", "b:failed", "c:unreachable", "d:unreachable"}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("got=%#v\nwant=%#v", summary, want)
+	}
+	if got[0].Stdout != "out-a\n" || got[0].ExitCode != 0 {
+		t.Fatalf("a: %#v", got[0])
+	}
+	if got[1].Stderr != "err-b\n" || got[1].ExitCode != 3 {
+		t.Fatalf("b: %#v", got[1])
+	}
+	if got[2].ExitCode != 255 {
+		t.Fatalf("c: %#v", got[2])
+	}
+	if got[3].ExitCode != -1 || got[3].Err == "" {
+		t.Fatalf("d: %#v", got[3])
+	}
+}
+
+func TestRunLimitsParallelism(t *testing.T) {
+	var targets []Target
+	for _, h := range []string{"a", "b", "c", "d", "e", "f"} {
+		targets = append(targets, sh(h, "sleep 0.1"))
+	}
+	var mu sync.Mutex
+	running, peak := 0, 0
+	update := func(_ int, r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Status == Running {
+			running++
+		} else {
+			running--
+		}
+		peak = max(peak, running)
+	}
+	Run(context.Background(), targets, Options{Parallel: 2}, update)
+	if peak != 2 {
+		t.Fatalf("peak=%d want 2", peak)
+	}
+}
+
+func TestRunTimeoutAndCancel(t *testing.T) {
+	got := Run(context.Background(), []Target{sh("slow", "sleep 5")}, Options{Timeout: 200 * time.Millisecond}, nil)
+	if got[0].Status != TimedOut || got[0].Duration > 3*time.Second {
+		t.Fatalf("timeout: %#v", got[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	update := func(i int, r Result) {
+		if i == 0 && r.Status == Running {
+			cancel()
+		}
+	}
+	got = Run(ctx, []Target{sh("a", "sleep 5"), sh("b", "true")}, Options{Parallel: 1}, update)
+	if got[0].Status != Canceled || got[1].Status != Canceled {
+		t.Fatalf("cancel: %#v", got)
+	}
+}
+
+func TestRunCapsOutput(t *testing.T) {
+	got := Run(context.Background(), []Target{sh("big", "head -c 2000000 /dev/zero")}, Options{}, nil)
+	if len(got[0].Stdout) > maxOutput+100 || !strings.HasSuffix(got[0].Stdout, "[output truncated]\n") {
+		t.Fatalf("len=%d", len(got[0].Stdout))
+	}
+}
+
+func TestCommand(t *testing.T) {
+	got, err := Command("web1", sshcmd.Settings{User: "ops", RemoteCommand: "ignored"}, "uptime")
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	want := []string{"ssh", "-T", "-o", "BatchMode=yes", "ops@web1", "sh -c 'uptime'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	// mosh needs a terminal and falls back to ssh; tsh keeps its program.
+	if got, _ := Command("web1", sshcmd.Settings{Backend: "mosh"}, "uptime"); got[0] != "ssh" {
+		t.Fatalf("mosh: got=%#v", got)
+	}
+	got, _ = Command("web1", sshcmd.Settings{Backend: "tsh"}, "uptime")
+	want = []string{"tsh", "ssh", "-T", "-o", "BatchMode=yes", "web1", "sh -c 'uptime'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tsh: got=%#v\nwant=%#v", got, want)
+	}
+}
+
+func TestCopyCommand(t *testing.T) {
+	got, err := CopyCommand("web1", sshcmd.Settings{User: "ops"}, sshcmd.Transfer{Local: []string{"app.conf"}, Remote: "/etc/"})
+	if err != nil {
+		t.Fatalf("CopyCommand: %v", err)
+	}
+	want := []string{"scp", "-o", "BatchMode=yes", "-r", "app.conf", "ops@web1:/etc/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+}
+
+func TestRunBatches(t *testing.T) {
+	var targets []Target
+	for _, h := range []string{"a", "b", "c", "d", "e"} {
+		targets = append(targets, sh(h, "sleep 0.05"))
+	}
+	var mu sync.Mutex
+	var events []string
+	update := func(i int, r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Status == Running {
+			events = append(events, "start "+r.Host)
+		}
+	}
+	var batches [][2]int
+	before := func(start, end int) bool {
+		batches = append(batches, [2]int{start, end})
+		events = append(events, "batch")
+		return true
+	}
+	got := Run(context.Background(), targets, Options{Batch: 2, BeforeBatch: before}, update)
+	for _, r := range got {
+		if r.Status != OK {
+			t.Fatalf("%s: %#v", r.Host, r)
+		}
+	}
+	if want := [][2]int{{2, 4}, {4, 5}}; !reflect.DeepEqual(batches, want) {
+		t.Fatalf("batches: got=%v want=%v", batches, want)
+	}
+	// Each batch starts only after the previous one was confirmed.
+	if events[2] != "batch" || events[5] != "batch" || events[6] != "start e" {
+		t.Fatalf("events: %v", events)
+	}
+}
+
+func TestRunHaltOnError(t *testing.T) {
+	targets := []Target{sh("a", "true"), sh("b", "exit 1"), sh("c", "true"), sh("d", "true")}
+	got := Run(context.Background(), targets, Options{Batch: 1, HaltOnError: true}, nil)
+	var summary []string
+	for _, r := range got {
+		summary = append(summary, r.Host+":"+r.Status.String())
+	}
+	want := []string{"a:ok", "b:failed", "c:skipped", "d:skipped"}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("got=%#v\nwant=%#v", summary, want)
+	}
+	if got[2].Err != "halted: b failed" {
+		t.Fatalf("Err: %q", got[2].Err)
+	}
+
+	got = Run(context.Background(), targets[:3], Options{Batch: 2, BeforeBatch: func(int, int) bool { return false }}, nil)
+	if got[1].Status != Failed || got[2].Status != Skipped {
+		t.Fatalf("declined: %#v", got)
+	}
+}
@@ -0,0 +1,260 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+const (
+	defaultTTL     = 5 * time.Minute
+	defaultTimeout = 30 * time.Second
+)
+
+// Host is one entry of a source's JSON document.
+type Host struct {
+	Name   string   `json:"name"`
+	User   string   `json:"user,omitempty"`
+	Port   int      `json:"port,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	// Source is the name of the [[sources]] entry the host came from.
+	Source string `json:"-"`
+}
+
+// Override returns the host's user/port and tags as a per-host override.
+func (h Host) Override() config.Host {
+	return config.Host{Host: h.Name, User: h.User, Port: h.Port, Tags: h.Tags}
+}
+
+// Error is a failure of one source.
+type Error struct {
+	Source string
+	Err    error
+}
+
+func (e Error) Error() string { return "source " + e.Source + ": " + e.Err.Error() }
+
+func (e Error) Unwrap() error { return e.Err }
+
+// Parse decodes a source document. Both {"hosts": [...]} and a bare array
+// of hosts are accepted. Entries without a name are dropped.
+func Parse(data []byte) ([]Host, error) {
+	data = bytes.TrimSpace(data)
+	var list []Host
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+	} else {
+		var doc struct {
+			Hosts []Host `json:"hosts"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		list = doc.Hosts
+	}
+
+	out := make([]Host, 0, len(list))
+	for _, h := range list {
+		h.Name = strings.TrimSpace(h.Name)
+		if h.Name == "" {
+			continue
+		}
+		if h.Port < 0 || h.Port > 65535 {
+			return nil, fmt.Errorf("host %q: invalid port %d", h.Name, h.Port)
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+type cacheFile struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Hosts     []Host    `json:"hosts"`
+}
+
+// Load runs every source (or reads its cache when fresh and refresh is
+// false) and returns all hosts in source order. A failing source falls back
+// to its stale cache, if any, and is reported in the error list.
+func Load(srcs []config.Source, cacheDir string, refresh bool) ([]Host, []Error) {
+	var out []Host
+	var errs []Error
+	for _, src := range srcs {
+		hosts, err := loadOne(src, cacheDir, refresh)
+		if err != nil {
+			errs = append(errs, Error{Source: src.Name, Err: err})
+		}
+		for i := range hosts {
+			hosts[i].Source = src.Name
+		}
+		out = append(out, hosts...)
+	}
+	return out, errs
+}
+
+// Cached returns the cached hosts of every source, however old, without
+// running any command. Sources without a readable cache are skipped.
+func Cached(srcs []config.Source, cacheDir string) []Host {
+	var out []Host
+	for _, src := range srcs {
+		if config.ValidateGroupName(src.Name) != nil {
+			continue
+		}
+		cached, err := readCache(cachePathOf(cacheDir, src.Name))
+		if err != nil {
+			continue
+		}
+		for i := range cached.Hosts {
+			cached.Hosts[i].Source = src.Name
+		}
+		out = append(out, cached.Hosts...)
+	}
+	return out
+}
+
+func loadOne(src config.Source, cacheDir string, refresh bool) ([]Host, error) {
+	if err := config.ValidateGroupName(src.Name); err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+	if len(src.Command) == 0 || strings.TrimSpace(src.Command[0]) == "" {
+		return nil, errors.New("command required")
+	}
+	ttl, err := parseDuration(src.TTL, defaultTTL)
+	if err != nil {
+		return nil, fmt.Errorf("ttl: %w", err)
+	}
+	timeout, err := parseDuration(src.Timeout, defaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("timeout: %w", err)
+	}
+
+	cachePath := cachePathOf(cacheDir, src.Name)
+	cached, cacheErr := readCache(cachePath)
+	if !refresh && cacheErr == nil && ttl > 0 && time.Since(cached.FetchedAt) < ttl {
+		return cached.Hosts, nil
+	}
+
+	hosts, err := run(src.Command, timeout)
+	if err != nil {
+		if cacheErr == nil {
+			return cached.Hosts, fmt.Errorf("%w (using cache from %s)", err, cached.FetchedAt.Format(time.RFC3339))
+		}
+		return nil, err
+	}
+	if ttl > 0 && cachePath != "" {
+		_ = writeCache(cachePath, cacheFile{FetchedAt: time.Now(), Hosts: hosts})
+	}
+	return hosts, nil
+}
+
+func run(argv []string, timeout time.Duration) ([]Host, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// #nosec G204 -- the command comes from the user's own config.toml.
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, firstLine(msg))
+		}
+		return nil, err
+	}
+	hosts, err := Parse(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return hosts, nil
+}
+
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def, nil
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration %q", s)
+	}
+	return d, nil
+}
+
+// cachePathOf is the cache file of the source name, empty without a
+// cache dir.
+func cachePathOf(cacheDir, name string) string {
+	if cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(cacheDir, name+".json")
+}
+
+func readCache(path string) (cacheFile, error) {
+	var c cacheFile
+	if path == "" {
+		return c, os.ErrNotExist
+	}
+	// #nosec G304 -- path is derived from the XDG cache dir and a validated name.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+func writeCache(path string, c cacheFile) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".source.*")
+	if err != nil {
+		return err
+	}
+	tmpPath := filepath.Clean(tmp.Name())
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
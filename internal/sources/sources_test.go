@@ -0,0 +1,95 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse([]byte(`{"hosts":[{"name":"web1","user":"deploy","port":2222,"tags":["web"],"groups":["prod"]},{"name":" "}]}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Host{{Name: "web1", User: "deploy", Port: 2222, Tags: []string{"web"}, Groups: []string{"prod"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+
+	got, err = Parse([]byte(` [{"name":"db1"}] `))
+	if err != nil || len(got) != 1 || got[0].Name != "db1" {
+		t.Fatalf("bare array: got=%#v err=%v", got, err)
+	}
+
+	if _, err := Parse([]byte(`{"hosts":[{"name":"x","port":70000}]}`)); err == nil {
+		t.Fatalf("expected invalid port error")
+	}
+}
+
+func TestLoadCachesAndFallsBack(t *testing.T) {
+	d := t.TempDir()
+	marker := filepath.Join(d, "runs")
+	src := config.Source{
+		Name:    "cmdb",
+		Command: []string{"sh", "-c", `echo x >> "$0"; echo '[{"name":"web1"}]'`, marker},
+		TTL:     "1h",
+	}
+
+	for i := 0; i < 2; i++ {
+		hosts, errs := Load([]config.Source{src}, d, false)
+		if len(errs) != 0 {
+			t.Fatalf("errs=%v", errs)
+		}
+		if len(hosts) != 1 || hosts[0].Name != "web1" || hosts[0].Source != "cmdb" {
+			t.Fatalf("hosts=%#v", hosts)
+		}
+	}
+	runs, _ := os.ReadFile(marker)
+	if n := strings.Count(string(runs), "x"); n != 1 {
+		t.Fatalf("command ran %d times, want 1 (second load should hit the cache)", n)
+	}
+
+	// Refresh with a failing command falls back to the stale cache.
+	src.Command = []string{"sh", "-c", "echo boom >&2; exit 3"}
+	hosts, errs := Load([]config.Source{src}, d, true)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "source cmdb: ") || !strings.Contains(errs[0].Error(), "boom") {
+		t.Fatalf("errs=%v", errs)
+	}
+	if len(hosts) != 1 || hosts[0].Name != "web1" {
+		t.Fatalf("hosts=%#v, want cached web1", hosts)
+	}
+}
+
+func TestCachedDoesNotRunCommands(t *testing.T) {
+	d := t.TempDir()
+	marker := filepath.Join(d, "runs")
+	src := config.Source{
+		Name:    "cmdb",
+		Command: []string{"sh", "-c", `echo x >> "$0"; echo '[{"name":"web1"}]'`, marker},
+		TTL:     "1ns",
+	}
+	if got := Cached([]config.Source{src}, d); len(got) != 0 {
+		t.Fatalf("no cache yet: got %#v", got)
+	}
+	if err := writeCache(cachePathOf(d, "cmdb"), cacheFile{Hosts: []Host{{Name: "web1"}}}); err != nil {
+		t.Fatalf("writeCache: %v", err)
+	}
+	got := Cached([]config.Source{src}, d)
+	if len(got) != 1 || got[0].Name != "web1" || got[0].Source != "cmdb" {
+		t.Fatalf("got %#v, want the expired cache", got)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("command ran: %v", err)
+	}
+}
+
+func TestLoadInvalidSource(t *testing.T) {
+	_, errs := Load([]config.Source{{Name: "bad name", Command: []string{"true"}}, {Name: "empty"}}, t.TempDir(), false)
+	if len(errs) != 2 {
+		t.Fatalf("errs=%v, want 2", errs)
+	}
+}
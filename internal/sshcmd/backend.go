@@ -0,0 +1,252 @@
+package sshcmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+// Backend is a connection client: the program that is run and how Settings
+// map to its arguments.
+type Backend struct {
+	Name    string
+	Program []string // argv prefix, e.g. ["tsh", "ssh"]
+	// Interactive marks clients that only run terminal sessions; exec
+	// falls back to ssh for them.
+	Interactive bool
+	// Proxied marks clients that reach hosts through a proxy, so the ssh
+	// port of a host cannot be probed directly.
+	Proxied bool
+
+	// args returns the arguments after Program. target is [user@]host and
+	// port the ssh port (0 or 22 for the default).
+	args func(target string, port int, s Settings) []string
+	// check, when set, rejects settings the client cannot honour.
+	check func(s Settings) error
+	// ignores, when set, names the settings of s args drops.
+	ignores func(s Settings) []string
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		config.BackendSSH:  {Name: config.BackendSSH, Program: []string{"ssh"}, args: sshArgs},
+		config.BackendMosh: {Name: config.BackendMosh, Program: []string{"mosh"}, Interactive: true, args: moshArgs},
+		config.BackendET:   {Name: config.BackendET, Program: []string{"et"}, Interactive: true, args: etArgs},
+		config.BackendTsh:  {Name: config.BackendTsh, Program: []string{"tsh", "ssh"}, Proxied: true, args: tshArgs, check: tshCheck, ignores: tshIgnores},
+	}
+)
+
+// LookupBackend returns the backend registered as name; empty means ssh.
+func LookupBackend(name string) (Backend, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = config.BackendSSH
+	}
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	b, ok := backends[name]
+	return b, ok
+}
+
+// BackendNames returns the built-in backends in display order followed by
+// the registered wrappers, sorted.
+func BackendNames() []string {
+	out := append([]string(nil), config.BuiltinBackends...)
+	var custom []string
+	backendsMu.RLock()
+	for name := range backends {
+		if !isBuiltinBackend(name) {
+			custom = append(custom, name)
+		}
+	}
+	backendsMu.RUnlock()
+	sort.Strings(custom)
+	return append(out, custom...)
+}
+
+// RegisterBackends adds the [[backends]] wrappers of config.toml to the
+// registry. Each runs its command with the arguments of the built-in
+// backend it is like.
+func RegisterBackends(defs []config.Backend) error {
+	for _, d := range defs {
+		like, ok := LookupBackend(d.Like)
+		if !ok || !isBuiltinBackend(like.Name) {
+			return fmt.Errorf("backend %q: like %q is not a built-in backend", d.Name, d.Like)
+		}
+		b := like
+		b.Name = d.Name
+		b.Program = append([]string(nil), d.Command...)
+		backendsMu.Lock()
+		backends[d.Name] = b
+		backendsMu.Unlock()
+	}
+	return nil
+}
+
+// Ignored names the settings of s that its backend does not pass on, for
+// explain to warn about.
+func Ignored(s Settings) []string {
+	b, ok := LookupBackend(s.Backend)
+	if !ok || b.ignores == nil {
+		return nil
+	}
+	return b.ignores(s)
+}
+
+// ForBatch returns s for a command run without a terminal (exec, tunnels):
+// interactive backends fall back to ssh. n is the length of the program
+// prefix of the backend, after which ssh flags such as -T can be inserted.
+func ForBatch(s Settings) (out Settings, n int) {
+	b, ok := LookupBackend(s.Backend)
+	if !ok {
+		return s, 1
+	}
+	if b.Interactive {
+		s.Backend = config.BackendSSH
+		return s, 1
+	}
+	return s, len(b.Program)
+}
+
+func isBuiltinBackend(name string) bool {
+	for _, n := range config.BuiltinBackends {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTTYFlag reports whether args already request a tty.
+func hasTTYFlag(args []string) bool {
+	for _, a := range args {
+		if a == "-t" || a == "-tt" {
+			return true
+		}
+	}
+	return false
+}
+
+// sshOptions returns the ssh flags of s other than the target: identity,
+// port, jump hosts, extra args and the tty flag.
+func sshOptions(port int, s Settings) []string {
+	var out []string
+	if s.IdentityFile != "" {
+		out = append(out, "-i", s.IdentityFile)
+	}
+	if port != 0 && port != 22 {
+		out = append(out, "-p", strconv.Itoa(port))
+	}
+	if j := jumpArg(s.Jump); j != "" {
+		out = append(out, "-J", j)
+	}
+	out = append(out, s.ExtraArgs...)
+	if s.ForceTTY && !hasTTYFlag(s.ExtraArgs) {
+		out = append(out, "-t")
+	}
+	return out
+}
+
+// sshArgs: ssh [options] target ["sh -c 'cmd'"].
+func sshArgs(target string, port int, s Settings) []string {
+	out := append(sshOptions(port, s), target)
+	if rc := strings.TrimSpace(s.RemoteCommand); rc != "" {
+		// ssh executes the remote command through a shell. If we pass args like
+		// ["sh","-c","ls -lah"], ssh will serialize them into a string without
+		// preserving argv boundaries, and the remote shell will split the script.
+		// To keep the script intact, pass a single shell command string.
+		out = append(out, "sh -c "+shellQuotePOSIX(rc))
+	}
+	return out
+}
+
+// moshArgs: mosh [--ssh="ssh options"] target [-- sh -c cmd]. mosh always
+// runs in a terminal, so the tty flag is dropped; mosh-server executes the
+// command argv as is.
+func moshArgs(target string, port int, s Settings) []string {
+	s.ForceTTY = false
+	var out []string
+	if opts := sshOptions(port, s); len(opts) > 0 {
+		words := []string{"ssh"}
+		for _, o := range opts {
+			words = append(words, shellWord(o))
+		}
+		out = append(out, "--ssh="+strings.Join(words, " "))
+	}
+	out = append(out, target)
+	if rc := strings.TrimSpace(s.RemoteCommand); rc != "" {
+		out = append(out, "--", "sh", "-c", rc)
+	}
+	return out
+}
+
+// etArgs: et [--ssh-option ...] [extra args] target [-c cmd]. The
+// identity, ssh port and jump hosts only apply to the ssh handshake that
+// starts the session; et's own flags (such as its port) go in extra_args.
+func etArgs(target string, port int, s Settings) []string {
+	var out []string
+	if s.IdentityFile != "" {
+		out = append(out, "--ssh-option", "IdentityFile="+s.IdentityFile)
+	}
+	if port != 0 && port != 22 {
+		out = append(out, "--ssh-option", "Port="+strconv.Itoa(port))
+	}
+	if j := jumpArg(s.Jump); j != "" {
+		out = append(out, "--ssh-option", "ProxyJump="+j)
+	}
+	out = append(out, s.ExtraArgs...)
+	out = append(out, target)
+	if rc := strings.TrimSpace(s.RemoteCommand); rc != "" {
+		out = append(out, "-c", rc)
+	}
+	return out
+}
+
+// tshArgs: tsh ssh [--port P] [extra args] [-t] target ["sh -c 'cmd'"].
+// tsh authenticates with the Teleport login, so the identity file is
+// dropped; extra_args are tsh flags.
+func tshArgs(target string, port int, s Settings) []string {
+	var out []string
+	if port != 0 && port != 22 {
+		out = append(out, "--port", strconv.Itoa(port))
+	}
+	out = append(out, s.ExtraArgs...)
+	if s.ForceTTY && !hasTTYFlag(s.ExtraArgs) {
+		out = append(out, "-t")
+	}
+	out = append(out, target)
+	if rc := strings.TrimSpace(s.RemoteCommand); rc != "" {
+		out = append(out, "sh -c "+shellQuotePOSIX(rc))
+	}
+	return out
+}
+
+// tshCheck rejects jump hosts: tsh reaches hosts through the Teleport proxy
+// and its -J is not OpenSSH's ProxyJump.
+func tshCheck(s Settings) error {
+	if jumpArg(s.Jump) != "" {
+		return fmt.Errorf("backend tsh does not support jump hosts: set jump = %q", config.JumpNone)
+	}
+	return nil
+}
+
+func tshIgnores(s Settings) []string {
+	if s.IdentityFile != "" {
+		return []string{"identity_file"}
+	}
+	return nil
+}
+
+// shellWord quotes s for a shell unless it only holds characters that need
+// no quoting.
+func shellWord(s string) string {
+	if s == "" || strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,:/@%+~") != "" {
+		return shellQuotePOSIX(s)
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package sshcmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+func TestBuildCommandBackends(t *testing.T) {
+	s := Settings{User: "me", Port: 2222, IdentityFile: "~/.ssh/id", Jump: []string{"gw"}, RemoteCommand: "uptime"}
+	tests := []struct {
+		backend string
+		want    []string
+	}{
+		{"", []string{"ssh", "-i", "~/.ssh/id", "-p", "2222", "-J", "gw", "me@db1", "sh -c 'uptime'"}},
+		{"mosh", []string{"mosh", "--ssh=ssh -i ~/.ssh/id -p 2222 -J gw", "me@db1", "--", "sh", "-c", "uptime"}},
+		{"et", []string{"et", "--ssh-option", "IdentityFile=~/.ssh/id", "--ssh-option", "Port=2222", "--ssh-option", "ProxyJump=gw", "me@db1", "-c", "uptime"}},
+	}
+	for _, tt := range tests {
+		s.Backend = tt.backend
+		got, err := BuildCommand("db1", s)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.backend, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: got=%#v, want %#v", tt.backend, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCommandTsh(t *testing.T) {
+	s := Settings{Backend: "tsh", User: "me", Port: 2222, IdentityFile: "~/.ssh/id", ExtraArgs: []string{"--cluster", "prod"}, RemoteCommand: "uptime"}
+	got, err := BuildCommand("db1", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"tsh", "ssh", "--port", "2222", "--cluster", "prod", "me@db1", "sh -c 'uptime'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+	if got := Ignored(s); !reflect.DeepEqual(got, []string{"identity_file"}) {
+		t.Fatalf("Ignored = %q", got)
+	}
+
+	s.Jump = []string{"gw"}
+	if _, err := BuildCommand("db1", s); err == nil {
+		t.Fatalf("jump host: want error")
+	}
+	s.Jump = []string{config.JumpNone}
+	if _, err := BuildCommand("db1", s); err != nil {
+		t.Fatalf("jump none: %v", err)
+	}
+}
+
+func TestBuildCommandForceTTY(t *testing.T) {
+	got, _ := BuildCommand("db1", Settings{ForceTTY: true})
+	if want := []string{"ssh", "-t", "db1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+	got, _ = BuildCommand("db1", Settings{ForceTTY: true, ExtraArgs: []string{"-tt"}})
+	if want := []string{"ssh", "-tt", "db1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+	got, _ = BuildCommand("db1", Settings{ForceTTY: true, Backend: "mosh", ExtraArgs: []string{"-o", "A=b c"}})
+	if want := []string{"mosh", "--ssh=ssh -o 'A=b c'", "db1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
+
+func TestRegisterBackends(t *testing.T) {
+	if err := RegisterBackends([]config.Backend{{Name: "corp", Command: []string{"corp-ssh", "--audit"}, Like: "ssh"}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := BuildCommand("db1", Settings{Backend: "corp", Port: 2200})
+	if want := []string{"corp-ssh", "--audit", "-p", "2200", "db1"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v err=%v, want %#v", got, err, want)
+	}
+	if err := RegisterBackends([]config.Backend{{Name: "x", Command: []string{"x"}, Like: "corp"}}); err == nil {
+		t.Fatal("like a wrapper: want error")
+	}
+	if _, err := BuildCommand("db1", Settings{Backend: "nope"}); err == nil {
+		t.Fatal("unknown backend: want error")
+	}
+}
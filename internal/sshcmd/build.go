@@ -1,10 +1,12 @@
 package sshcmd
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
 )
 
 func shellQuotePOSIX(s string) string {
@@ -20,7 +22,10 @@ type Settings struct {
 	Port          int
 	IdentityFile  string
 	ExtraArgs     []string
+	Jump          []string // ProxyJump hops, rendered as -J
 	RemoteCommand string
+	Backend       string // registered backend name; empty means ssh
+	ForceTTY      bool   // request a tty (ssh -t) for an interactive remote command
 }
 
 func FromDefaults(defaults config.Defaults) Settings {
@@ -29,6 +34,8 @@ func FromDefaults(defaults config.Defaults) Settings {
 		Port:         defaults.Port,
 		IdentityFile: defaults.IdentityFile,
 		ExtraArgs:    defaults.ExtraArgs,
+		Jump:         defaults.Jump,
+		Backend:      defaults.Backend,
 	}
 }
 
@@ -46,6 +53,12 @@ func ApplyGroup(base Settings, group config.Group) Settings {
 	if len(group.ExtraArgs) != 0 {
 		s.ExtraArgs = group.ExtraArgs
 	}
+	if len(group.Jump) != 0 {
+		s.Jump = group.Jump
+	}
+	if strings.TrimSpace(group.Backend) != "" {
+		s.Backend = group.Backend
+	}
 	if strings.TrimSpace(group.RemoteCommand) != "" {
 		s.RemoteCommand = group.RemoteCommand
 	}
@@ -66,6 +79,12 @@ func ApplyHost(base Settings, host config.Host) Settings {
 	if len(host.ExtraArgs) != 0 {
 		s.ExtraArgs = host.ExtraArgs
 	}
+	if len(host.Jump) != 0 {
+		s.Jump = host.Jump
+	}
+	if strings.TrimSpace(host.Backend) != "" {
+		s.Backend = host.Backend
+	}
 	return s
 }
 
@@ -73,15 +92,31 @@ func Merge(defaults config.Defaults, group config.Group) Settings {
 	return ApplyGroup(FromDefaults(defaults), group)
 }
 
-// BuildCommand returns the full command slice starting with "ssh".
+// BuildCommand returns the full command slice of the backend of s,
+// starting with its program ("ssh" by default). Container and pod targets
+// (see container.Parse) get a `docker exec` or `kubectl exec` shell instead.
 func BuildCommand(host string, s Settings) ([]string, error) {
+	if t, ok := container.Parse(host); ok {
+		return t.Command(true, s.RemoteCommand), nil
+	}
+	b, ok := LookupBackend(s.Backend)
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q", s.Backend)
+	}
+	if b.check != nil {
+		if err := b.check(s); err != nil {
+			return nil, err
+		}
+	}
+	cmd := append([]string(nil), b.Program...)
+
 	baseHost := strings.TrimSpace(host)
 	if baseHost == "" {
-		return []string{"ssh"}, nil
+		return cmd, nil
 	}
 
 	sshPort := s.Port
-	if h, p, ok := parseBracketHost(baseHost); ok {
+	if h, p, ok := SplitBracketHost(baseHost); ok {
 		baseHost = h
 		sshPort = p
 	}
@@ -90,32 +125,12 @@ func BuildCommand(host string, s Settings) ([]string, error) {
 	if s.User != "" {
 		target = s.User + "@" + baseHost
 	}
-
-	cmd := []string{"ssh"}
-	if s.IdentityFile != "" {
-		cmd = append(cmd, "-i", s.IdentityFile)
-	}
-	if sshPort != 0 && sshPort != 22 {
-		cmd = append(cmd, "-p", strconv.Itoa(sshPort))
-	}
-	if len(s.ExtraArgs) != 0 {
-		cmd = append(cmd, s.ExtraArgs...)
-	}
-	cmd = append(cmd, target)
-
-	if rc := strings.TrimSpace(s.RemoteCommand); rc != "" {
-		// ssh executes the remote command through a shell. If we pass args like
-		// ["sh","-c","ls -lah"], ssh will serialize them into a string without
-		// preserving argv boundaries, and the remote shell will split the script.
-		// To keep the script intact, pass a single shell command string.
-		cmd = append(cmd, "sh -c "+shellQuotePOSIX(rc))
-	}
-
-	return cmd, nil
+	return append(cmd, b.args(target, sshPort, s)...), nil
 }
 
-func parseBracketHost(s string) (host string, port int, ok bool) {
-	// known_hosts uses "[host]:port".
+// SplitBracketHost splits the known_hosts "[host]:port" form; ok is false
+// for plain host names.
+func SplitBracketHost(s string) (host string, port int, ok bool) {
 	if !strings.HasPrefix(s, "[") {
 		return "", 0, false
 	}
@@ -134,3 +149,17 @@ func parseBracketHost(s string) (host string, port int, ok bool) {
 	}
 	return h, p, true
 }
+
+// KeepSessionOpen appends an interactive shell to a remote command, so the
+// session stays open after cmd finishes.
+func KeepSessionOpen(cmd string) string {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return ""
+	}
+	// Avoid doubling when the caller already includes it.
+	if strings.Contains(cmd, "exec ${SHELL") || strings.Contains(cmd, "exec $SHELL") {
+		return cmd
+	}
+	return cmd + "; exec ${SHELL:-sh}"
+}
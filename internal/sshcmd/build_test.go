@@ -64,3 +64,42 @@ func TestBuildCommandPort22Omitted(t *testing.T) {
 		t.Fatalf("cmd=%v, want %v", cmd, want)
 	}
 }
+
+func TestBuildCommandJump(t *testing.T) {
+	s := Settings{Port: 22, Jump: []string{"ops@bastion:22", "[10.0.0.1]:2222", "[fe80::1]:2200"}}
+	got, err := BuildCommand("[db1]:2022", s)
+	if err != nil {
+		t.Fatalf("BuildCommand: %v", err)
+	}
+	want := []string{"ssh", "-p", "2022", "-J", "ops@bastion,10.0.0.1:2222,[fe80::1]:2200", "db1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
+
+func TestBuildCommandJumpNone(t *testing.T) {
+	got, _ := BuildCommand("db1", Settings{Jump: []string{"none"}})
+	if want := []string{"ssh", "db1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
+
+func TestBuildCommandContainer(t *testing.T) {
+	got, _ := BuildCommand("k8s:prod/api-0", Settings{User: "me", Backend: "mosh", RemoteCommand: "top"})
+	want := []string{"kubectl", "exec", "-it", "api-0", "-n", "prod", "--", "sh", "-c", "top"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
+
+func TestKeepSessionOpen(t *testing.T) {
+	if got := KeepSessionOpen(" uptime "); got != "uptime; exec ${SHELL:-sh}" {
+		t.Fatalf("got=%q", got)
+	}
+	if got := KeepSessionOpen("top; exec $SHELL"); got != "top; exec $SHELL" {
+		t.Fatalf("got=%q, want unchanged", got)
+	}
+	if got := KeepSessionOpen("  "); got != "" {
+		t.Fatalf("got=%q, want empty", got)
+	}
+}
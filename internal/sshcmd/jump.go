@@ -0,0 +1,61 @@
+package sshcmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+)
+
+// SplitJumpHop splits a hop written as [user@]host[:port] or
+// [user@][host]:port into its parts; host is returned without brackets.
+func SplitJumpHop(hop string) (user, host string, port int) {
+	hop = strings.TrimSpace(hop)
+	if i := strings.LastIndex(hop, "@"); i >= 0 {
+		user, hop = hop[:i], hop[i+1:]
+	}
+	if strings.HasPrefix(hop, "[") {
+		if end := strings.Index(hop, "]"); end > 0 {
+			host = hop[1:end]
+			if p, err := strconv.Atoi(strings.TrimPrefix(hop[end+1:], ":")); err == nil {
+				port = p
+			}
+			return user, host, port
+		}
+	}
+	if i := strings.LastIndex(hop, ":"); i >= 0 && !strings.Contains(hop[:i], ":") {
+		if p, err := strconv.Atoi(hop[i+1:]); err == nil {
+			return user, hop[:i], p
+		}
+	}
+	return user, hop, 0
+}
+
+// FormatJumpHop renders a hop for ssh -J. IPv6 addresses are bracketed;
+// port 0 and 22 are omitted.
+func FormatJumpHop(user, host string, port int) string {
+	out := host
+	if strings.Contains(host, ":") {
+		out = "[" + host + "]"
+	}
+	if port != 0 && port != 22 {
+		out += ":" + strconv.Itoa(port)
+	}
+	if user != "" {
+		out = user + "@" + out
+	}
+	return out
+}
+
+// jumpArg returns the -J value for hops, or "" when there are none or the
+// list is ["none"].
+func jumpArg(hops []string) string {
+	if len(hops) == 0 || (len(hops) == 1 && strings.TrimSpace(hops[0]) == config.JumpNone) {
+		return ""
+	}
+	out := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		out = append(out, FormatJumpHop(SplitJumpHop(hop)))
+	}
+	return strings.Join(out, ",")
+}
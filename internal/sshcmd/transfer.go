@@ -0,0 +1,108 @@
+package sshcmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/container"
+)
+
+// Transfer is an scp copy between this machine and a host.
+type Transfer struct {
+	Pull   bool     // copy Remote from the host into Local[0]; otherwise push Local to Remote
+	Local  []string // files or directories to push, or the destination of a pull
+	Remote string   // path on the host; empty is the login directory
+}
+
+// SCPCommand returns the scp command that runs t on host with the user,
+// port, identity, jump hosts and ssh -o options of s. Directories are
+// copied recursively. scp always speaks ssh, so the backend of s is not
+// used.
+func SCPCommand(host string, s Settings, t Transfer) ([]string, error) {
+	target, port, err := transferTarget(host, s)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Local) == 0 {
+		return nil, fmt.Errorf("local path required")
+	}
+	if t.Pull && strings.TrimSpace(t.Remote) == "" {
+		return nil, fmt.Errorf("remote path required")
+	}
+	cmd := append([]string{"scp", "-r"}, transferOptions(port, s)...)
+	remote := target + ":" + t.Remote
+	if t.Pull {
+		return append(cmd, remote, t.Local[0]), nil
+	}
+	cmd = append(cmd, t.Local...)
+	return append(cmd, remote), nil
+}
+
+// SFTPCommand returns the interactive sftp command for host with the same
+// settings as SCPCommand.
+func SFTPCommand(host string, s Settings) ([]string, error) {
+	target, port, err := transferTarget(host, s)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{"sftp"}, transferOptions(port, s)...), target), nil
+}
+
+// transferTarget returns [user@]host for scp and sftp, with IPv6 addresses
+// bracketed, and the ssh port.
+func transferTarget(host string, s Settings) (target string, port int, err error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", 0, fmt.Errorf("host required")
+	}
+	if container.IsTarget(host) {
+		return "", 0, fmt.Errorf("host %q is a container, not an ssh host", host)
+	}
+	port = s.Port
+	if h, p, ok := SplitBracketHost(host); ok {
+		host, port = h, p
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if s.User != "" {
+		host = s.User + "@" + host
+	}
+	return host, port, nil
+}
+
+// transferOptions returns the scp/sftp flags of s. The port flag is -P, and
+// of the extra args only those both tools share with ssh are kept (-o, -F,
+// -4, -6, -C, -q, -v); -p PORT becomes -P PORT.
+func transferOptions(port int, s Settings) []string {
+	var out []string
+	if s.IdentityFile != "" {
+		out = append(out, "-i", s.IdentityFile)
+	}
+	extra := s.ExtraArgs
+	for i := 0; i < len(extra); i++ {
+		a := extra[i]
+		switch {
+		case (a == "-o" || a == "-F") && i+1 < len(extra):
+			out = append(out, a, extra[i+1])
+			i++
+		case strings.HasPrefix(a, "-o") && len(a) > 2:
+			out = append(out, a)
+		case a == "-4", a == "-6", a == "-C", a == "-q", a == "-v":
+			out = append(out, a)
+		case a == "-p" && i+1 < len(extra):
+			if p, err := strconv.Atoi(extra[i+1]); err == nil {
+				port = p
+			}
+			i++
+		}
+	}
+	if port != 0 && port != 22 {
+		out = append(out, "-P", strconv.Itoa(port))
+	}
+	if j := jumpArg(s.Jump); j != "" {
+		out = append(out, "-J", j)
+	}
+	return out
+}
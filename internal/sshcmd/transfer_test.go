@@ -0,0 +1,51 @@
+package sshcmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSCPCommand(t *testing.T) {
+	s := Settings{User: "me", Port: 2222, IdentityFile: "~/.ssh/id", Jump: []string{"gw"}, Backend: "mosh",
+		ExtraArgs: []string{"-o", "ServerAliveInterval=30", "-A", "-tt"}}
+	push, err := SCPCommand("db1", s, Transfer{Local: []string{"a.conf", "b.conf"}, Remote: "/etc/app/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"scp", "-r", "-i", "~/.ssh/id", "-o", "ServerAliveInterval=30", "-P", "2222", "-J", "gw", "a.conf", "b.conf", "me@db1:/etc/app/"}
+	if !reflect.DeepEqual(push, want) {
+		t.Fatalf("push=%#v, want %#v", push, want)
+	}
+
+	pull, err := SCPCommand("[fe80::1]:2201", Settings{}, Transfer{Pull: true, Local: []string{"logs/"}, Remote: "/var/log/syslog"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"scp", "-r", "-P", "2201", "[fe80::1]:/var/log/syslog", "logs/"}
+	if !reflect.DeepEqual(pull, want) {
+		t.Fatalf("pull=%#v, want %#v", pull, want)
+	}
+
+	for _, tt := range []struct {
+		host string
+		t    Transfer
+	}{
+		{"db1", Transfer{Remote: "/tmp"}},
+		{"db1", Transfer{Pull: true, Local: []string{"."}}},
+		{"docker:redis", Transfer{Local: []string{"a"}}},
+	} {
+		if _, err := SCPCommand(tt.host, Settings{}, tt.t); err == nil {
+			t.Fatalf("%s %+v: want error", tt.host, tt.t)
+		}
+	}
+}
+
+func TestSFTPCommand(t *testing.T) {
+	got, err := SFTPCommand("db1", Settings{User: "me", ExtraArgs: []string{"-p", "2022"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"sftp", "-P", "2022", "me@db1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,263 @@
+package sshconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxIncludeDepth mirrors ssh's own limit on nested Include directives.
+const maxIncludeDepth = 16
+
+// Host is a concrete (non-wildcard) alias declared by a "Host" line, with the
+// first value of each supported keyword found in its blocks.
+type Host struct {
+	Alias        string
+	HostName     string
+	User         string
+	Port         int
+	IdentityFile string
+	// Source is the file the alias was first declared in.
+	Source string
+}
+
+// DefaultPath returns ~/.ssh/config.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// Load parses an ssh_config file and every file it includes. A missing
+// top-level file is not an error. Hosts are returned sorted by alias.
+func Load(path string) ([]Host, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	p := &parser{
+		baseDir: filepath.Dir(path),
+		byAlias: make(map[string]*Host),
+	}
+	if err := p.parseFile(filepath.Clean(path), 0, true); err != nil {
+		return nil, err
+	}
+
+	return p.hosts(), nil
+}
+
+// Parse reads ssh_config data from r. Include directives are resolved
+// relative to baseDir (normally ~/.ssh).
+func Parse(r io.Reader, name, baseDir string) ([]Host, error) {
+	p := &parser{
+		baseDir: baseDir,
+		byAlias: make(map[string]*Host),
+	}
+	if err := p.parse(r, name, 0); err != nil {
+		return nil, err
+	}
+	return p.hosts(), nil
+}
+
+type parser struct {
+	baseDir string
+	byAlias map[string]*Host
+}
+
+func (p *parser) hosts() []Host {
+	out := make([]Host, 0, len(p.byAlias))
+	for _, h := range p.byAlias {
+		out = append(out, *h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Alias < out[j].Alias })
+	return out
+}
+
+func (p *parser) parseFile(path string, depth int, top bool) error {
+	// #nosec G304 -- ssh_config paths come from the user's own config.
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// ssh ignores missing files (including unmatched Include targets).
+			return nil
+		}
+		if top {
+			return err
+		}
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+	return p.parse(f, path, depth)
+}
+
+func (p *parser) parse(r io.Reader, name string, depth int) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// Aliases of the current Host block; nil inside Match blocks and before
+	// the first Host line.
+	var cur []*Host
+	lineNo := 0
+	for s.Scan() {
+		lineNo++
+		kw, args := splitLine(s.Text())
+		if kw == "" {
+			continue
+		}
+
+		switch kw {
+		case "host":
+			cur = cur[:0]
+			for _, a := range args {
+				if isPattern(a) {
+					continue
+				}
+				h, ok := p.byAlias[a]
+				if !ok {
+					h = &Host{Alias: a, Source: name}
+					p.byAlias[a] = h
+				}
+				cur = append(cur, h)
+			}
+		case "match":
+			cur = nil
+		case "include":
+			if depth+1 > maxIncludeDepth {
+				return fmt.Errorf("%s:%d: include nested too deeply", name, lineNo)
+			}
+			for _, a := range args {
+				if err := p.include(a, depth+1); err != nil {
+					return err
+				}
+			}
+		default:
+			if len(args) == 0 {
+				continue
+			}
+			for _, h := range cur {
+				if err := apply(h, kw, args[0]); err != nil {
+					return fmt.Errorf("%s:%d: %w", name, lineNo, err)
+				}
+			}
+		}
+	}
+	return s.Err()
+}
+
+func (p *parser) include(pattern string, depth int) error {
+	pattern = expandHome(pattern)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(p.baseDir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("include %q: %w", pattern, err)
+	}
+	sort.Strings(matches)
+	for _, m := range matches {
+		if st, err := os.Stat(m); err == nil && st.IsDir() {
+			continue
+		}
+		if err := p.parseFile(m, depth, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply sets kw on h unless it was already set (ssh uses the first value).
+func apply(h *Host, kw, val string) error {
+	switch kw {
+	case "hostname":
+		if h.HostName == "" {
+			h.HostName = val
+		}
+	case "user":
+		if h.User == "" {
+			h.User = val
+		}
+	case "port":
+		if h.Port == 0 {
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 || n > 65535 {
+				return fmt.Errorf("invalid port %q", val)
+			}
+			h.Port = n
+		}
+	case "identityfile":
+		if h.IdentityFile == "" {
+			h.IdentityFile = val
+		}
+	}
+	return nil
+}
+
+// splitLine returns the lower-cased keyword and its arguments. Both
+// "Keyword value" and "Keyword=value" forms are accepted, and double-quoted
+// arguments may contain spaces.
+func splitLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return strings.ToLower(line), nil
+	}
+	kw := strings.ToLower(line[:i])
+	rest := strings.TrimLeft(line[i:], " \t")
+	rest = strings.TrimPrefix(rest, "=")
+	return kw, splitArgs(rest)
+}
+
+func splitArgs(s string) []string {
+	var out []string
+	var b strings.Builder
+	inQuote := false
+	hasTok := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasTok = true
+		case (r == ' ' || r == '\t') && !inQuote:
+			if hasTok {
+				out = append(out, b.String())
+				b.Reset()
+				hasTok = false
+			}
+		case r == '#' && !inQuote && !hasTok:
+			// Trailing comment.
+			return out
+		default:
+			b.WriteRune(r)
+			hasTok = true
+		}
+	}
+	if hasTok {
+		out = append(out, b.String())
+	}
+	return out
+}
+
+// isPattern reports whether a Host argument is a wildcard or negation
+// rather than a concrete alias.
+func isPattern(s string) bool {
+	return s == "" || strings.ContainsAny(s, "*?!")
+}
+
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil && home != "" {
+			return filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	return p
+}
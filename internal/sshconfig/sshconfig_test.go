@@ -0,0 +1,84 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	in := strings.Join([]string{
+		"# comment",
+		"Host *",
+		"  User everyone",
+		"Host bastion jump-*",
+		"  HostName bastion.example.com",
+		"  User ops",
+		"  Port 2222",
+		"  IdentityFile ~/.ssh/ops_ed25519",
+		"Host db-primary !db-old",
+		"  HostName=10.0.0.5 # trailing comment",
+		"  Port 22",
+		"  Port 2200",
+		"Match host foo",
+		"  User ignored",
+		`Host "with space"`,
+	}, "\n")
+
+	got, err := Parse(strings.NewReader(in), "config", t.TempDir())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []Host{
+		{Alias: "bastion", HostName: "bastion.example.com", User: "ops", Port: 2222, IdentityFile: "~/.ssh/ops_ed25519", Source: "config"},
+		{Alias: "db-primary", HostName: "10.0.0.5", Port: 22, Source: "config"},
+		{Alias: "with space", Source: "config"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+}
+
+func TestLoadFollowsInclude(t *testing.T) {
+	d := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(d, "config.d"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	main := filepath.Join(d, "config")
+	if err := os.WriteFile(main, []byte("Include config.d/*.conf missing.conf\nHost web1\n  User deploy\n"), 0o600); err != nil {
+		t.Fatalf("write main: %v", err)
+	}
+	inc := filepath.Join(d, "config.d", "a.conf")
+	if err := os.WriteFile(inc, []byte("Host web1 web2\n  User first\n"), 0o600); err != nil {
+		t.Fatalf("write include: %v", err)
+	}
+
+	got, err := Load(main)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []Host{
+		{Alias: "web1", User: "first", Source: inc},
+		{Alias: "web2", User: "first", Source: inc},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+
+	if got, err := Load(filepath.Join(d, "nope")); err != nil || len(got) != 0 {
+		t.Fatalf("Load missing: got=%v err=%v", got, err)
+	}
+}
+
+func TestLoadIncludeCycle(t *testing.T) {
+	d := t.TempDir()
+	p := filepath.Join(d, "config")
+	if err := os.WriteFile(p, []byte("Include config\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(p); err == nil {
+		t.Fatalf("expected error for recursive include")
+	}
+}
@@ -0,0 +1,179 @@
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Vars are the variables of remote command, window name and pane title
+// templates, e.g. `journalctl -u {{.Meta.service}}` or `cd /srv/{{.Group}}`.
+// Only these references are expanded; the rest of the text is kept verbatim.
+type Vars struct {
+	Host      string            // host as listed: web1.example.com, [10.0.0.1]:2222
+	ShortHost string            // first label of the host name: web1 (IP addresses stay whole)
+	User      string            // effective user; empty when ssh picks it
+	Port      int               // effective port
+	Group     string            // group connected through; empty for a direct connect
+	Meta      map[string]string // host meta over the group's meta
+}
+
+// NewVars returns the variables of host. The port of the [host]:port form
+// wins over port, as it does for ssh; a zero port means 22.
+func NewVars(host, user string, port int, group string, meta map[string]string) Vars {
+	host = strings.TrimSpace(host)
+	base := host
+	if strings.HasPrefix(base, "[") {
+		if i := strings.LastIndex(base, "]:"); i > 0 {
+			if p, err := strconv.Atoi(base[i+2:]); err == nil {
+				port = p
+			}
+			base = base[1:i]
+		}
+	}
+	if port == 0 {
+		port = 22
+	}
+	short := base
+	if net.ParseIP(base) == nil {
+		if i := strings.IndexByte(base, '.'); i > 0 {
+			short = base[:i]
+		}
+	}
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	return Vars{Host: host, ShortHost: short, User: user, Port: port, Group: group, Meta: meta}
+}
+
+// varNames are the fields of Vars other than Meta.
+var varNames = []string{"Host", "ShortHost", "User", "Port", "Group"}
+
+// fieldRef matches a field reference: {{.Host}}, {{ .Meta.key }}, and
+// also the {{.Names}} of `docker ps --format`.
+var fieldRef = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)(?:\.([^\s{}]+))?\s*\}\}`)
+
+// Expand replaces the references to the Vars in text with their values of
+// v. Other Go template text, such as the {{.Names}} of `docker ps --format`
+// or {{json .}}, is left as is. A reference that looks like a misspelt
+// variable ({{.Hsot}}, {{.host}}) or a missing meta key is an error; name
+// (the setting) prefixes it.
+func Expand(name, text string, v Vars) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	var err error
+	fail := func(e error) {
+		if err == nil {
+			err = fmt.Errorf("%s: %w", name, e)
+		}
+	}
+	out := fieldRef.ReplaceAllStringFunc(text, func(ref string) string {
+		m := fieldRef.FindStringSubmatch(ref)
+		field, sub := m[1], m[2]
+		if field == "Meta" {
+			if sub == "" {
+				fail(errors.New("{{.Meta}} needs a key: {{.Meta.key}}"))
+				return ref
+			}
+			val, ok := v.Meta[sub]
+			if !ok {
+				fail(fmt.Errorf("meta key %q is not set", sub))
+			}
+			return val
+		}
+		if !slices.Contains(varNames, field) {
+			if like := misspelt(field); like != "" {
+				fail(fmt.Errorf("unknown variable .%s (did you mean .%s?)", field, like))
+			}
+			return ref
+		}
+		if sub != "" {
+			fail(fmt.Errorf(".%s has no field %q", field, sub))
+			return ref
+		}
+		switch field {
+		case "Host":
+			return v.Host
+		case "ShortHost":
+			return v.ShortHost
+		case "User":
+			return v.User
+		case "Port":
+			return strconv.Itoa(v.Port)
+		default:
+			return v.Group
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// misspelt returns the variable field is a near miss of: the same name in
+// another case, or one edit (or swap) away. Plurals such as docker's
+// {{.Ports}} are not near misses.
+func misspelt(field string) string {
+	for _, n := range slices.Concat(varNames, []string{"Meta"}) {
+		if strings.EqualFold(field, n) {
+			return n
+		}
+		if field != n+"s" && editDistance(strings.ToLower(field), strings.ToLower(n)) == 1 {
+			return n
+		}
+	}
+	return ""
+}
+
+// editDistance is the optimal string alignment distance of a and b:
+// insertions, deletions, substitutions and swaps of adjacent letters.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// HostError is a template error of one host.
+type HostError struct {
+	Host string
+	Err  error
+}
+
+func (e HostError) Error() string { return e.Host + ": " + e.Err.Error() }
+
+// Errors are the template errors of a connect, in host order.
+type Errors []HostError
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return ""
+	case 1:
+		return e[0].Error()
+	default:
+		return fmt.Sprintf("%s (+%d more)", e[0].Error(), len(e)-1)
+	}
+}
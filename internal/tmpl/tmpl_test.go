@@ -0,0 +1,90 @@
+package tmpl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewVars(t *testing.T) {
+	got := NewVars("web1.prod.example.com", "deploy", 0, "prod", nil)
+	want := Vars{Host: "web1.prod.example.com", ShortHost: "web1", User: "deploy", Port: 22, Group: "prod", Meta: map[string]string{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if v := NewVars("[10.0.0.1]:2222", "", 22, "", nil); v.ShortHost != "10.0.0.1" || v.Port != 2222 {
+		t.Fatalf("bracket host: %#v", v)
+	}
+	if v := NewVars("db1", "", 2200, "", nil); v.Port != 2200 {
+		t.Fatalf("port: %#v", v)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	v := NewVars("web1.example.com", "ops", 22, "prod", map[string]string{"service": "nginx"})
+	got, err := Expand("remote_command", "cd /srv/{{.Group}} && journalctl -u {{.Meta.service}} # {{.ShortHost}} {{.User}}@{{.Port}}", v)
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	if want := "cd /srv/prod && journalctl -u nginx # web1 ops@22"; got != want {
+		t.Fatalf("got=%q, want %q", got, want)
+	}
+	if got, err := Expand("remote_command", "echo ${HOME}", v); err != nil || got != "echo ${HOME}" {
+		t.Fatalf("plain text: got=%q err=%v", got, err)
+	}
+	if _, err := Expand("remote_command", "{{.Meta.missing}}", v); err == nil {
+		t.Fatalf("expected missing key error")
+	}
+	if got, err := Expand("remote_command", "{{ .Host }}:{{.Port}}", v); err != nil || got != "web1.example.com:22" {
+		t.Fatalf("spaced reference: got=%q err=%v", got, err)
+	}
+}
+
+func TestExpandKeepsOtherTemplates(t *testing.T) {
+	v := NewVars("web1", "", 22, "prod", nil)
+	for in, want := range map[string]string{
+		"docker ps --format '{{.Names}}'":                    "docker ps --format '{{.Names}}'",
+		"docker ps --format '{{json .}}' # {{.Group}}":       "docker ps --format '{{json .}}' # prod",
+		"awk '{{print $1}}' {{.Nope}} {{.Group":              "awk '{{print $1}}' {{.Nope}} {{.Group",
+		"kubectl get po -o go-template='{{range .items}}x'":  "kubectl get po -o go-template='{{range .items}}x'",
+		"docker ps --format '{{.ID}} {{.Ports}} {{.Image}}'": "docker ps --format '{{.ID}} {{.Ports}} {{.Image}}'",
+	} {
+		got, err := Expand("remote_command", in, v)
+		if err != nil || got != want {
+			t.Fatalf("Expand(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	e := Errors{{Host: "a", Err: errString("x")}, {Host: "b", Err: errString("y")}}
+	if got := e.Error(); got != "a: x (+1 more)" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
+
+func TestExpandRejectsMisspeltVariables(t *testing.T) {
+	v := NewVars("web1", "", 22, "prod", map[string]string{"svc-name": "api"})
+	if got, err := Expand("remote_command", "systemctl status {{.Meta.svc-name}}", v); err != nil || got != "systemctl status api" {
+		t.Fatalf("meta key with a dash: got=%q err=%v", got, err)
+	}
+	for in, want := range map[string]string{
+		"ping {{.Hsot}}":     "unknown variable .Hsot (did you mean .Host?)",
+		"ping {{ .host }}":   "unknown variable .host (did you mean .Host?)",
+		"cd /srv/{{.Gruop}}": "did you mean .Group?",
+		"{{.Shorthost}}":     "did you mean .ShortHost?",
+		"{{.Mta.app}}":       "did you mean .Meta?",
+		"{{.Meta.svc-nmae}}": `meta key "svc-nmae" is not set`,
+		"{{.Meta}}":          "needs a key",
+		"{{.Host.Name}}":     `.Host has no field "Name"`,
+	} {
+		_, err := Expand("remote_command", in, v)
+		if err == nil || !strings.Contains(err.Error(), want) || !strings.HasPrefix(err.Error(), "remote_command: ") {
+			t.Fatalf("Expand(%q): err=%v, want %q", in, err, want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package tmux
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 type OpenMode string
 
@@ -10,14 +13,25 @@ const (
 	OpenPane    OpenMode = "tmux-pane"
 )
 
-func ResolveOpenMode(tmuxSetting string, openModeSetting string, inTmux bool) OpenMode {
+// ResolveOpenMode returns the open mode of a connect to hosts hosts. The
+// current terminal only runs one host: a multi-host connect resolved to
+// OpenCurrent opens in a window instead (the detached tmux_session outside
+// tmux), unless tmux is "never".
+func ResolveOpenMode(tmuxSetting string, openModeSetting string, inTmux bool, hosts int) OpenMode {
 	tmuxSetting = strings.ToLower(strings.TrimSpace(tmuxSetting))
 	openModeSetting = strings.ToLower(strings.TrimSpace(openModeSetting))
 
 	if tmuxSetting == "never" {
 		return OpenCurrent
 	}
+	mode := resolveOpenMode(tmuxSetting, openModeSetting, inTmux)
+	if mode == OpenCurrent && hosts > 1 {
+		return OpenWindow
+	}
+	return mode
+}
 
+func resolveOpenMode(tmuxSetting string, openModeSetting string, inTmux bool) OpenMode {
 	if tmuxSetting == "force" {
 		if openModeSetting == string(OpenPane) {
 			return OpenPane
@@ -73,12 +87,43 @@ func SplitPaneCmdFlag(splitFlag string, sshCmd []string) []string {
 }
 
 func NewSessionCmd(session string, sshCmd []string) []string {
+	cmd := []string{"tmux", "new-session", "-A", "-s", SessionName(session), "--"}
+	cmd = append(cmd, sshCmd...)
+	return cmd
+}
+
+// SessionName returns the tmux_session setting, "ssh-tui" when unset.
+func SessionName(session string) string {
 	session = strings.TrimSpace(session)
 	if session == "" {
-		session = "ssh-tui"
+		return "ssh-tui"
 	}
+	return session
+}
 
-	cmd := []string{"tmux", "new-session", "-A", "-s", session, "--"}
-	cmd = append(cmd, sshCmd...)
-	return cmd
+// AttachSessionCmd returns the argv attaching a new client to session.
+func AttachSessionCmd(session string) []string {
+	return []string{"tmux", "attach-session", "-t", SessionName(session)}
+}
+
+// newWindowArgs returns the tmux arguments creating a window named name
+// that runs sshCmd and prints format. An empty session means the current
+// one (inside tmux); otherwise the window is added to session, which is
+// started detached when it does not exist yet, sized like the terminal
+// (width and height, 0 when unknown).
+func newWindowArgs(session string, exists bool, width, height int, name, format string, sshCmd []string) []string {
+	var args []string
+	switch {
+	case session == "":
+		args = []string{"new-window", "-P", "-F", format, "-n", name}
+	case exists:
+		args = []string{"new-window", "-t", session + ":", "-P", "-F", format, "-n", name}
+	default:
+		args = []string{"new-session", "-d", "-s", session, "-P", "-F", format, "-n", name}
+		if width > 0 && height > 0 {
+			args = append(args, "-x", strconv.Itoa(width), "-y", strconv.Itoa(height))
+		}
+	}
+	args = append(args, "--")
+	return append(args, sshCmd...)
 }
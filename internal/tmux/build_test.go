@@ -3,6 +3,8 @@ package tmux
 import (
 	"reflect"
 	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
 )
 
 func TestResolveOpenMode(t *testing.T) {
@@ -11,19 +13,24 @@ func TestResolveOpenMode(t *testing.T) {
 		tmux     string
 		openMode string
 		inTmux   bool
+		hosts    int
 		want     OpenMode
 	}{
-		{"never forces current", "never", "tmux-window", true, OpenCurrent},
-		{"force defaults to window", "force", "current", false, OpenWindow},
-		{"force pane", "force", "tmux-pane", false, OpenPane},
-		{"auto not in tmux defaults current", "auto", "auto", false, OpenCurrent},
-		{"auto in tmux defaults window", "auto", "auto", true, OpenWindow},
-		{"explicit window", "auto", "tmux-window", false, OpenWindow},
+		{"never forces current", "never", "tmux-window", true, 1, OpenCurrent},
+		{"force defaults to window", "force", "current", false, 1, OpenWindow},
+		{"force pane", "force", "tmux-pane", false, 1, OpenPane},
+		{"auto not in tmux defaults current", "auto", "auto", false, 1, OpenCurrent},
+		{"auto in tmux defaults window", "auto", "auto", true, 1, OpenWindow},
+		{"explicit window", "auto", "tmux-window", false, 1, OpenWindow},
+		{"multi-host auto not in tmux opens a window", "auto", "auto", false, 2, OpenWindow},
+		{"multi-host current opens a window", "auto", "current", false, 3, OpenWindow},
+		{"multi-host never stays current", "never", "auto", false, 2, OpenCurrent},
+		{"multi-host pane", "auto", "tmux-pane", false, 2, OpenPane},
 	}
 
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ResolveOpenMode(tt.tmux, tt.openMode, tt.inTmux)
+			got := ResolveOpenMode(tt.tmux, tt.openMode, tt.inTmux, tt.hosts)
 			if got != tt.want {
 				t.Fatalf("got=%q want=%q", got, tt.want)
 			}
@@ -47,3 +54,57 @@ func TestCmdBuilders(t *testing.T) {
 		t.Fatalf("NewSessionCmd=%v want=%v", got, want)
 	}
 }
+
+func TestNewWindowArgs(t *testing.T) {
+	ssh := []string{"ssh", "h"}
+	tc := []struct {
+		name    string
+		session string
+		exists  bool
+		w, h    int
+		want    []string
+	}{
+		{"current session", "", false, 0, 0, []string{"new-window", "-P", "-F", "#{pane_id}", "-n", "h", "--", "ssh", "h"}},
+		{"existing session", "ops", true, 200, 50, []string{"new-window", "-t", "ops:", "-P", "-F", "#{pane_id}", "-n", "h", "--", "ssh", "h"}},
+		{"new session", "ops", false, 200, 50, []string{"new-session", "-d", "-s", "ops", "-P", "-F", "#{pane_id}", "-n", "h", "-x", "200", "-y", "50", "--", "ssh", "h"}},
+		{"new session, no terminal", "ops", false, 0, 0, []string{"new-session", "-d", "-s", "ops", "-P", "-F", "#{pane_id}", "-n", "h", "--", "ssh", "h"}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newWindowArgs(tt.session, tt.exists, tt.w, tt.h, "h", "#{pane_id}", ssh); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got=%v want=%v", got, tt.want)
+			}
+		})
+	}
+	if got, want := AttachSessionCmd(" "), []string{"tmux", "attach-session", "-t", "ssh-tui"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("AttachSessionCmd=%v want=%v", got, want)
+	}
+}
+
+func TestWindowCount(t *testing.T) {
+	for _, tt := range []struct{ panes, max, want int }{
+		{5, 0, 1},
+		{5, 5, 1},
+		{21, 10, 3},
+		{20, 10, 2},
+		{1, 1, 1},
+	} {
+		if got := WindowCount(tt.panes, tt.max); got != tt.want {
+			t.Fatalf("WindowCount(%d, %d)=%d want %d", tt.panes, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePaneSettingsMaxPanes(t *testing.T) {
+	d := config.Defaults{MaxPanesPerWindow: 3}
+	if ps := ResolvePaneSettings(d, nil, 10); ps.MaxPanes != 3 || ps.Layout != "even-vertical" {
+		t.Fatalf("defaults: %+v (auto layout of a 3-pane window)", ps)
+	}
+	g := config.Group{MaxPanesPerWindow: 8}
+	if ps := ResolvePaneSettings(d, &g, 10); ps.MaxPanes != 8 || ps.Layout != "tiled" {
+		t.Fatalf("group override: %+v", ps)
+	}
+	if ps := ResolvePaneSettings(config.Defaults{}, nil, 30); ps.MaxPanes != 0 {
+		t.Fatalf("no cap: %+v", ps)
+	}
+}
@@ -8,8 +8,14 @@ import (
 
 // OneWindowOpts controls how tmuxOpenOneWindow creates panes in a single tmux window.
 type OneWindowOpts struct {
+	// Session to open the window in, started detached when it does not
+	// exist yet (outside tmux); empty means the current session.
+	Session string
+
 	WindowName string
 	PaneTitles []string
+	// Tags are set as the @ssh_tui_host/@ssh_tui_group options of each pane.
+	Tags []Tag
 
 	// SplitFlag is passed to tmux split-window: "-h" or "-v".
 	SplitFlag string
@@ -22,9 +28,15 @@ type OneWindowOpts struct {
 	// PaneBorderFormat and PaneBorderStatus are tmux window options.
 	PaneBorderFormat string
 	PaneBorderStatus string // off|top|bottom
+
+	// MaxPanes caps the panes of a window; the rest go to more windows,
+	// named WindowName-1, WindowName-2, ... (0 = no cap).
+	MaxPanes int
 }
 
-// OpenOneWindow creates a new tmux window and splits it into panes, one per SSH command.
+// OpenOneWindow creates a new tmux window and splits it into panes, one per
+// SSH command, or as many windows as opts.MaxPanes requires. A window that
+// fails halfway is closed; the windows already built are kept.
 func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 	if len(sshCmds) == 0 {
 		return fmt.Errorf("no hosts selected")
@@ -35,6 +47,25 @@ func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 		name = "ssh"
 	}
 
+	n := WindowCount(len(sshCmds), opts.MaxPanes)
+	if n == 1 {
+		return openWindow(sshCmds, name, opts)
+	}
+	for w := 0; w < n; w++ {
+		start := w * opts.MaxPanes
+		end := min(start+opts.MaxPanes, len(sshCmds))
+		chunk := opts
+		chunk.PaneTitles = sliceFrom(opts.PaneTitles, start, end)
+		chunk.Tags = sliceFrom(opts.Tags, start, end)
+		if err := openWindow(sshCmds[start:end], fmt.Sprintf("%s-%d", name, w+1), chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openWindow builds one window of OpenOneWindow.
+func openWindow(sshCmds [][]string, name string, opts OneWindowOpts) error {
 	layout := strings.TrimSpace(opts.Layout)
 	if layout == "" {
 		layout = "even-horizontal"
@@ -55,15 +86,12 @@ func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 	}
 
 	// Create window and capture both window_id and pane_id.
-	args := []string{"new-window", "-P", "-F", "#{window_id} #{pane_id}", "-n", name, "--"}
-	args = append(args, sshCmds[0]...)
-	// #nosec G204 -- running tmux with argv (no shell); args are constructed by the app.
-	out, err := exec.Command("tmux", args...).CombinedOutput()
+	out, err := newWindow(strings.TrimSpace(opts.Session), name, "#{window_id} #{pane_id}", sshCmds[0])
 	if err != nil {
-		return fmt.Errorf("tmux error: %s", tmuxErrMsg(out, err))
+		return err
 	}
 
-	fields := strings.Fields(strings.TrimSpace(string(out)))
+	fields := strings.Fields(out)
 	if len(fields) < 2 {
 		return fmt.Errorf("tmux error: missing window/pane id")
 	}
@@ -86,6 +114,7 @@ func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 	if title := tmuxPaneTitle(opts.PaneTitles, 0); title != "" {
 		_ = tmuxRun("select-pane", "-t", firstPaneID, "-T", title)
 	}
+	TagPane(firstPaneID, paneTag(opts.Tags, 0))
 
 	for i := 1; i < len(sshCmds); i++ {
 		splitArgs := []string{"split-window", "-t", winID, splitFlag, "-P", "-F", "#{pane_id}", "--"}
@@ -93,7 +122,9 @@ func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 		// #nosec G204 -- running tmux with argv (no shell); args are constructed by the app.
 		out, err := exec.Command("tmux", splitArgs...).CombinedOutput()
 		if err != nil {
-			return fmt.Errorf("tmux error: %s", tmuxErrMsg(out, err))
+			// Roll back the half-built window.
+			_ = tmuxRun("kill-window", "-t", winID)
+			return fmt.Errorf("tmux error: %s (window %s closed)", tmuxErrMsg(out, err), name)
 		}
 
 		paneFields := strings.Fields(strings.TrimSpace(string(out)))
@@ -105,6 +136,7 @@ func OpenOneWindow(sshCmds [][]string, opts OneWindowOpts) error {
 			if title := tmuxPaneTitle(opts.PaneTitles, i); title != "" {
 				_ = tmuxRun("select-pane", "-t", paneID, "-T", title)
 			}
+			TagPane(paneID, paneTag(opts.Tags, i))
 		}
 	}
 
@@ -120,6 +152,19 @@ func tmuxPaneTitle(titles []string, idx int) string {
 	return strings.TrimSpace(titles[idx])
 }
 
+// sliceFrom returns s[start:end], cut to the length of s.
+func sliceFrom[T any](s []T, start, end int) []T {
+	start, end = min(start, len(s)), min(end, len(s))
+	return s[start:end]
+}
+
+func paneTag(tags []Tag, idx int) Tag {
+	if idx < 0 || idx >= len(tags) {
+		return Tag{}
+	}
+	return tags[idx]
+}
+
 func tmuxRun(args ...string) error {
 	// #nosec G204 -- running tmux with argv (no shell); args are internal.
 	_, err := exec.Command("tmux", args...).CombinedOutput()
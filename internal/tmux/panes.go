@@ -13,10 +13,21 @@ type PaneSettings struct {
 	SyncPanes    bool
 	BorderFormat string
 	BorderStatus string
+	MaxPanes     int // panes per window; 0 = no cap
 }
 
-// ResolvePaneSettings merges defaults and optional group overrides into PaneSettings.
+// ResolvePaneSettings merges defaults and optional group overrides into
+// PaneSettings. The auto layout is chosen for the panes of one window, at
+// most MaxPanes of the paneCount.
 func ResolvePaneSettings(defaults config.Defaults, group *config.Group, paneCount int) PaneSettings {
+	maxPanes := defaults.MaxPanesPerWindow
+	if group != nil && group.MaxPanesPerWindow > 0 {
+		maxPanes = group.MaxPanesPerWindow
+	}
+	if maxPanes > 0 && paneCount > maxPanes {
+		paneCount = maxPanes
+	}
+
 	split := strings.TrimSpace(defaults.PaneSplit)
 	layout := strings.TrimSpace(defaults.PaneLayout)
 	sync := strings.TrimSpace(defaults.PaneSync)
@@ -89,5 +100,15 @@ func ResolvePaneSettings(defaults config.Defaults, group *config.Group, paneCoun
 		SyncPanes:    syncOn,
 		BorderFormat: borderFmt,
 		BorderStatus: borderPos,
+		MaxPanes:     max(0, maxPanes),
+	}
+}
+
+// WindowCount returns how many windows OpenOneWindow opens for paneCount
+// panes with at most maxPanes each (0 = no cap).
+func WindowCount(paneCount, maxPanes int) int {
+	if maxPanes <= 0 || paneCount <= maxPanes {
+		return 1
 	}
+	return (paneCount + maxPanes - 1) / maxPanes
 }
@@ -0,0 +1,294 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// User options set on the panes ssh-tui opens, so they can be found again.
+const (
+	OptHost  = "@ssh_tui_host"
+	OptGroup = "@ssh_tui_group"
+)
+
+// Tag identifies the host (and group, empty for a direct connect) of a pane.
+type Tag struct {
+	Host  string
+	Group string
+	// Keep turns remain-on-exit on for the pane, so it stays, dead, after
+	// its command exits and can be respawned.
+	Keep bool
+}
+
+// Pane is a live tmux pane opened by ssh-tui.
+type Pane struct {
+	Session     string
+	WindowID    string
+	WindowIndex int
+	WindowName  string
+	PaneID      string
+	PaneIndex   int
+	Title       string
+	Dead        bool // the command exited and remain-on-exit kept the pane
+	DeadStatus  int
+	Host        string
+	Group       string
+}
+
+// Target is the "session:window.pane" name shown to the user.
+func (p Pane) Target() string {
+	return fmt.Sprintf("%s:%d.%d", p.Session, p.WindowIndex, p.PaneIndex)
+}
+
+// listPanesFormat is the list-panes -F format parsed by ParsePanes: one
+// tab-separated line per pane.
+var listPanesFormat = strings.Join([]string{
+	"#{session_name}",
+	"#{window_id}",
+	"#{window_index}",
+	"#{window_name}",
+	"#{pane_id}",
+	"#{pane_index}",
+	"#{pane_title}",
+	"#{pane_dead}",
+	"#{pane_dead_status}",
+	"#{" + OptHost + "}",
+	"#{" + OptGroup + "}",
+}, "\t")
+
+// ListPanesCmd returns the argv listing every pane of the tmux server.
+func ListPanesCmd() []string {
+	return []string{"tmux", "list-panes", "-a", "-F", listPanesFormat}
+}
+
+// ParsePanes parses list-panes output in listPanesFormat, keeping the panes
+// tagged with a host, sorted by host then target.
+func ParsePanes(out string) []Pane {
+	var panes []Pane
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 11 || strings.TrimSpace(f[9]) == "" {
+			continue
+		}
+		p := Pane{
+			Session:    f[0],
+			WindowID:   f[1],
+			WindowName: f[3],
+			PaneID:     f[4],
+			Title:      f[6],
+			Dead:       f[7] == "1",
+			Host:       strings.TrimSpace(f[9]),
+			Group:      strings.TrimSpace(f[10]),
+		}
+		p.WindowIndex, _ = strconv.Atoi(f[2])
+		p.PaneIndex, _ = strconv.Atoi(f[5])
+		p.DeadStatus, _ = strconv.Atoi(f[8])
+		panes = append(panes, p)
+	}
+	sort.SliceStable(panes, func(i, j int) bool {
+		if panes[i].Host != panes[j].Host {
+			return panes[i].Host < panes[j].Host
+		}
+		if panes[i].Session != panes[j].Session {
+			return panes[i].Session < panes[j].Session
+		}
+		if panes[i].WindowIndex != panes[j].WindowIndex {
+			return panes[i].WindowIndex < panes[j].WindowIndex
+		}
+		return panes[i].PaneIndex < panes[j].PaneIndex
+	})
+	return panes
+}
+
+// ListPanes returns the live panes opened by ssh-tui on the tmux server. No
+// server running is not an error.
+func ListPanes() ([]Pane, error) {
+	argv := ListPanesCmd()
+	// #nosec G204 -- running tmux with argv (no shell); args are internal.
+	out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput()
+	if err != nil {
+		msg := tmuxErrMsg(out, err)
+		if strings.Contains(msg, "no server running") || strings.Contains(msg, "error connecting to") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tmux error: %s", msg)
+	}
+	return ParsePanes(string(out)), nil
+}
+
+// FindOpen looks for live panes of every one of hosts tagged with group (any
+// group when group is empty) and returns the pane of the first host. With
+// sameWindow they must all be in one window, as OpenOneWindow leaves them.
+func FindOpen(panes []Pane, hosts []string, group string, sameWindow bool) (Pane, bool) {
+	if len(hosts) == 0 {
+		return Pane{}, false
+	}
+	byWindow := map[string]map[string]Pane{}
+	byHost := map[string]Pane{}
+	for _, p := range panes {
+		if p.Dead || (group != "" && p.Group != group) {
+			continue
+		}
+		if byWindow[p.WindowID] == nil {
+			byWindow[p.WindowID] = map[string]Pane{}
+		}
+		if _, ok := byWindow[p.WindowID][p.Host]; !ok {
+			byWindow[p.WindowID][p.Host] = p
+		}
+		if _, ok := byHost[p.Host]; !ok {
+			byHost[p.Host] = p
+		}
+	}
+	if !sameWindow {
+		for _, h := range hosts {
+			if _, ok := byHost[h]; !ok {
+				return Pane{}, false
+			}
+		}
+		return byHost[hosts[0]], true
+	}
+	for _, p := range panes {
+		w := byWindow[p.WindowID]
+		first, ok := w[hosts[0]]
+		if !ok || first.PaneID != p.PaneID {
+			continue
+		}
+		all := true
+		for _, h := range hosts[1:] {
+			if _, ok := w[h]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return first, true
+		}
+	}
+	return Pane{}, false
+}
+
+// TagPane sets the ssh-tui user options on a pane. Best-effort: pane
+// options need tmux 3.0 or later.
+func TagPane(paneID string, t Tag) {
+	paneID = strings.TrimSpace(paneID)
+	if paneID == "" || strings.TrimSpace(t.Host) == "" {
+		return
+	}
+	_ = tmuxRun("set-option", "-p", "-t", paneID, OptHost, strings.TrimSpace(t.Host))
+	if g := strings.TrimSpace(t.Group); g != "" {
+		_ = tmuxRun("set-option", "-p", "-t", paneID, OptGroup, g)
+	}
+	if t.Keep {
+		// remain-on-exit is a pane option since tmux 3.2; before, it
+		// applies to the whole window.
+		if tmuxRun("set-option", "-p", "-t", paneID, "remain-on-exit", "on") != nil {
+			_ = tmuxRun("set-option", "-w", "-t", paneID, "remain-on-exit", "on")
+		}
+	}
+}
+
+// NewWindow runs NewWindowCmd and tags the pane it creates.
+func NewWindow(name string, sshCmd []string, t Tag) error {
+	return NewWindowIn("", name, sshCmd, t)
+}
+
+// NewWindowIn is NewWindow in session, started detached when it does not
+// exist; "" is the current session.
+func NewWindowIn(session, name string, sshCmd []string, t Tag) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "ssh"
+	}
+	out, err := newWindow(session, name, "#{pane_id}", sshCmd)
+	if err != nil {
+		return err
+	}
+	TagPane(out, t)
+	return nil
+}
+
+// newWindow creates a window (see newWindowArgs) and returns what it
+// printed in format.
+func newWindow(session, name, format string, sshCmd []string) (string, error) {
+	exists := false
+	width, height := 0, 0
+	if session != "" {
+		exists = HasSession(session)
+		if !exists {
+			width, height = termSize()
+		}
+	}
+	args := newWindowArgs(session, exists, width, height, name, format, sshCmd)
+	// #nosec G204 -- running tmux with argv (no shell); args are constructed by the app.
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("tmux error: %s", tmuxErrMsg(out, err))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// HasSession reports whether the tmux server has a session named session.
+func HasSession(session string) bool {
+	return tmuxRun("has-session", "-t", "="+session) == nil
+}
+
+// termSize returns the size of the terminal on stdout, 0, 0 when it is not
+// one.
+func termSize() (width, height int) {
+	w, h, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0, 0
+	}
+	return w, h
+}
+
+// SelectPane makes p the current pane of its window and its window the
+// current one of its session.
+func SelectPane(p Pane) error {
+	if err := tmuxRunErr("select-window", "-t", p.WindowID); err != nil {
+		return err
+	}
+	return tmuxRunErr("select-pane", "-t", p.PaneID)
+}
+
+// SwitchClient shows the session of p in the current client (inside tmux).
+func SwitchClient(p Pane) error { return tmuxRunErr("switch-client", "-t", p.Session) }
+
+// Focus selects p and shows its session in the current client (inside
+// tmux).
+func Focus(p Pane) error {
+	if err := SelectPane(p); err != nil {
+		return err
+	}
+	return SwitchClient(p)
+}
+
+// AttachCmd returns the argv attaching a new client to the session of p
+// (outside tmux).
+func AttachCmd(p Pane) []string {
+	return AttachSessionCmd(p.Session)
+}
+
+// KillPane closes p.
+func KillPane(p Pane) error { return tmuxRunErr("kill-pane", "-t", p.PaneID) }
+
+// KillWindow closes the window of p with all its panes.
+func KillWindow(p Pane) error { return tmuxRunErr("kill-window", "-t", p.WindowID) }
+
+// RespawnPane restarts the command of the dead pane p.
+func RespawnPane(p Pane) error { return tmuxRunErr("respawn-pane", "-t", p.PaneID) }
+
+func tmuxRunErr(args ...string) error {
+	// #nosec G204 -- running tmux with argv (no shell); args are internal.
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("tmux error: %s", tmuxErrMsg(out, err))
+	}
+	return nil
+}
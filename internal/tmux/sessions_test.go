@@ -0,0 +1,76 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePanes(t *testing.T) {
+	out := strings.Join([]string{
+		"main\t@1\t1\tzsh\t%1\t0\tlaptop\t0\t0\t\t",
+		"ssh-tui\t@3\t2\tprod\t%5\t1\tweb2\t0\t0\tweb2.example.com\tprod",
+		"ssh-tui\t@3\t2\tprod\t%4\t0\tweb1\t1\t255\tweb1.example.com\tprod",
+		"main\t@2\t3\tdb1\t%7\t0\tdb1\t0\t0\tdb1\t",
+		"garbage",
+		"",
+	}, "\n")
+	got := ParsePanes(out)
+	if len(got) != 3 {
+		t.Fatalf("got %d panes, want 3: %#v", len(got), got)
+	}
+	if got[0].Host != "db1" || got[0].Group != "" || got[0].Target() != "main:3.0" {
+		t.Fatalf("first pane: %#v", got[0])
+	}
+	web1 := got[1]
+	if web1.Host != "web1.example.com" || web1.Group != "prod" || !web1.Dead || web1.DeadStatus != 255 ||
+		web1.PaneID != "%4" || web1.WindowID != "@3" || web1.WindowName != "prod" || web1.Title != "web1" {
+		t.Fatalf("web1 pane: %#v", web1)
+	}
+	if got[2].Dead || got[2].Target() != "ssh-tui:2.1" {
+		t.Fatalf("web2 pane: %#v", got[2])
+	}
+}
+
+func TestListPanesCmd(t *testing.T) {
+	argv := ListPanesCmd()
+	if len(argv) != 5 || argv[1] != "list-panes" || argv[2] != "-a" {
+		t.Fatalf("argv=%v", argv)
+	}
+	if f := strings.Split(argv[4], "\t"); len(f) != 11 || f[9] != "#{@ssh_tui_host}" {
+		t.Fatalf("format=%q", argv[4])
+	}
+}
+
+func TestFindOpen(t *testing.T) {
+	panes := []Pane{
+		{WindowID: "@1", PaneID: "%1", Host: "db1"},
+		{WindowID: "@2", PaneID: "%2", Host: "web1", Group: "prod", Dead: true},
+		{WindowID: "@3", PaneID: "%3", Host: "web1", Group: "prod"},
+		{WindowID: "@3", PaneID: "%4", Host: "web2", Group: "prod"},
+		{WindowID: "@4", PaneID: "%5", Host: "web1"},
+	}
+	if p, ok := FindOpen(panes, []string{"db1"}, "", true); !ok || p.PaneID != "%1" {
+		t.Fatalf("db1: %v %v", p, ok)
+	}
+	if p, ok := FindOpen(panes, []string{"web1"}, "", true); !ok || p.PaneID != "%3" {
+		t.Fatalf("web1, any group: %v %v (dead pane must be skipped)", p, ok)
+	}
+	if p, ok := FindOpen(panes, []string{"web2", "web1"}, "prod", true); !ok || p.PaneID != "%4" {
+		t.Fatalf("prod: %v %v", p, ok)
+	}
+	if _, ok := FindOpen(panes, []string{"web1", "web3"}, "prod", true); ok {
+		t.Fatal("web3 is not open")
+	}
+	if _, ok := FindOpen(panes, []string{"db1"}, "prod", true); ok {
+		t.Fatal("db1 is not open through prod")
+	}
+	if _, ok := FindOpen(panes, []string{"db1", "web2"}, "", true); ok {
+		t.Fatal("db1 and web2 are in different windows")
+	}
+	if p, ok := FindOpen(panes, []string{"db1", "web2"}, "", false); !ok || p.PaneID != "%1" {
+		t.Fatalf("db1 and web2, own windows: %v %v", p, ok)
+	}
+	if _, ok := FindOpen(panes, nil, "", false); ok {
+		t.Fatal("no hosts")
+	}
+}
@@ -0,0 +1,225 @@
+package tunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// startGrace is how long Start waits for ssh to fail before it reports the
+// tunnel as up (ExitOnForwardFailure makes bind errors exit quickly).
+var startGrace = 1500 * time.Millisecond
+
+// Status is the state of a tunnel process.
+type Status int
+
+const (
+	Stopped Status = iota // no state file
+	Running               // the recorded process is alive and still runs the tunnel
+	Failed                // the recorded process has exited
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Failed:
+		return "failed"
+	default:
+		return "stopped"
+	}
+}
+
+// State is the record of a started tunnel, stored as DIR/NAME.json next to
+// its ssh output in DIR/NAME.log.
+type State struct {
+	Name    string    `json:"name"`
+	PID     int       `json:"pid"`
+	Command []string  `json:"command"`
+	Started time.Time `json:"started"`
+}
+
+// Info is the state of one tunnel as seen by Check.
+type Info struct {
+	State  State
+	Status Status
+	Err    string // last ssh output line of a failed tunnel
+}
+
+// Start runs argv in the background in its own session, so it outlives the
+// caller, and records it in dir. It returns an error when the tunnel is
+// already running or the process exits within a short grace period; in the
+// latter case the state is kept and Check reports it as Failed.
+func Start(dir, name string, argv []string) (State, error) {
+	if len(argv) == 0 {
+		return State{}, errors.New("empty command")
+	}
+	if info := Check(dir, name); info.Status == Running {
+		return info.State, fmt.Errorf("tunnel %q is already running (pid %d)", name, info.State.PID)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return State{}, err
+	}
+	logPath := filepath.Join(dir, name+".log")
+	// #nosec G304 -- path is derived from the XDG state dir and a validated name.
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return State{}, err
+	}
+	defer func() { _ = logFile.Close() }()
+
+	// #nosec G204 -- argv is an ssh command built from the user's own config.
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return State{}, err
+	}
+	st := State{Name: name, PID: cmd.Process.Pid, Command: argv, Started: time.Now()}
+	if err := writeState(dir, st); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return State{}, err
+	}
+
+	// Reap the process when it exits so a dead tunnel is not left as a
+	// zombie that still looks alive.
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		msg := lastLine(logPath)
+		if msg == "" {
+			msg = "exited"
+		}
+		return st, fmt.Errorf("tunnel %q: %s", name, msg)
+	case <-time.After(startGrace):
+		return st, nil
+	}
+}
+
+// Stop terminates the tunnel's process, if it is alive and still runs the
+// recorded command, and removes its state. Stopping a failed or stale tunnel
+// clears it without signalling anything.
+func Stop(dir, name string) error {
+	st, err := readState(dir, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("tunnel %q is not running", name)
+		}
+		return err
+	}
+	if running(st) {
+		if err := syscall.Kill(st.PID, syscall.SIGTERM); err != nil {
+			return fmt.Errorf("tunnel %q: stop pid %d: %w", name, st.PID, err)
+		}
+	}
+	_ = os.Remove(filepath.Join(dir, name+".log"))
+	return os.Remove(filepath.Join(dir, name+".json"))
+}
+
+// Check returns the status of the tunnel named name. A state whose pid is
+// alive but runs another command (the pid was reused, e.g. after a reboot)
+// is stale: it is removed and the tunnel reported as stopped.
+func Check(dir, name string) Info {
+	st, err := readState(dir, name)
+	if err != nil {
+		return Info{Status: Stopped}
+	}
+	if running(st) {
+		return Info{State: st, Status: Running}
+	}
+	if alive(st.PID) {
+		_ = os.Remove(filepath.Join(dir, name+".log"))
+		_ = os.Remove(filepath.Join(dir, name+".json"))
+		return Info{Status: Stopped}
+	}
+	msg := lastLine(filepath.Join(dir, name+".log"))
+	if msg == "" {
+		msg = "exited"
+	}
+	return Info{State: st, Status: Failed, Err: msg}
+}
+
+// running reports whether the process of st is alive and runs st.Command,
+// so a reused pid is neither reported as the tunnel nor signalled. Without
+// /proc (macOS) only liveness can be checked.
+func running(st State) bool {
+	if !alive(st.PID) {
+		return false
+	}
+	// #nosec G304 -- path is built from an integer pid.
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", st.PID))
+	if err != nil {
+		if _, statErr := os.Stat("/proc/self"); statErr != nil {
+			return true
+		}
+		return false
+	}
+	args := strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	return slices.Equal(args, st.Command)
+}
+
+func alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func readState(dir, name string) (State, error) {
+	var st State
+	// #nosec G304 -- path is derived from the XDG state dir and a validated name.
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+func writeState(dir string, st State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tunnel.*")
+	if err != nil {
+		return err
+	}
+	tmpPath := filepath.Clean(tmp.Name())
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(dir, st.Name+".json"))
+}
+
+// lastLine returns the last non-empty line of the file at path.
+func lastLine(path string) string {
+	// #nosec G304 -- path is derived from the XDG state dir and a validated name.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
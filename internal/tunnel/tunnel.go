@@ -0,0 +1,111 @@
+package tunnel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+// Spec is a configured tunnel and where it runs through.
+type Spec struct {
+	config.Tunnel
+	// Host is the ssh target: the [[hosts]] entry that defines the tunnel,
+	// or for group tunnels its host field (default: the first group member,
+	// static hosts before [[sources]] and match members).
+	Host string
+	// Group names the group that defines the tunnel; empty for host tunnels.
+	Group string
+}
+
+// List returns every tunnel of inv: host tunnels first, then group tunnels,
+// each in file order. res resolves the members of groups without a tunnel
+// host.
+func List(inv config.Inventory, res hosts.LoadResult) []Spec {
+	var out []Spec
+	for _, h := range inv.Hosts {
+		for _, t := range h.Tunnels {
+			out = append(out, Spec{Tunnel: t, Host: strings.TrimSpace(h.Host)})
+		}
+	}
+	for _, g := range inv.Groups {
+		for _, t := range g.Tunnels {
+			host := strings.TrimSpace(t.Host)
+			if host == "" {
+				if members := hosts.GroupMembers(inv, g, res); len(members) > 0 {
+					host = members[0]
+				}
+			}
+			out = append(out, Spec{Tunnel: t, Host: host, Group: g.Name})
+		}
+	}
+	return out
+}
+
+// Find returns the tunnel named name (see List).
+func Find(inv config.Inventory, res hosts.LoadResult, name string) (Spec, bool) {
+	name = strings.TrimSpace(name)
+	for _, s := range List(inv, res) {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Spec{}, false
+}
+
+// Chain returns the group chain the tunnel's ssh settings resolve through:
+// the defining group, or none for host tunnels.
+func (s Spec) Chain(inv config.Inventory) []config.Group {
+	if i := config.FindGroup(inv, s.Group); s.Group != "" && i >= 0 {
+		return []config.Group{inv.Groups[i]}
+	}
+	return nil
+}
+
+// Forward returns the ssh forwarding flag and its argument.
+func (s Spec) Forward() []string {
+	switch s.Type {
+	case config.TunnelLocal:
+		return []string{"-L", s.Listen + ":" + s.Target}
+	case config.TunnelRemote:
+		return []string{"-R", s.Listen + ":" + s.Target}
+	default:
+		return []string{"-D", s.Listen}
+	}
+}
+
+// Describe is a short human form, e.g. "5432 -> db:5432" or "socks 1080".
+func (s Spec) Describe() string {
+	switch s.Type {
+	case config.TunnelLocal:
+		return s.Listen + " -> " + s.Target
+	case config.TunnelRemote:
+		return "remote " + s.Listen + " -> " + s.Target
+	default:
+		return "socks " + s.Listen
+	}
+}
+
+// Command returns the background ssh command of the tunnel: no remote
+// command, no TTY, and exit when a forward can't be set up. mosh and et
+// cannot forward ports and fall back to ssh.
+func Command(s Spec, settings sshcmd.Settings) ([]string, error) {
+	if s.Host == "" {
+		return nil, fmt.Errorf("tunnel %q: group %q has no host to connect through: set the tunnel's host", s.Name, s.Group)
+	}
+	if container.IsTarget(s.Host) {
+		return nil, fmt.Errorf("tunnel %q: %s is a container, not an ssh host", s.Name, s.Host)
+	}
+	settings, n := sshcmd.ForBatch(settings)
+	settings.RemoteCommand = ""
+	cmd, err := sshcmd.BuildCommand(s.Host, settings)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-N", "-T", "-o", "ExitOnForwardFailure=yes"}
+	args = append(args, s.Forward()...)
+	return append(append(cmd[:n:n], args...), cmd[n:]...), nil
+}
@@ -0,0 +1,140 @@
+package tunnel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+func TestListAndCommand(t *testing.T) {
+	inv := config.Inventory{
+		Hosts: []config.Host{{Host: "bastion", Tunnels: []config.Tunnel{
+			{Name: "pg", Type: config.TunnelLocal, Listen: "5432", Target: "db:5432"},
+		}}},
+		Groups: []config.Group{
+			{Name: "prod", Hosts: []string{"web1", "web2"}, Tunnels: []config.Tunnel{
+				{Name: "socks", Type: config.TunnelDynamic, Listen: "1080"},
+				{Name: "hook", Type: config.TunnelRemote, Listen: "8080", Target: "localhost:3000", Host: "web2"},
+			}},
+			{Name: "empty", Tunnels: []config.Tunnel{{Name: "lost", Type: config.TunnelDynamic, Listen: "1081"}}},
+		},
+	}
+	specs := List(inv, hosts.LoadResult{})
+	var targets []string
+	for _, s := range specs {
+		targets = append(targets, s.Name+"@"+s.Host)
+	}
+	if want := []string{"pg@bastion", "socks@web1", "hook@web2", "lost@"}; !reflect.DeepEqual(targets, want) {
+		t.Fatalf("got=%#v\nwant=%#v", targets, want)
+	}
+
+	got, err := Command(specs[0], sshcmd.Settings{User: "ops", Port: 2222, RemoteCommand: "uptime"})
+	if err != nil {
+		t.Fatalf("Command: %v", err)
+	}
+	want := []string{"ssh", "-N", "-T", "-o", "ExitOnForwardFailure=yes", "-L", "5432:db:5432", "-p", "2222", "ops@bastion"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got=%#v\nwant=%#v", got, want)
+	}
+	if got, _ := Command(specs[2], sshcmd.Settings{}); !reflect.DeepEqual(got[5:7], []string{"-R", "8080:localhost:3000"}) {
+		t.Fatalf("remote: got=%#v", got)
+	}
+	if _, err := Command(specs[3], sshcmd.Settings{}); err == nil {
+		t.Fatalf("group without hosts: want error")
+	}
+
+	s, ok := Find(inv, hosts.LoadResult{}, "socks")
+	if !ok || len(s.Chain(inv)) != 1 || s.Chain(inv)[0].Name != "prod" {
+		t.Fatalf("Find/Chain: %#v ok=%v", s, ok)
+	}
+	if s, _ := Find(inv, hosts.LoadResult{}, "pg"); s.Chain(inv) != nil {
+		t.Fatalf("host tunnel chain: %#v", s.Chain(inv))
+	}
+}
+
+func TestListMatchedGroupHost(t *testing.T) {
+	inv := config.Inventory{Groups: []config.Group{
+		{Name: "web", Match: []string{"web*"}, Tunnels: []config.Tunnel{{Name: "socks", Type: config.TunnelDynamic, Listen: "1080"}}},
+	}}
+	list := []string{"db1", "web1", "web2"}
+	res := hosts.LoadResult{Hosts: list, Matched: hosts.MatchGroups(inv, list, nil)}
+	if s, ok := Find(inv, res, "socks"); !ok || s.Host != "web1" {
+		t.Fatalf("Find: %#v ok=%v, want the first matched host", s, ok)
+	}
+}
+
+func TestStartCheckStop(t *testing.T) {
+	startGrace = 200 * time.Millisecond
+	dir := t.TempDir()
+
+	st, err := Start(dir, "sleeper", []string{"sleep", "30"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if info := Check(dir, "sleeper"); info.Status != Running || info.State.PID != st.PID {
+		t.Fatalf("Check: %#v", info)
+	}
+	if _, err := Start(dir, "sleeper", []string{"sleep", "30"}); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("second Start: err=%v", err)
+	}
+	if err := Stop(dir, "sleeper"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if info := Check(dir, "sleeper"); info.Status != Stopped {
+		t.Fatalf("after Stop: %#v", info)
+	}
+	if err := Stop(dir, "sleeper"); err == nil {
+		t.Fatalf("Stop of a stopped tunnel: want error")
+	}
+}
+
+func TestStartReportsEarlyExit(t *testing.T) {
+	startGrace = 2 * time.Second
+	dir := t.TempDir()
+
+	_, err := Start(dir, "bad", []string{"sh", "-c", "echo 'bind: Address already in use' >&2; exit 255"})
+	if err == nil || !strings.Contains(err.Error(), "Address already in use") {
+		t.Fatalf("err=%v", err)
+	}
+	info := Check(dir, "bad")
+	if info.Status != Failed || info.Err != "bind: Address already in use" {
+		t.Fatalf("Check: %#v", info)
+	}
+	if err := Stop(dir, "bad"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
+
+func TestCheckStaleState(t *testing.T) {
+	if _, err := os.Stat("/proc/self"); err != nil {
+		t.Skip("no /proc")
+	}
+	dir := t.TempDir()
+	// The pid of this test process is alive but does not run the recorded
+	// command, like a pid reused after a reboot.
+	st := State{Name: "reused", PID: os.Getpid(), Command: []string{"ssh", "-N", "db"}, Started: time.Now()}
+	if err := writeState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	if info := Check(dir, "reused"); info.Status != Stopped {
+		t.Fatalf("Check: %#v", info)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "reused.json")); !os.IsNotExist(err) {
+		t.Fatalf("stale state kept: %v", err)
+	}
+
+	if err := writeState(dir, st); err != nil {
+		t.Fatal(err)
+	}
+	// Stop must clear the stale state without signalling this process.
+	if err := Stop(dir, "reused"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
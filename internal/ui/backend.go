@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+// backendChoices returns the values a form's backend field cycles through:
+// "" (inherit) first when inherit is set, then every registered backend.
+func backendChoices(inherit bool) []string {
+	var out []string
+	if inherit {
+		out = append(out, "")
+	}
+	return append(out, sshcmd.BackendNames()...)
+}
+
+// defaultBackend is the backend of d, "ssh" when unset.
+func defaultBackend(d config.Defaults) string {
+	if b := strings.TrimSpace(d.Backend); b != "" {
+		return b
+	}
+	return config.BackendSSH
+}
+
+// backendLine renders the backend choices as form segments; the current
+// value is boxed. emptyLabel names the "" choice.
+func backendLine(cur string, vals []string, emptyLabel string, focused bool) string {
+	cur = strings.TrimSpace(cur)
+	parts := make([]string, 0, len(vals))
+	for _, v := range vals {
+		text := v
+		if v == "" {
+			text = emptyLabel
+		}
+		if v != cur {
+			parts = append(parts, tabInactiveStyle.Render(text))
+			continue
+		}
+		box := "[" + text + "]"
+		if focused {
+			parts = append(parts, segFocusedStyle.Render(box))
+		} else {
+			parts = append(parts, checkedStyle.Render(box))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
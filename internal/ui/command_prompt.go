@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/history"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// commandHistoryLimit caps the command history file.
+const commandHistoryLimit = 100
+
+// commandPromptRows is how many snippets the prompt lists at once.
+const commandPromptRows = 6
+
+// commandHistoryMsg carries the command history after a typed command was
+// recorded, so every screen sees it.
+type commandHistoryMsg struct {
+	history []string
+}
+
+// commandChoice is the command picked in the command prompt; adhoc when it
+// was typed rather than taken from a snippet.
+type commandChoice struct {
+	command string
+	adhoc   bool
+}
+
+// commandPrompt is the modal of the connect-with-command action (Ctrl+O): a
+// command field that fuzzy-filters the offered snippets (Tab picks one) and
+// browses the command history (Up/Down).
+type commandPrompt struct {
+	crumb string
+	intro string
+
+	input    textinput.Model
+	query    string
+	snippets []config.Snippet
+	matches  []int // indices into snippets, best match first
+	sel      int   // index into matches; -1 runs the typed command
+	offset   int
+
+	history []string // oldest first
+	histIdx int      // len(history) when not browsing
+	draft   string   // typed value saved while browsing
+}
+
+func newCommandPrompt(width, height int, crumb, intro string, snippets []config.Snippet, hist []string) *commandPrompt {
+	p := &commandPrompt{crumb: crumb, intro: intro, snippets: snippets, sel: -1, history: hist, histIdx: len(hist)}
+	mw, mh := p.size(width, height)
+	innerW, _ := frameInnerSize(mw, mh)
+
+	in := textinput.New()
+	in.CharLimit = 512
+	in.Prompt = "cmd: "
+	in.Placeholder = "run on remote, keep session open"
+	if len(snippets) > 0 {
+		in.Placeholder = "run on remote, or search snippets"
+	}
+	in.Width = max(1, min(70, innerW-len(in.Prompt)))
+	in.Focus()
+	configureSearch(&in)
+	setSearchFocused(&in, true)
+	p.input = in
+	p.filter()
+	return p
+}
+
+func (p *commandPrompt) size(width, height int) (int, int) {
+	h := 9
+	if len(p.snippets) > 0 {
+		h += 2 + commandPromptRows
+	}
+	return modalSize(width, height, 88, h, 6, 10)
+}
+
+func (p *commandPrompt) refreshAccentStyles() {
+	setSearchFocused(&p.input, true)
+}
+
+// offeredSnippets returns the snippets the command prompt offers for a
+// connect through group (nil for none) to hostNames.
+func offeredSnippets(opts Options, group *config.Group, hostNames []string) []config.Snippet {
+	return hosts.OfferedSnippets(opts.Inventory, opts.Sourced, opts.Snippets, group, hostNames)
+}
+
+// rememberCommand records a typed command in the command history and
+// returns a command that hands the new history to the other screens.
+func rememberCommand(opts *Options, command string) tea.Cmd {
+	p, err := config.DefaultCommandHistoryPath()
+	if err != nil {
+		return nil
+	}
+	next, err := history.Add(p, []string{command}, commandHistoryLimit)
+	if err != nil {
+		return nil
+	}
+	opts.CommandHistory = next
+	return func() tea.Msg { return commandHistoryMsg{history: next} }
+}
+
+// filter re-matches the snippets against the typed value.
+func (p *commandPrompt) filter() {
+	p.query = strings.TrimSpace(p.input.Value())
+	p.sel = -1
+	p.offset = 0
+	p.matches = p.matches[:0]
+	if p.query == "" {
+		for i := range p.snippets {
+			p.matches = append(p.matches, i)
+		}
+		return
+	}
+	names := make([]string, len(p.snippets))
+	for i, s := range p.snippets {
+		names[i] = s.Name + " " + s.Description
+	}
+	for _, m := range fuzzy.Find(p.query, names) {
+		p.matches = append(p.matches, m.Index)
+	}
+}
+
+// moveSelection cycles through the matching snippets and back to the typed
+// command.
+func (p *commandPrompt) moveSelection(delta int) {
+	n := len(p.matches)
+	if n == 0 {
+		return
+	}
+	// Positions 0..n-1 are snippets, n is the typed command.
+	pos := p.sel
+	if pos < 0 {
+		pos = n
+	}
+	pos = (pos + delta + n + 1) % (n + 1)
+	if pos == n {
+		p.sel = -1
+		return
+	}
+	p.sel = pos
+	if p.sel < p.offset {
+		p.offset = p.sel
+	}
+	if p.sel >= p.offset+commandPromptRows {
+		p.offset = p.sel - commandPromptRows + 1
+	}
+}
+
+// browseHistory moves through the command history; -1 is older.
+func (p *commandPrompt) browseHistory(delta int) {
+	next := p.histIdx + delta
+	if next < 0 || next > len(p.history) {
+		return
+	}
+	if p.histIdx == len(p.history) {
+		p.draft = p.input.Value()
+	}
+	p.histIdx = next
+	if next == len(p.history) {
+		p.input.SetValue(p.draft)
+	} else {
+		p.input.SetValue(p.history[next])
+	}
+	p.input.CursorEnd()
+	p.filter()
+}
+
+// update handles a key. It returns done when the prompt closes, with the
+// command to connect with on Enter (nil on Esc or an empty command).
+func (p *commandPrompt) update(msg tea.KeyMsg) (choice *commandChoice, done bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return nil, true, nil
+	case "enter":
+		if p.sel >= 0 {
+			s := p.snippets[p.matches[p.sel]]
+			return &commandChoice{command: strings.TrimSpace(s.Command)}, true, nil
+		}
+		c := strings.TrimSpace(p.input.Value())
+		if c == "" {
+			return nil, true, nil
+		}
+		return &commandChoice{command: c, adhoc: true}, true, nil
+	case "tab":
+		p.moveSelection(1)
+		return nil, false, nil
+	case "shift+tab":
+		p.moveSelection(-1)
+		return nil, false, nil
+	case "up":
+		p.browseHistory(-1)
+		return nil, false, nil
+	case "down":
+		p.browseHistory(1)
+		return nil, false, nil
+	}
+	p.input, cmd = p.input.Update(msg)
+	if strings.TrimSpace(p.input.Value()) != p.query {
+		p.histIdx = len(p.history)
+		p.filter()
+	}
+	return nil, false, cmd
+}
+
+func (p *commandPrompt) View(width, height int) string {
+	mw, mh := p.size(width, height)
+	innerW, _ := frameInnerSize(mw, mh)
+
+	var lines []string
+	lines = append(lines, p.intro, "", p.input.View())
+	if len(p.snippets) > 0 {
+		lines = append(lines, "", dim.Render(fmt.Sprintf("Snippets (%d/%d):", len(p.matches), len(p.snippets))))
+		nameW := 0
+		for _, i := range p.matches {
+			nameW = max(nameW, len(p.snippets[i].Name))
+		}
+		nameW = min(nameW, 24)
+		end := min(len(p.matches), p.offset+commandPromptRows)
+		for row := p.offset; row < end; row++ {
+			s := p.snippets[p.matches[row]]
+			detail := s.Description
+			if detail == "" {
+				detail = s.Command
+			}
+			name := truncateTail(s.Name, nameW)
+			name += strings.Repeat(" ", max(0, nameW-len([]rune(name))))
+			detail = truncateTail(detail, innerW-nameW-4)
+			if row == p.sel {
+				line := "> " + name + "  " + detail
+				lines = append(lines, rowActiveStyle.Render(line+strings.Repeat(" ", max(0, innerW-lipgloss.Width(line)))))
+				continue
+			}
+			lines = append(lines, "  "+name+"  "+dim.Render(detail))
+		}
+	}
+	footer := "Enter connect  ↑/↓ history  Esc cancel"
+	if len(p.snippets) > 0 {
+		footer = "Enter connect  Tab snippet  ↑/↓ history  Esc cancel"
+	}
+	box := renderFrame(mw, mh, breadcrumbTitle(p.crumb, "Command"), "", strings.Join(lines, "\n"), footerStyle.Render(footer))
+	return placeCentered(width, height, box)
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -103,6 +106,62 @@ func connectConfirmBox(maxWidth int, count int, hostNames []string) string {
 	return strings.Join(parts, "\n")
 }
 
+// reuseAskBox asks whether to focus the pane already open for a connect or
+// to open a new window.
+func reuseAskBox(maxWidth int, p tmx.Pane) string {
+	boxW := maxWidth
+	if boxW <= 0 {
+		boxW = 60
+	}
+	boxW = min(60, max(24, boxW-4))
+	title := confirmTitleStyle.Render("Already open")
+	body := truncateTail(fmt.Sprintf("%s is open in %s", p.Host, p.Target()), boxW-2)
+	footer := footerKeyStyle.Render("[f/\u21b5]") + dim.Render(" focus") +
+		"   " + footerKeyStyle.Render("[n]") + dim.Render(" new window") +
+		"   " + footerKeyStyle.Render("[Esc]") + dim.Render(" cancel")
+	return renderConfirmBox(boxW+6, title, body, footer)
+}
+
+// preflightConfirmBox summarizes a group pre-flight check and lists the
+// unreachable hosts with the reason.
+func preflightConfirmBox(maxWidth int, rep probe.Report) string {
+	boxW := maxWidth
+	if boxW <= 0 {
+		boxW = 60
+	}
+	boxW = min(60, max(24, boxW-4))
+	totalW := boxW + 6
+	title := confirmTitleStyle.Render(fmt.Sprintf("%d up, %d down", len(rep.Up), len(rep.Down)))
+	footer := footerKeyStyle.Render("[o/\u21b5]") + dim.Render(" open reachable") +
+		"   " + footerKeyStyle.Render("[a]") + dim.Render(" open all") +
+		"   " + footerKeyStyle.Render("[n/Esc]") + dim.Render(" cancel")
+
+	parts := []string{boxTitleTop(totalW, title), boxLine(totalW, "")}
+	shown := len(rep.Down)
+	extra := 0
+	if shown > 4 {
+		shown = 4
+		extra = len(rep.Down) - 4
+	}
+	for i := 0; i < shown; i++ {
+		more := ""
+		if i == shown-1 && extra > 0 {
+			more = fmt.Sprintf("    +%d more", extra)
+		}
+		host := truncateTail(rep.Down[i], boxW-4-len(more))
+		line := "  " + statusErr.Render("●") + " " + host
+		if room := boxW - 6 - lipgloss.Width(host) - len(more); rep.Reasons[i] != "" && room >= 6 {
+			line += dim.Render("  " + truncateTail(rep.Reasons[i], room))
+		}
+		parts = append(parts, boxLine(totalW, line+more))
+	}
+	parts = append(parts, boxLine(totalW, ""))
+	parts = append(parts, boxLine(totalW, "  "+footer))
+	parts = append(parts, boxLine(totalW, ""))
+	parts = append(parts, boxBottom(totalW))
+	return strings.Join(parts, "\n")
+}
+
 func removeHostsConfirmBox(maxWidth int, hosts []string, groupName string) string {
 	boxW := maxWidth
 	if boxW <= 0 {
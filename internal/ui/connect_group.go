@@ -2,9 +2,9 @@ package ui
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"github.com/al-bashkir/ssh-tui/internal/mux"
 	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
 	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
 )
@@ -15,59 +15,38 @@ func (m *appModel) connectHostsWithDefaults(hostsToOpen []string) (execCmd []str
 	}
 
 	defaults := m.opts.Config.Defaults
-	base := sshcmd.FromDefaults(defaults)
-	sshCmds := make([][]string, 0, len(hostsToOpen))
-	for _, h := range hostsToOpen {
-		s := base
-		if hc, ok := hostConfigFor(m.opts.Inventory, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
-		}
-		cmd, _ := sshcmd.BuildCommand(h, s)
-		sshCmds = append(sshCmds, cmd)
+	plan, err := planConnect(m.opts, nil, hostsToOpen, nil)
+	if err != nil {
+		return nil, toast{}, err
 	}
+	sshCmds := plan.cmds
 
-	inTmux := tmx.InTmux()
-	mode := tmx.ResolveOpenMode(defaults.Tmux, defaults.OpenMode, inTmux)
+	win := resolveWindow(m.opts, nil)
+	inMux := insideMux(defaults)
+	mode := tmx.ResolveOpenMode(win.Tmux, win.OpenMode, inMux, len(sshCmds))
 
 	if mode == tmx.OpenCurrent {
 		if len(sshCmds) > 1 {
-			return nil, toast{}, fmt.Errorf("multi-host requires tmux (window or pane mode)")
+			return nil, toast{}, errMultiHostNoTmux
 		}
 		return sshCmds[0], toast{}, nil
 	}
 
-	if !inTmux {
-		if len(sshCmds) > 1 {
-			return nil, toast{}, fmt.Errorf("multi-host requires an active tmux session")
+	oneWindow := mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1)
+	mx := mux.Select(defaults.Multiplexer)
+	if !inMux {
+		if !mux.IsTmux(mx) {
+			return nil, toast{}, mux.NotInside(mx)
 		}
-		return tmx.NewSessionCmd(defaults.TmuxSession, sshCmds[0]), toast{}, nil
+		attach, err := openDetached(plan, defaults, nil, oneWindow)
+		return attach, toast{}, err
 	}
 
-	window := windowName(hostsToOpen[0])
-	ps := resolvePaneSettings(defaults, nil, len(sshCmds))
-	if mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1) {
-		if err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-			WindowName:       window,
-			PaneTitles:       hostsToOpen,
-			SplitFlag:        ps.SplitFlag,
-			Layout:           ps.Layout,
-			SyncPanes:        ps.SyncPanes,
-			PaneBorderFormat: ps.BorderFormat,
-			PaneBorderStatus: ps.BorderStatus,
-		}); err != nil {
-			return nil, toast{}, err
-		}
-		return nil, toast{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}, nil
+	t, err := openInMux(mx, plan, defaults, nil, oneWindow, "")
+	if err != nil {
+		return nil, toast{}, err
 	}
-
-	for i, sshCmd := range sshCmds {
-		tmuxCmd := tmx.NewWindowCmd(windowName(hostsToOpen[i]), sshCmd)
-		// #nosec G204 -- tmux argv is constructed (no shell) from known host/group settings.
-		if err := exec.Command(tmuxCmd[0], tmuxCmd[1:]...).Run(); err != nil {
-			return nil, toast{}, fmt.Errorf("tmux error: %s", err.Error())
-		}
-	}
-	return nil, toast{text: fmt.Sprintf("opened %d", len(sshCmds)), level: toastInfo}, nil
+	return nil, t, nil
 }
 
 func (m *appModel) connectHostsForGroup(groupIndex int, hostsToOpen []string, remoteCommandOverride string) (execCmd []string, toastResult toast, err error) {
@@ -80,76 +59,42 @@ func (m *appModel) connectHostsForGroup(groupIndex int, hostsToOpen []string, re
 
 	g := m.opts.Inventory.Groups[groupIndex]
 	defaults := m.opts.Config.Defaults
-	base := sshcmd.FromDefaults(defaults)
 	rc := strings.TrimSpace(remoteCommandOverride)
 
-	sshCmds := make([][]string, 0, len(hostsToOpen))
-	for _, h := range hostsToOpen {
-		s := base
-		if hc, ok := hostConfigFor(m.opts.Inventory, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
-		}
-		s = sshcmd.ApplyGroup(s, g)
-		if rc != "" {
-			s.RemoteCommand = rc
-		}
-		cmd, _ := sshcmd.BuildCommand(h, s)
-		sshCmds = append(sshCmds, cmd)
+	var modify func(*sshcmd.Settings)
+	if rc != "" {
+		modify = func(s *sshcmd.Settings) { s.RemoteCommand = rc }
 	}
-
-	tmuxSetting := defaults.Tmux
-	if strings.TrimSpace(g.Tmux) != "" {
-		tmuxSetting = g.Tmux
-	}
-	openModeSetting := defaults.OpenMode
-	if strings.TrimSpace(g.OpenMode) != "" {
-		openModeSetting = g.OpenMode
+	plan, err := planConnect(m.opts, &g, hostsToOpen, modify)
+	if err != nil {
+		return nil, toast{}, err
 	}
+	sshCmds := plan.cmds
 
-	inTmux := tmx.InTmux()
-	mode := tmx.ResolveOpenMode(tmuxSetting, openModeSetting, inTmux)
+	win := resolveWindow(m.opts, &g)
+	inMux := insideMux(defaults)
+	mode := tmx.ResolveOpenMode(win.Tmux, win.OpenMode, inMux, len(sshCmds))
 
 	if mode == tmx.OpenCurrent {
 		if len(sshCmds) > 1 {
-			return nil, toast{}, fmt.Errorf("multi-host requires tmux (window or pane mode)")
+			return nil, toast{}, errMultiHostNoTmux
 		}
 		return sshCmds[0], toast{}, nil
 	}
 
-	if !inTmux {
-		if len(sshCmds) > 1 {
-			return nil, toast{}, fmt.Errorf("multi-host requires an active tmux session")
-		}
-		return tmx.NewSessionCmd(defaults.TmuxSession, sshCmds[0]), toast{}, nil
-	}
-
-	window := strings.TrimSpace(g.Name)
-	if window == "" {
-		window = windowName(hostsToOpen[0])
-	}
-
-	ps := resolvePaneSettings(defaults, &g, len(sshCmds))
-	if mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1) {
-		if err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-			WindowName:       window,
-			PaneTitles:       hostsToOpen,
-			SplitFlag:        ps.SplitFlag,
-			Layout:           ps.Layout,
-			SyncPanes:        ps.SyncPanes,
-			PaneBorderFormat: ps.BorderFormat,
-			PaneBorderStatus: ps.BorderStatus,
-		}); err != nil {
-			return nil, toast{}, err
+	oneWindow := mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1)
+	mx := mux.Select(defaults.Multiplexer)
+	if !inMux {
+		if !mux.IsTmux(mx) {
+			return nil, toast{}, mux.NotInside(mx)
 		}
-		return nil, toast{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}, nil
+		attach, err := openDetached(plan, defaults, &g, oneWindow)
+		return attach, toast{}, err
 	}
 
-	for _, sshCmd := range sshCmds {
-		tmuxCmd := tmx.NewWindowCmd(window, sshCmd)
-		// #nosec G204 -- tmux argv is constructed (no shell) from known host/group settings.
-		if err := exec.Command(tmuxCmd[0], tmuxCmd[1:]...).Run(); err != nil {
-			return nil, toast{}, fmt.Errorf("tmux error: %s", err.Error())
-		}
+	t, err := openInMux(mx, plan, defaults, &g, oneWindow, "")
+	if err != nil {
+		return nil, toast{}, err
 	}
-	return nil, toast{text: fmt.Sprintf("opened %d", len(sshCmds)), level: toastInfo}, nil
+	return nil, t, nil
 }
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+	"github.com/al-bashkir/ssh-tui/internal/tmpl"
+	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
+)
+
+// connectPlan is a connect with its templates expanded: the client command,
+// tmux window name and pane title of every host.
+type connectPlan struct {
+	group   string // connected group, empty for a direct connect
+	hosts   []string
+	cmds    [][]string
+	windows []string // window of each host when it gets its own
+	panes   []string
+	reuse   string // reuse_window: new|focus|ask
+	keep    bool   // remain_on_exit: keep the panes after ssh exits
+}
+
+// window returns the name of the window holding all panes: that of the
+// first host.
+func (p connectPlan) window() string {
+	if len(p.windows) == 0 {
+		return "ssh"
+	}
+	return p.windows[0]
+}
+
+// tag is the @ssh_tui_host/@ssh_tui_group of the pane of host i.
+func (p connectPlan) tag(i int) tmx.Tag {
+	return tmx.Tag{Host: p.hosts[i], Group: p.group, Keep: p.keep}
+}
+
+// tags are the tags of every pane.
+func (p connectPlan) tags() []tmx.Tag {
+	out := make([]tmx.Tag, len(p.hosts))
+	for i := range p.hosts {
+		out[i] = p.tag(i)
+	}
+	return out
+}
+
+// findOpen returns the tagged tmux pane of the first host when every host
+// of p is already open: in a single window when oneWindow, each in a window
+// of its own otherwise. Only the setting allows reuse; see reuseOrOpen.
+func (p connectPlan) findOpen(oneWindow bool) (tmx.Pane, bool) {
+	if p.reuse != config.ReuseFocus && p.reuse != config.ReuseAsk {
+		return tmx.Pane{}, false
+	}
+	panes, err := tmx.ListPanes()
+	if err != nil {
+		return tmx.Pane{}, false
+	}
+	return tmx.FindOpen(panes, p.hosts, p.group, oneWindow)
+}
+
+// singlePlan is the plan of one prebuilt command, named after host.
+func singlePlan(host string, argv []string) connectPlan {
+	return connectPlan{hosts: []string{host}, cmds: [][]string{argv}, windows: []string{tmx.WindowName(host)}, panes: []string{host}}
+}
+
+// planConnect resolves the settings of every host of a connect through
+// group (nil for a direct connect), lets modify adjust them and builds the
+// commands. Template and command errors of all hosts are returned
+// together, before anything is launched.
+func planConnect(opts Options, group *config.Group, hostsToOpen []string, modify func(*sshcmd.Settings)) (connectPlan, error) {
+	// group.Hosts may hold the flattened members; resolve chains on the
+	// stored group.
+	var chains hosts.Chains
+	if group != nil {
+		stored := *group
+		if i := config.FindGroup(opts.Inventory, group.Name); i >= 0 {
+			stored = opts.Inventory.Groups[i]
+		}
+		chains = groupChains(opts, stored)
+	}
+	win := resolveWindow(opts, group)
+
+	p := connectPlan{hosts: hostsToOpen, reuse: strings.TrimSpace(win.ReuseWindow), keep: opts.Config.Defaults.RemainOnExit}
+	if group != nil {
+		p.group = group.Name
+	}
+	var errs tmpl.Errors
+	for _, h := range hostsToOpen {
+		var chain []config.Group
+		if group != nil {
+			chain = chains.For(*group, h)
+		}
+		in := resolveInput(opts, chain)
+		s := resolve.Resolve(in, h).SSH
+		if modify != nil {
+			modify(&s)
+		}
+		window, pane, err := resolve.Names(in, h, &s, win)
+		if err != nil {
+			errs = append(errs, tmpl.HostError{Host: h, Err: err})
+			continue
+		}
+		if window == "" {
+			window = tmx.GroupWindowName([]string{h}, group)
+		}
+		if pane == "" {
+			pane = h
+		}
+		cmd, err := sshcmd.BuildCommand(h, s)
+		if err != nil {
+			errs = append(errs, tmpl.HostError{Host: h, Err: err})
+			continue
+		}
+		p.cmds = append(p.cmds, cmd)
+		p.windows = append(p.windows, window)
+		p.panes = append(p.panes, pane)
+	}
+	if len(errs) > 0 {
+		return connectPlan{}, errs
+	}
+	return p, nil
+}
@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
-	"os/exec"
+	"slices"
+	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/mux"
 	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,68 +24,227 @@ type dispatchResult struct {
 }
 
 // dispatchConnect dispatches SSH commands based on the resolved open mode.
-// It handles OpenCurrent (single host, direct exec), new-session (not in tmux),
-// and in-tmux modes (pane, window, per-window).
+// It handles OpenCurrent (direct exec of a single host), the detached tmux
+// session (not in a multiplexer) and the in-multiplexer modes (pane, window,
+// per-window). inMux is insideMux(defaults).
 //
-// For async tmux operations it returns a tea.Cmd; otherwise result fields are set directly.
+// For async multiplexer operations it returns a tea.Cmd; otherwise result fields are set directly.
 func dispatchConnect(
-	hostsToOpen []string,
-	sshCmds [][]string,
+	plan connectPlan,
 	defaults config.Defaults,
 	group *config.Group,
 	mode tmx.OpenMode,
-	inTmux bool,
+	inMux bool,
 ) (result dispatchResult, cmd tea.Cmd) {
+	sshCmds := plan.cmds
 	if mode == tmx.OpenCurrent {
 		if len(sshCmds) > 1 {
-			return dispatchResult{toast: toast{text: "multi-host requires tmux (window or pane mode)", level: toastWarn}}, nil
+			return dispatchResult{toast: toast{text: errMultiHostNoTmux.Error(), level: toastWarn}}, nil
 		}
 		return dispatchResult{execCmd: sshCmds[0], quit: true}, nil
 	}
 
-	if !inTmux {
-		if len(sshCmds) > 1 {
-			return dispatchResult{toast: toast{text: "multi-host requires an active tmux session", level: toastWarn}}, nil
+	oneWindow := mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1)
+	mx := mux.Select(defaults.Multiplexer)
+	if !mux.IsTmux(mx) {
+		if !inMux {
+			return dispatchResult{toast: toast{text: mux.NotInside(mx).Error(), level: toastErr}}, nil
 		}
-		return dispatchResult{
-			execCmd: tmx.NewSessionCmd(defaults.TmuxSession, sshCmds[0]),
-			quit:    true,
-		}, nil
+		return dispatchResult{}, openInMuxCmd(mx, plan, defaults, group, oneWindow)
 	}
-
-	return dispatchResult{}, func() tea.Msg {
-		wName := tmuxWindowName(hostsToOpen, group)
-
-		if mode == tmx.OpenPane || (mode == tmx.OpenWindow && len(sshCmds) > 1) {
-			ps := resolvePaneSettings(defaults, group, len(sshCmds))
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       wName,
-				PaneTitles:       hostsToOpen,
-				SplitFlag:        ps.SplitFlag,
-				Layout:           ps.Layout,
-				SyncPanes:        ps.SyncPanes,
-				PaneBorderFormat: ps.BorderFormat,
-				PaneBorderStatus: ps.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg{text: err.Error(), level: toastErr}
+	// Hosts split across windows by max_panes_per_window are found open
+	// like hosts with a window each.
+	sameWindow := oneWindow && tmx.WindowCount(len(sshCmds), resolvePaneSettings(defaults, group, len(sshCmds)).MaxPanes) == 1
+	if !inMux {
+		detached := func() ([]string, error) { return openDetached(plan, defaults, group, oneWindow) }
+		if p, ok := plan.findOpen(sameWindow); ok {
+			if plan.reuse == config.ReuseAsk {
+				return dispatchResult{}, func() tea.Msg { return reuseAskMsg{pane: p, detached: detached} }
+			}
+			if err := tmx.SelectPane(p); err != nil {
+				return dispatchResult{toast: toast{text: err.Error(), level: toastErr}}, nil
 			}
-			return toastMsg{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}
+			return dispatchResult{execCmd: tmx.AttachCmd(p), quit: true}, nil
 		}
+		attach, err := detached()
+		if err != nil {
+			return dispatchResult{toast: toast{text: err.Error(), level: toastErr}}, nil
+		}
+		return dispatchResult{execCmd: attach, quit: true}, nil
+	}
+
+	return dispatchResult{}, reuseOrOpen(plan, sameWindow, openInMuxCmd(mx, plan, defaults, group, oneWindow))
+}
+
+// errMultiHostNoTmux rejects a multi-host connect with tmux = "never": the
+// current terminal runs one host (other modes open a window, see
+// tmx.ResolveOpenMode).
+var errMultiHostNoTmux = errors.New(`multi-host connect needs tmux: tmux is "never"`)
+
+// insideMux reports whether ssh-tui runs inside the multiplexer setting of
+// d selects.
+func insideMux(d config.Defaults) bool {
+	return mux.Select(d.Multiplexer).Inside()
+}
 
+// openInMuxCmd runs openInMux in the current window or session of mx and
+// reports the outcome as a toast.
+func openInMuxCmd(mx mux.Multiplexer, plan connectPlan, defaults config.Defaults, group *config.Group, oneWindow bool) tea.Cmd {
+	return func() tea.Msg {
+		t, err := openInMux(mx, plan, defaults, group, oneWindow, "")
+		if err != nil {
+			return toastMsg{text: err.Error(), level: toastErr}
+		}
+		return toastMsg(t)
+	}
+}
+
+// openInMux opens the hosts of plan with mx, in session ("" for the current
+// one, tmux only): as the panes of one window when oneWindow, in a window
+// each otherwise. It returns the toast reporting what was opened, a warning
+// naming the settings mx ignored.
+func openInMux(mx mux.Multiplexer, plan connectPlan, defaults config.Defaults, group *config.Group, oneWindow bool, session string) (toast, error) {
+	sshCmds := plan.cmds
+	var text string
+	var ignored []string
+	if oneWindow {
+		ps := resolvePaneSettings(defaults, group, len(sshCmds))
+		opts := tmx.OneWindowOpts{
+			Session:          session,
+			WindowName:       plan.window(),
+			PaneTitles:       plan.panes,
+			Tags:             plan.tags(),
+			SplitFlag:        ps.SplitFlag,
+			Layout:           ps.Layout,
+			SyncPanes:        ps.SyncPanes,
+			PaneBorderFormat: ps.BorderFormat,
+			PaneBorderStatus: ps.BorderStatus,
+			MaxPanes:         ps.MaxPanes,
+		}
+		if err := mx.OpenPanes(sshCmds, opts); err != nil {
+			return toast{}, err
+		}
+		ignored = mx.Unsupported(len(sshCmds), opts)
+		n := tmx.WindowCount(len(sshCmds), ps.MaxPanes)
+		if slices.Contains(ignored, "max_panes_per_window") {
+			n = 1
+		}
+		if n > 1 {
+			text = fmt.Sprintf("opened %d in %d windows", len(sshCmds), n)
+		} else {
+			text = fmt.Sprintf("opened %d in one window", len(sshCmds))
+		}
+	} else {
 		for i, sshCmd := range sshCmds {
-			name := tmuxWindowName(hostsToOpen[i:i+1], group)
-			tmuxCmd := tmx.NewWindowCmd(name, sshCmd)
-			// #nosec G204 -- tmux argv is constructed (no shell) from known host/group settings.
-			if err := exec.Command(tmuxCmd[0], tmuxCmd[1:]...).Run(); err != nil {
-				return toastMsg{text: "tmux error: " + err.Error(), level: toastErr}
+			if err := mx.NewWindow(session, plan.windows[i], sshCmd, plan.tag(i)); err != nil {
+				return toast{}, err
 			}
 		}
-		return toastMsg{text: fmt.Sprintf("opened %d", len(sshCmds)), level: toastInfo}
+		text = fmt.Sprintf("opened %d", len(sshCmds))
+	}
+	if !mux.IsTmux(mx) && (plan.reuse == config.ReuseFocus || plan.reuse == config.ReuseAsk) {
+		ignored = append(ignored, "reuse_window")
+	}
+	if len(ignored) > 0 {
+		return toast{text: fmt.Sprintf("%s (%s ignores %s)", text, mx.Name(), strings.Join(ignored, ", ")), level: toastWarn}, nil
+	}
+	return toast{text: text, level: toastInfo}, nil
+}
+
+// openDetached opens the hosts of plan in the tmux_session, started
+// detached when the server has no such session, and returns the command
+// attaching to it.
+func openDetached(plan connectPlan, defaults config.Defaults, group *config.Group, oneWindow bool) ([]string, error) {
+	session := tmx.SessionName(defaults.TmuxSession)
+	if _, err := openInMux(mux.Tmux{}, plan, defaults, group, oneWindow, session); err != nil {
+		return nil, err
+	}
+	return tmx.AttachSessionCmd(session), nil
+}
+
+// reuseAskMsg asks whether to focus pane, already open for the hosts of a
+// connect, or to open them again: by running open inside tmux, by running
+// detached and execing the attach command it returns outside.
+type reuseAskMsg struct {
+	pane     tmx.Pane
+	open     tea.Cmd
+	detached func() ([]string, error)
+}
+
+// defaultReuseWindow is the reuse_window of d, "new" when unset.
+func defaultReuseWindow(d config.Defaults) string {
+	if r := strings.TrimSpace(d.ReuseWindow); r != "" {
+		return r
+	}
+	return config.ReuseNew
+}
+
+// defaultMultiplexer is the multiplexer of d, "auto" when unset.
+func defaultMultiplexer(d config.Defaults) string {
+	if m := strings.TrimSpace(d.Multiplexer); m != "" {
+		return m
+	}
+	return config.MuxAuto
+}
+
+// reuseOrOpen wraps open, the command opening the tmux window(s) of plan:
+// unless plan.reuse is "new", the window already holding its hosts is
+// focused instead, or reuseAskMsg asks what to do.
+func reuseOrOpen(plan connectPlan, oneWindow bool, open tea.Cmd) tea.Cmd {
+	if plan.reuse != config.ReuseFocus && plan.reuse != config.ReuseAsk {
+		return open
+	}
+	return func() tea.Msg {
+		p, ok := plan.findOpen(oneWindow)
+		if !ok {
+			return open()
+		}
+		if plan.reuse == config.ReuseAsk {
+			return reuseAskMsg{pane: p, open: open}
+		}
+		return focusPane(p)()
+	}
+}
+
+// focusPane selects p and switches the client to it (inside tmux).
+func focusPane(p tmx.Pane) tea.Cmd {
+	return func() tea.Msg {
+		if err := tmx.Focus(p); err != nil {
+			return toastMsg{text: err.Error(), level: toastErr}
+		}
+		return toastMsg{text: fmt.Sprintf("focused %s (%s)", p.Host, p.Target()), level: toastInfo}
 	}
 }
 
-// tmuxWindowName returns a name for the tmux window, preferring the group name if set.
-func tmuxWindowName(hosts []string, group *config.Group) string {
-	return tmx.GroupWindowName(hosts, group)
+// answerReuse handles a key of the reuse_window = "ask" dialog: focus the
+// open pane, open a new window anyway or cancel. Outside tmux both answers
+// quit to exec the tmux client.
+func (m *appModel) answerReuse(k string) tea.Cmd {
+	ask := *m.reuseAsk
+	switch k {
+	case "f", "F", "enter":
+		m.reuseAsk = nil
+		if ask.detached == nil {
+			return focusPane(ask.pane)
+		}
+		if err := tmx.SelectPane(ask.pane); err != nil {
+			return func() tea.Msg { return toastMsg{text: err.Error(), level: toastErr} }
+		}
+		m.execCmd = tmx.AttachCmd(ask.pane)
+		return tea.Quit
+	case "n", "N":
+		m.reuseAsk = nil
+		if ask.detached == nil {
+			return ask.open
+		}
+		attach, err := ask.detached()
+		if err != nil {
+			return func() tea.Msg { return toastMsg{text: err.Error(), level: toastErr} }
+		}
+		m.execCmd = attach
+		return tea.Quit
+	case "esc", "q":
+		m.reuseAsk = nil
+	}
+	return nil
 }
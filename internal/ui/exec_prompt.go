@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// execSpec is a command run as asked for in the exec prompt, or a copy
+// asked for in the transfer prompt.
+type execSpec struct {
+	command  string
+	transfer *sshcmd.Transfer // set for a copy: scp instead of command
+	batch    int              // 0: all hosts at once (exec_parallel); 1: serial
+	halt     bool             // skip the remaining batches after a failure
+	pause    bool             // ask before every batch after the first
+}
+
+// runPrompt is a modal that asks for a run on the exec screen: the exec
+// prompt or the transfer prompt.
+type runPrompt interface {
+	update(msg tea.KeyMsg) (spec *execSpec, done bool, cmd tea.Cmd)
+	View(width, height int) string
+	refreshAccentStyles()
+}
+
+type execPromptField int
+
+const (
+	execFieldCommand execPromptField = iota
+	execFieldMode
+	execFieldBatch
+	execFieldHalt
+	execFieldPause
+	execFieldCount
+)
+
+const (
+	execModeParallel = "parallel"
+	execModeSerial   = "serial"
+	execModeBatch    = "batch"
+)
+
+// execPrompt is the modal of the exec action (`X`): the command and how to
+// roll it out over the hosts.
+type execPrompt struct {
+	crumb string
+	hosts int
+
+	focus   execPromptField
+	inCmd   textinput.Model
+	inBatch textinput.Model
+	mode    string
+	halt    bool
+	pause   bool
+	err     string
+}
+
+func newExecPrompt(width, height int, crumb string, hosts int) *execPrompt {
+	mw, mh := execPromptSize(width, height)
+	innerW, _ := frameInnerSize(mw, mh)
+
+	cmd := textinput.New()
+	cmd.CharLimit = 512
+	cmd.Prompt = ""
+	cmd.Placeholder = "run on every host, collect output"
+	cmd.Width = max(1, min(70, innerW-14))
+	cmd.Focus()
+	configureSearch(&cmd)
+	setSearchFocused(&cmd, true)
+
+	batch := textinput.New()
+	batch.CharLimit = 5
+	batch.Prompt = ""
+	batch.Placeholder = "2"
+	batch.Width = 6
+	configureSearch(&batch)
+	setSearchFocused(&batch, false)
+
+	return &execPrompt{crumb: crumb, hosts: hosts, inCmd: cmd, inBatch: batch, mode: execModeParallel}
+}
+
+func execPromptSize(width, height int) (int, int) {
+	return modalSize(width, height, 88, 14, 6, 6)
+}
+
+func (p *execPrompt) refreshAccentStyles() {
+	setSearchFocused(&p.inCmd, p.focus == execFieldCommand)
+	setSearchFocused(&p.inBatch, p.focus == execFieldBatch)
+}
+
+// fields returns the focusable fields; the batch size only in batch mode and
+// the rollout options only when hosts run in batches.
+func (p *execPrompt) fields() []execPromptField {
+	switch p.mode {
+	case execModeBatch:
+		return []execPromptField{execFieldCommand, execFieldMode, execFieldBatch, execFieldHalt, execFieldPause}
+	case execModeSerial:
+		return []execPromptField{execFieldCommand, execFieldMode, execFieldHalt, execFieldPause}
+	default:
+		return []execPromptField{execFieldCommand, execFieldMode}
+	}
+}
+
+func (p *execPrompt) setFocus(f execPromptField) {
+	p.focus = f
+	p.inCmd.Blur()
+	p.inBatch.Blur()
+	switch f {
+	case execFieldCommand:
+		p.inCmd.Focus()
+	case execFieldBatch:
+		p.inBatch.Focus()
+	}
+	p.refreshAccentStyles()
+}
+
+func (p *execPrompt) moveFocus(delta int) {
+	fields := p.fields()
+	idx := 0
+	for i, f := range fields {
+		if f == p.focus {
+			idx = i
+		}
+	}
+	idx = (idx + delta + len(fields)) % len(fields)
+	p.setFocus(fields[idx])
+}
+
+// update handles a key. It returns done when the prompt closes, with the
+// spec to run on Enter (nil on Esc).
+func (p *execPrompt) update(msg tea.KeyMsg) (spec *execSpec, done bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return nil, true, nil
+	case "enter":
+		s, err := p.spec()
+		if err != nil {
+			p.err = err.Error()
+			return nil, false, nil
+		}
+		return &s, true, nil
+	case "tab", "down":
+		p.moveFocus(1)
+		return nil, false, nil
+	case "shift+tab", "up":
+		p.moveFocus(-1)
+		return nil, false, nil
+	}
+
+	switch p.focus {
+	case execFieldCommand:
+		p.inCmd, cmd = p.inCmd.Update(msg)
+		return nil, false, cmd
+	case execFieldBatch:
+		p.inBatch, cmd = p.inBatch.Update(msg)
+		return nil, false, cmd
+	}
+
+	delta := 0
+	switch msg.String() {
+	case "h", "left":
+		delta = -1
+	case "l", "right", " ":
+		delta = 1
+	}
+	if delta == 0 {
+		return nil, false, nil
+	}
+	switch p.focus {
+	case execFieldMode:
+		p.mode = cycleChoice(p.mode, []string{execModeParallel, execModeSerial, execModeBatch}, delta)
+	case execFieldHalt:
+		p.halt = !p.halt
+	case execFieldPause:
+		p.pause = !p.pause
+	}
+	return nil, false, nil
+}
+
+func (p *execPrompt) spec() (execSpec, error) {
+	s := execSpec{command: strings.TrimSpace(p.inCmd.Value())}
+	if s.command == "" {
+		return s, fmt.Errorf("command required")
+	}
+	switch p.mode {
+	case execModeSerial:
+		s.batch = 1
+	case execModeBatch:
+		n, err := strconv.Atoi(strings.TrimSpace(p.inBatch.Value()))
+		if err != nil || n < 1 {
+			return s, fmt.Errorf("batch size must be a number >= 1")
+		}
+		s.batch = n
+	}
+	if s.batch > 0 {
+		s.halt = p.halt
+		s.pause = p.pause
+	}
+	return s, nil
+}
+
+func (p *execPrompt) View(width, height int) string {
+	mw, mh := execPromptSize(width, height)
+	labelW := 12
+	label := func(s string, focused bool) string {
+		padded := s + strings.Repeat(" ", max(0, labelW-len(s)))
+		if focused {
+			return headerStyle.Render(padded)
+		}
+		return padded
+	}
+	seg := func(on bool, text string, focused bool) string {
+		if on {
+			box := "[" + text + "]"
+			if focused {
+				return segFocusedStyle.Render(box)
+			}
+			return checkedStyle.Render(box)
+		}
+		return tabInactiveStyle.Render(text)
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Run a command on %d hosts and collect the output.", p.hosts), "")
+	lines = append(lines, label("Command:", p.focus == execFieldCommand)+" "+p.inCmd.View())
+	modeFocused := p.focus == execFieldMode
+	modeLine := seg(p.mode == execModeParallel, "parallel", modeFocused) + "  " +
+		seg(p.mode == execModeSerial, "serial", modeFocused) + "  " +
+		seg(p.mode == execModeBatch, "batch", modeFocused)
+	lines = append(lines, label("Mode:", modeFocused)+" "+modeLine)
+	if p.mode == execModeBatch {
+		lines = append(lines, label("Batch size:", p.focus == execFieldBatch)+" "+p.inBatch.View())
+	}
+	if p.mode != execModeParallel {
+		haltFocused := p.focus == execFieldHalt
+		lines = append(lines, label("On failure:", haltFocused)+" "+seg(!p.halt, "continue", haltFocused)+"  "+seg(p.halt, "halt", haltFocused))
+		pauseFocused := p.focus == execFieldPause
+		lines = append(lines, label("Batches:", pauseFocused)+" "+seg(!p.pause, "run on", pauseFocused)+"  "+seg(p.pause, "ask first", pauseFocused))
+	}
+	if p.err != "" {
+		lines = append(lines, "", statusErr.Render(p.err))
+	}
+	footer := footerStyle.Render("Enter run  Tab next  ←/→ change  Esc cancel")
+	box := renderFrame(mw, mh, breadcrumbTitle(p.crumb, "Exec"), "", strings.Join(lines, "\n"), footer)
+	return placeCentered(width, height, box)
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+)
+
+// explainView is the effective-settings preview of one host (the `i` key);
+// the CLI equivalent is `ssh-tui explain host`.
+type explainView struct {
+	title    string
+	settings resolve.Settings
+	command  string
+	warning  string // settings the backend ignores
+}
+
+func newExplainView(opts Options, chain []config.Group, host string) *explainView {
+	s := resolve.Resolve(resolveInput(opts, chain), host)
+	title := host
+	if len(chain) > 0 {
+		names := make([]string, 0, len(chain))
+		for _, g := range chain {
+			names = append(names, g.Name)
+		}
+		title += " (group " + strings.Join(names, " > ") + ")"
+	}
+	v := &explainView{title: title, settings: s}
+	cmd, err := sshcmd.BuildCommand(host, s.SSH)
+	if err != nil {
+		v.warning = err.Error()
+	} else {
+		v.command = strings.Join(cmd, " ")
+	}
+	if ignored := sshcmd.Ignored(s.SSH); len(ignored) > 0 {
+		v.warning = s.SSH.Backend + " ignores " + strings.Join(ignored, ", ")
+	}
+	return v
+}
+
+func explainBox(maxWidth int, v *explainView) string {
+	boxW := maxWidth
+	if boxW <= 0 {
+		boxW = 80
+	}
+	boxW = min(80, max(30, boxW-4))
+	totalW := boxW + 6
+	title := helpTitleStyle.Render(truncateTail(v.title, boxW-2))
+	footer := footerKeyStyle.Render("[Esc/i]") + dim.Render(" close")
+
+	nameW, originW := 0, 0
+	fields := v.settings.Fields()
+	for _, f := range fields {
+		nameW = max(nameW, len(f.Name))
+		originW = max(originW, len(f.Origin))
+	}
+	valueW := max(4, boxW-nameW-originW-4)
+
+	parts := []string{boxTitleTop(totalW, title), boxLine(totalW, "")}
+	for _, f := range fields {
+		value, origin := f.Value, string(f.Origin)
+		if value == "" {
+			value = "-"
+		}
+		if origin == "" {
+			origin = "-"
+		}
+		line := fmt.Sprintf("  %-*s  %s  %s", nameW, f.Name, dim.Render(fmt.Sprintf("%-*s", originW, origin)), truncateTail(value, valueW))
+		parts = append(parts, boxLine(totalW, line))
+	}
+	parts = append(parts, boxLine(totalW, ""))
+	if v.command != "" {
+		parts = append(parts, boxLine(totalW, "  "+dim.Render(truncateTail(v.command, boxW))))
+	}
+	if v.warning != "" {
+		parts = append(parts, boxLine(totalW, "  "+statusWarn.Render(truncateTail(v.warning, boxW))))
+	}
+	parts = append(parts, boxLine(totalW, ""))
+	parts = append(parts, boxLine(totalW, "  "+footer))
+	parts = append(parts, boxBottom(totalW))
+	return strings.Join(parts, "\n")
+}
@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
 	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
 )
 
@@ -23,6 +27,42 @@ func findHostConfig(inv config.Inventory, host string) (index int, hc config.Hos
 	return -1, config.Host{Host: strings.TrimSpace(host)}
 }
 
+// resolveInput returns the resolve.Input of a connect through chain (nil
+// for a direct connect).
+func resolveInput(opts Options, chain []config.Group) resolve.Input {
+	return resolve.Input{
+		Defaults:  opts.Config.Defaults,
+		Inventory: opts.Inventory,
+		Sourced:   opts.Sourced,
+		Chain:     chain,
+	}
+}
+
+// resolveSSH returns the effective ssh settings of host (see resolve.Resolve).
+func resolveSSH(opts Options, chain []config.Group, host string) sshcmd.Settings {
+	return resolve.Resolve(resolveInput(opts, chain), host).SSH
+}
+
+// resolveWindow returns the effective tmux settings of a connect through g
+// (nil for a direct connect).
+func resolveWindow(opts Options, g *config.Group) resolve.Window {
+	var chain []config.Group
+	if g != nil {
+		chain = []config.Group{*g}
+	}
+	return resolve.Resolve(resolveInput(opts, chain), "").Window
+}
+
+// groupMembers returns the static hosts of g plus the [[sources]] hosts that
+// declare membership in it and the hosts matched by its match patterns.
+func groupMembers(opts Options, g config.Group) []string {
+	return hosts.GroupMembers(opts.Inventory, g, opts.loadResult())
+}
+
+func groupChains(opts Options, g config.Group) hosts.Chains {
+	return hosts.MemberChains(opts.Inventory, g, opts.loadResult())
+}
+
 func isHostHidden(inv config.Inventory, host string) bool {
 	h := strings.TrimSpace(host)
 	for _, hh := range inv.HiddenHosts {
@@ -33,3 +73,58 @@ func isHostHidden(inv config.Inventory, host string) bool {
 	hc, ok := hostConfigFor(inv, host)
 	return ok && hc.Hidden
 }
+
+// hostBadgesFor returns the list badges of a host. Callers that show hidden
+// hosts set hidden themselves.
+func hostBadgesFor(opts Options, host string) hostBadges {
+	_, hasCfg := hostConfigFor(opts.Inventory, host)
+	_, fromSSHConfig := hosts.FindSSHConfig(opts.SSHConfig, host)
+	b := hostBadges{hasCfg: hasCfg, hashed: isHostHashed(opts, host), sshConfig: fromSSHConfig}
+	if t, ok := container.Parse(host); ok {
+		b.kind = t.Kind
+	}
+	if sh, ok := hosts.FindSourced(opts.Sourced, host); ok {
+		b.source = sh.Source
+	}
+	info := hosts.HostInfo(opts.Inventory, opts.Sourced, host)
+	b.tags = info.Tags
+	b.description = info.Description
+	s := resolveSSH(opts, nil, host)
+	b.setSSH(s)
+	b.setProbe(opts, s, host)
+	return b
+}
+
+// setSSH fills the badges of b that come from the effective settings s.
+func (b *hostBadges) setSSH(s sshcmd.Settings) {
+	b.jump = s.Jump
+	b.backend = ""
+	if s.Backend != "" && s.Backend != config.BackendSSH {
+		b.backend = s.Backend
+	}
+}
+
+// groupHostBadges returns hostBadgesFor(host) marked dynamic when host is a
+// member through match patterns or [[sources]] rather than a hosts list, and
+// with the included group it comes from when that is not g itself. Its jump
+// hops and backend include the group settings.
+func groupHostBadges(opts Options, g config.Group, chains hosts.Chains, host string) hostBadges {
+	b := hostBadgesFor(opts, host)
+	chain := chains.For(g, host)
+	leaf := chain[len(chain)-1]
+	b.dynamic = !hosts.IsStaticMember(leaf, host)
+	if len(chain) > 1 {
+		b.from = leaf.Name
+	}
+	s := resolveSSH(opts, chain, host)
+	b.setSSH(s)
+	b.setProbe(opts, s, host)
+	return b
+}
+
+// isHostHashed reports whether host was only found through a hashed
+// known_hosts entry. opts.HashedHosts is sorted.
+func isHostHashed(opts Options, host string) bool {
+	i := sort.SearchStrings(opts.HashedHosts, host)
+	return i < len(opts.HashedHosts) && opts.HashedHosts[i] == host
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/history"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+)
+
+// customHostHistoryLimit caps the custom-host history file.
+const customHostHistoryLimit = 200
+
+// loadHosts reloads every enabled host source (known_hosts or the
+// inventory, ~/.ssh/config, [[sources]], docker containers, pods) and
+// resolves hashed known_hosts entries. With refresh, sources are re-run even if their cache is fresh.
+func loadHosts(opts Options, refresh bool) (hosts.LoadResult, []hosts.PathError) {
+	cacheDir, _ := config.DefaultSourcesCacheDir()
+	return hosts.Load(hosts.Sources{
+		Defaults:   opts.Config.Defaults,
+		Inventory:  opts.Inventory,
+		KnownHosts: opts.KnownHosts,
+		History:    opts.CustomHostHistory,
+		Dynamic:    opts.Config.Sources,
+		CacheDir:   cacheDir,
+		Refresh:    refresh,
+	})
+}
+
+// hostReloadEnabled reports whether Reload has a source to re-read.
+func hostReloadEnabled(cfg config.Config) bool {
+	d := cfg.Defaults
+	return d.LoadKnownHosts || d.LoadSSHConfig || d.LoadDocker || d.LoadKubernetes || len(cfg.Sources) > 0
+}
+
+// loadErrorsToast summarizes host source errors, naming the first one.
+func loadErrorsToast(errs []hosts.PathError) toast {
+	switch len(errs) {
+	case 0:
+		return toast{}
+	case 1:
+		return toast{text: errs[0].Error(), level: toastErr}
+	default:
+		return toast{text: fmt.Sprintf("%s (+%d more)", errs[0].Error(), len(errs)-1), level: toastErr}
+	}
+}
+
+// rememberCustomHosts records hosts typed into the custom host prompt so that
+// later reloads can match them against hashed known_hosts entries.
+func (m *appModel) rememberCustomHosts(names []string) {
+	p, err := config.DefaultCustomHostHistoryPath()
+	if err != nil {
+		return
+	}
+	next, err := history.Add(p, names, customHostHistoryLimit)
+	if err != nil {
+		return
+	}
+	m.opts.CustomHostHistory = next
+	if m.hosts != nil {
+		m.hosts.opts.CustomHostHistory = next
+	}
+}
@@ -13,6 +13,7 @@ type keyMap struct {
 	Settings    key.Binding
 	CustomHost  key.Binding
 	HostConfig  key.Binding
+	Explain     key.Binding
 	ConnectCmd  key.Binding
 	ConnectSame key.Binding
 	ToggleSel   key.Binding
@@ -29,6 +30,18 @@ type keyMap struct {
 	Copy        key.Binding
 	HideHost    key.Binding
 	ShowHidden  key.Binding
+	TunnelStart key.Binding
+	TunnelStop  key.Binding
+	PaneJump    key.Binding
+	PaneKill    key.Binding
+	WindowKill  key.Binding
+	PaneRespawn key.Binding
+	Exec        key.Binding
+	ExecCancel  key.Binding
+	ExecCompare key.Binding
+	ExecSelect  key.Binding
+	Transfer    key.Binding
+	SFTP        key.Binding
 }
 
 func defaultKeyMap() keyMap {
@@ -73,6 +86,10 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("e"),
 			key.WithHelp("e", "config"),
 		),
+		Explain: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "effective settings"),
+		),
 		ConnectCmd: key.NewBinding(
 			key.WithKeys("ctrl+o"),
 			key.WithHelp("ctrl+o", "connect with custom command"),
@@ -137,6 +154,54 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("H"),
 			key.WithHelp("H", "show hidden"),
 		),
+		TunnelStart: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "start/stop tunnel"),
+		),
+		TunnelStop: key.NewBinding(
+			key.WithKeys("x", "d"),
+			key.WithHelp("x", "stop/clear tunnel"),
+		),
+		PaneJump: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "jump to pane"),
+		),
+		PaneKill: key.NewBinding(
+			key.WithKeys("x", "d"),
+			key.WithHelp("x", "kill pane"),
+		),
+		WindowKill: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "kill window"),
+		),
+		PaneRespawn: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "respawn dead pane"),
+		),
+		Exec: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "run command, collect output"),
+		),
+		ExecCancel: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "cancel run"),
+		),
+		ExecCompare: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "compare outputs"),
+		),
+		ExecSelect: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "select hosts of output"),
+		),
+		Transfer: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "copy files (scp)"),
+		),
+		SFTP: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "open sftp"),
+		),
 	}
 }
 
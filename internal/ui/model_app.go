@@ -7,6 +7,9 @@ import (
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
 	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	"github.com/al-bashkir/ssh-tui/internal/runner"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -23,6 +26,9 @@ const (
 	screenDefaultsForm
 	screenCustomHost
 	screenHostForm
+	screenTunnels
+	screenExec
+	screenSessions
 )
 
 type switchScreenMsg struct {
@@ -110,6 +116,12 @@ type hostFormSaveMsg struct {
 	host  config.Host
 }
 
+// selectHostsMsg replaces the Hosts selection with hosts and shows the
+// Hosts tab.
+type selectHostsMsg struct {
+	hosts []string
+}
+
 type toggleHiddenHostMsg struct {
 	host string
 	hide bool
@@ -139,6 +151,9 @@ type appModel struct {
 	defaultsForm       *defaultsFormModel
 	customHost         *customHostModel
 	hostForm           *hostFormModel
+	tunnels            *tunnelsModel
+	sessions           *sessionsModel
+	exec               *execModel
 	gpHosts            []string
 	gpReturnTo         screen
 	gpConnectAfterAdd  bool
@@ -148,22 +163,37 @@ type appModel struct {
 	hostFormReturnTo   screen
 	defaultsToastToken int
 	toastToken         int
+	tunnelsTicking     bool         // a tunnelsTickMsg is scheduled
+	sessionsTicking    bool         // a sessionsTickMsg is scheduled
+	reuseAsk           *reuseAskMsg // connect to hosts already open, waiting for an answer
 
 	quitting bool
 	execCmd  []string
 }
 
 func newAppModel(opts Options) *appModel {
+	if opts.Config.Defaults.Probe && opts.Probes == nil {
+		_, interval, err := config.ParseProbe(opts.Config.Defaults)
+		if err != nil {
+			interval = time.Minute
+		}
+		opts.Probes = probe.NewCache(interval)
+	}
 	m := &appModel{
-		opts:   opts,
-		screen: screenHosts,
-		hosts:  newHostsModel(opts),
-		groups: newGroupsModel(opts),
+		opts:     opts,
+		screen:   screenHosts,
+		hosts:    newHostsModel(opts),
+		groups:   newGroupsModel(opts),
+		tunnels:  newTunnelsModel(opts),
+		sessions: newSessionsModel(opts),
 	}
 	return m
 }
 
 func (m *appModel) Init() tea.Cmd {
+	if m.opts.Probes != nil {
+		return tea.Batch(m.hosts.Init(), probeTick())
+	}
 	return m.hosts.Init()
 }
 
@@ -217,6 +247,27 @@ func (m *appModel) applyWindowSize(ws tea.WindowSizeMsg) tea.Cmd {
 		}
 		cmds = append(cmds, cmd)
 	}
+	if m.tunnels != nil {
+		model, cmd := m.tunnels.Update(ws)
+		if tm, ok := model.(*tunnelsModel); ok {
+			m.tunnels = tm
+		}
+		cmds = append(cmds, cmd)
+	}
+	if m.sessions != nil {
+		model, cmd := m.sessions.Update(ws)
+		if sm, ok := model.(*sessionsModel); ok {
+			m.sessions = sm
+		}
+		cmds = append(cmds, cmd)
+	}
+	if m.exec != nil {
+		model, cmd := m.exec.Update(ws)
+		if em, ok := model.(*execModel); ok {
+			m.exec = em
+		}
+		cmds = append(cmds, cmd)
+	}
 	if m.defaultsForm != nil {
 		model, cmd := m.defaultsForm.Update(ws)
 		if dm, ok := model.(*defaultsFormModel); ok {
@@ -256,6 +307,18 @@ func (m *appModel) collectToastKey() string {
 		b.WriteByte('|')
 		b.WriteString(m.gh.toast.text)
 	}
+	if m.tunnels != nil && !m.tunnels.toast.empty() {
+		b.WriteByte('|')
+		b.WriteString(m.tunnels.toast.text)
+	}
+	if m.sessions != nil && !m.sessions.toast.empty() {
+		b.WriteByte('|')
+		b.WriteString(m.sessions.toast.text)
+	}
+	if m.exec != nil && !m.exec.toast.empty() {
+		b.WriteByte('|')
+		b.WriteString(m.exec.toast.text)
+	}
 	return b.String()
 }
 
@@ -269,6 +332,15 @@ func (m *appModel) clearToasts() {
 	if m.gh != nil {
 		m.gh.toast = toast{}
 	}
+	if m.tunnels != nil {
+		m.tunnels.toast = toast{}
+	}
+	if m.sessions != nil {
+		m.sessions.toast = toast{}
+	}
+	if m.exec != nil {
+		m.exec.toast = toast{}
+	}
 }
 
 func (m *appModel) maxToastLevel() toastLevel {
@@ -282,6 +354,15 @@ func (m *appModel) maxToastLevel() toastLevel {
 	if m.gh != nil && !m.gh.toast.empty() && m.gh.toast.level > lvl {
 		lvl = m.gh.toast.level
 	}
+	if m.tunnels != nil && !m.tunnels.toast.empty() && m.tunnels.toast.level > lvl {
+		lvl = m.tunnels.toast.level
+	}
+	if m.sessions != nil && !m.sessions.toast.empty() && m.sessions.toast.level > lvl {
+		lvl = m.sessions.toast.level
+	}
+	if m.exec != nil && !m.exec.toast.empty() && m.exec.toast.level > lvl {
+		lvl = m.exec.toast.level
+	}
 	return lvl
 }
 
@@ -296,6 +377,15 @@ func (m *appModel) breadcrumb() string {
 			return "Groups > " + m.gh.group.Name
 		}
 		return "Groups"
+	case screenTunnels:
+		return "Tunnels"
+	case screenSessions:
+		return "Sessions"
+	case screenExec:
+		if m.exec != nil {
+			return "Groups > " + m.exec.group + " > Exec"
+		}
+		return "Groups"
 	case screenDefaultsForm:
 		return "Settings"
 	default:
@@ -330,6 +420,21 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	result, cmd := m.doUpdate(msg)
 	cur := m.collectToastKey()
 
+	// The Tunnels tab polls the tunnel processes while it is shown.
+	if m.screen == screenTunnels && !m.tunnelsTicking {
+		m.tunnelsTicking = true
+		m.tunnels.opts = m.opts
+		m.tunnels.refresh()
+		cmd = tea.Batch(cmd, m.tunnels.tick())
+	}
+	// The Sessions tab re-lists the tmux panes while it is shown.
+	if m.screen == screenSessions && !m.sessionsTicking {
+		m.sessionsTicking = true
+		m.sessions.opts = m.opts
+		m.sessions.refresh()
+		cmd = tea.Batch(cmd, m.sessions.tick())
+	}
+
 	if cur != "" && cur != prev {
 		m.toastToken++
 		token := m.toastToken
@@ -343,12 +448,84 @@ func (m *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *appModel) doUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if km, ok := msg.(tea.KeyMsg); ok && m.reuseAsk != nil {
+		return m, m.answerReuse(km.String())
+	}
 	switch msg := msg.(type) {
+	case reuseAskMsg:
+		m.reuseAsk = &msg
+		return m, nil
 	case tea.WindowSizeMsg:
 		return m, m.applyWindowSize(msg)
 	case switchScreenMsg:
 		m.screen = msg.to
 		return m, nil
+	case tunnelsTickMsg:
+		if m.screen != screenTunnels {
+			m.tunnelsTicking = false
+			return m, nil
+		}
+		m.tunnels.opts = m.opts
+		m.tunnels.refresh()
+		return m, m.tunnels.tick()
+	case sessionsTickMsg:
+		if m.screen != screenSessions {
+			m.sessionsTicking = false
+			return m, nil
+		}
+		m.sessions.refresh()
+		return m, m.sessions.tick()
+	case probeTickMsg:
+		return m, tea.Batch(m.probeVisible(), probeTick())
+	case probeDoneMsg:
+		return m, m.probeVisible()
+	case preflightDoneMsg:
+		// The summary only makes sense where the connect was started.
+		if m.screen != screenGroups {
+			m.groups.toast = toast{}
+			return m, nil
+		}
+	case tunnelDoneMsg:
+		_, cmd := m.tunnels.Update(msg)
+		return m, cmd
+	case openExecMsg:
+		return m, m.openExec(msg)
+	case selectHostsMsg:
+		if m.exec != nil {
+			m.exec.stop()
+			m.exec = nil
+		}
+		m.hosts.selectOnly(msg.hosts)
+		m.screen = screenHosts
+		return m, nil
+	case execUpdateMsg:
+		if m.exec == nil {
+			return m, nil
+		}
+		_, cmd := m.exec.Update(msg)
+		return m, cmd
+	case commandHistoryMsg:
+		m.opts.CommandHistory = msg.history
+		if m.hosts != nil {
+			m.hosts.opts.CommandHistory = msg.history
+		}
+		if m.groups != nil {
+			m.groups.opts.CommandHistory = msg.history
+		}
+		if m.gh != nil {
+			m.gh.opts.CommandHistory = msg.history
+		}
+		return m, nil
+	case knownHostsReloadMsg:
+		// Keep the shared options in sync: group members can come from
+		// [[sources]], so group counts change on reload too.
+		m.opts.SetLoadResult(msg.res, msg.errs)
+		if m.groups != nil {
+			m.groups.opts = m.opts
+			m.groups.Refresh(m.opts.Inventory)
+		}
+		_, cmd := m.hosts.Update(msg)
+		return m, cmd
 	case openGroupFormMsg:
 		var g config.Group
 		if msg.index >= 0 && msg.index < len(m.opts.Inventory.Groups) {
@@ -527,6 +704,7 @@ func (m *appModel) doUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.screen = screenGroupPicker
 		return m, nil
 	case customHostConnectMsg:
+		m.rememberCustomHosts(msg.hosts)
 		var execCmd []string
 		var toastResult toast
 		var err error
@@ -810,12 +988,53 @@ func (m *appModel) doUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.hostForm = hm
 		}
 		return m, cmd
+	case screenTunnels:
+		model, cmd := m.tunnels.Update(msg)
+		if tm, ok := model.(*tunnelsModel); ok {
+			m.tunnels = tm
+			if tm.quitting {
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+		return m, cmd
+	case screenSessions:
+		model, cmd := m.sessions.Update(msg)
+		if sm, ok := model.(*sessionsModel); ok {
+			m.sessions = sm
+			if len(sm.execCmd) != 0 {
+				m.execCmd = sm.execCmd
+				return m, tea.Quit
+			}
+			if sm.quitting {
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+		return m, cmd
+	case screenExec:
+		model, cmd := m.exec.Update(msg)
+		if em, ok := model.(*execModel); ok {
+			m.exec = em
+			if em.quitting {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			if em.closed {
+				m.screen = em.returnTo
+				m.exec = nil
+			}
+		}
+		return m, cmd
 	default:
 		return m, nil
 	}
 }
 
 func (m *appModel) View() string {
+	if m.reuseAsk != nil {
+		return placeCentered(m.width, m.height, reuseAskBox(m.width, m.reuseAsk.pane))
+	}
 	switch m.screen {
 	case screenGroups:
 		return m.groups.View()
@@ -833,6 +1052,12 @@ func (m *appModel) View() string {
 		return placeCentered(m.width, m.height, m.customHost.View())
 	case screenHostForm:
 		return placeCentered(m.width, m.height, m.hostForm.View())
+	case screenTunnels:
+		return m.tunnels.View()
+	case screenSessions:
+		return m.sessions.View()
+	case screenExec:
+		return m.exec.View()
 	default:
 		return m.hosts.View()
 	}
@@ -855,28 +1080,58 @@ func (m *appModel) saveGroup(index int, g config.Group) error {
 	}
 
 	newInv := m.opts.Inventory
+	newInv.Groups = append([]config.Group(nil), newInv.Groups...)
 	if index < 0 {
 		newInv.Groups = append(newInv.Groups, g)
 	} else {
 		if index >= len(newInv.Groups) {
 			return fmt.Errorf("invalid group index")
 		}
+		if oldName := strings.TrimSpace(newInv.Groups[index].Name); oldName != g.Name {
+			newInv.Groups = config.RenameGroupRefs(newInv.Groups, oldName, g.Name)
+		}
 		newInv.Groups[index] = g
 	}
+	if err := config.CheckIncludes(newInv); err != nil {
+		return err
+	}
 
 	if _, err := config.SaveInventory(m.opts.InventoryPath, newInv); err != nil {
 		return err
 	}
 
 	m.opts.Inventory = newInv
+	m.rematchGroups()
 	// Propagate to screens.
 	m.hosts.opts.Inventory = newInv
 	m.groups.Refresh(newInv)
 	return nil
 }
 
+// rematchGroups re-evaluates group match patterns after an inventory change
+// (renamed groups, edited host tags or meta) and shares the result.
+func (m *appModel) rematchGroups() {
+	m.opts.Matched = hosts.MatchGroups(m.opts.Inventory, m.opts.Hosts, m.opts.Sourced)
+	if m.hosts != nil {
+		m.hosts.opts.Matched = m.opts.Matched
+	}
+	if m.groups != nil {
+		m.groups.opts.Matched = m.opts.Matched
+	}
+	if m.gh != nil {
+		m.gh.opts.Matched = m.opts.Matched
+	}
+	if m.picker != nil {
+		m.picker.opts.Matched = m.opts.Matched
+	}
+	if m.gp != nil {
+		m.gp.opts.Matched = m.opts.Matched
+	}
+}
+
 func (m *appModel) saveDefaults(d config.Defaults) error {
 	oldLoadKnownHosts := m.opts.Config.Defaults.LoadKnownHosts
+	oldLoadSSHConfig := m.opts.Config.Defaults.LoadSSHConfig
 	oldKnownPaths := append([]string(nil), m.opts.KnownHosts...)
 
 	newCfg := m.opts.Config
@@ -889,8 +1144,8 @@ func (m *appModel) saveDefaults(d config.Defaults) error {
 	SetAccentColor(newCfg.Defaults.AccentColor)
 	m.refreshAccentStyles()
 
-	// Update hosts source if load_known_hosts toggled.
-	if oldLoadKnownHosts != newCfg.Defaults.LoadKnownHosts {
+	// Update hosts source if load_known_hosts or load_ssh_config toggled.
+	if oldLoadKnownHosts != newCfg.Defaults.LoadKnownHosts || oldLoadSSHConfig != newCfg.Defaults.LoadSSHConfig {
 		if newCfg.Defaults.LoadKnownHosts {
 			if len(oldKnownPaths) != 0 {
 				m.opts.KnownHosts = oldKnownPaths
@@ -898,21 +1153,16 @@ func (m *appModel) saveDefaults(d config.Defaults) error {
 			if len(m.opts.KnownHosts) == 0 {
 				m.opts.KnownHosts = hosts.DefaultKnownHostsPaths()
 			}
-			res, errs := hosts.LoadKnownHosts(m.opts.KnownHosts)
-			m.opts.Hosts = res.Hosts
-			m.opts.SkippedLines = res.SkippedLines
-			m.opts.LoadErrors = errs
 		} else {
 			m.opts.KnownHosts = nil
-			m.opts.Hosts = config.ConfigHosts(m.opts.Inventory)
-			m.opts.SkippedLines = 0
-			m.opts.LoadErrors = nil
 		}
+		res, errs := loadHosts(m.opts, false)
+		m.opts.SetLoadResult(res, errs)
 
 		if m.hosts != nil {
-			m.hosts.keymap.Reload.SetEnabled(newCfg.Defaults.LoadKnownHosts)
+			m.hosts.keymap.Reload.SetEnabled(hostReloadEnabled(newCfg))
 			m.hosts.opts = m.opts
-			_, _ = m.hosts.Update(knownHostsReloadMsg{res: hosts.LoadResult{Hosts: m.opts.Hosts, SkippedLines: m.opts.SkippedLines}, errs: m.opts.LoadErrors})
+			_, _ = m.hosts.Update(knownHostsReloadMsg{res: m.opts.loadResult(), errs: m.opts.LoadErrors})
 		}
 		if m.picker != nil {
 			// Recreate to refresh list source.
@@ -948,20 +1198,29 @@ func (m *appModel) saveDefaults(d config.Defaults) error {
 func (m *appModel) refreshAccentStyles() {
 	if m.hosts != nil {
 		setSearchBarFocused(&m.hosts.search, m.hosts.focus == focusSearch)
-		if m.hosts.cmdPrompt {
-			setSearchFocused(&m.hosts.cmdInput, true)
+		if m.hosts.cmdPr != nil {
+			m.hosts.cmdPr.refreshAccentStyles()
+		}
+		if m.hosts.execPr != nil {
+			m.hosts.execPr.refreshAccentStyles()
 		}
 	}
 	if m.groups != nil {
 		setSearchBarFocused(&m.groups.search, m.groups.focus == focusSearch)
-		if m.groups.cmdPrompt {
-			setSearchFocused(&m.groups.cmdInput, true)
+		if m.groups.cmdPr != nil {
+			m.groups.cmdPr.refreshAccentStyles()
+		}
+		if m.groups.execPr != nil {
+			m.groups.execPr.refreshAccentStyles()
 		}
 	}
 	if m.gh != nil {
 		setSearchBarFocused(&m.gh.search, m.gh.focus == focusSearch)
-		if m.gh.cmdPrompt {
-			setSearchFocused(&m.gh.cmdInput, true)
+		if m.gh.cmdPr != nil {
+			m.gh.cmdPr.refreshAccentStyles()
+		}
+		if m.gh.execPr != nil {
+			m.gh.execPr.refreshAccentStyles()
 		}
 	}
 	if m.picker != nil {
@@ -1016,6 +1275,7 @@ func (m *appModel) saveHostConfig(index int, h config.Host) error {
 	}
 
 	m.opts.Inventory = newInv
+	m.rematchGroups()
 	if m.hosts != nil {
 		m.hosts.opts.Inventory = newInv
 	}
@@ -1044,13 +1304,16 @@ func (m *appModel) deleteGroup(index int) error {
 
 	newInv := m.opts.Inventory
 	newInv.Groups = append([]config.Group(nil), newInv.Groups...)
+	name := strings.TrimSpace(newInv.Groups[index].Name)
 	newInv.Groups = append(newInv.Groups[:index], newInv.Groups[index+1:]...)
+	newInv.Groups = config.RenameGroupRefs(newInv.Groups, name, "")
 
 	if _, err := config.SaveInventory(m.opts.InventoryPath, newInv); err != nil {
 		return err
 	}
 
 	m.opts.Inventory = newInv
+	m.rematchGroups()
 	m.hosts.opts.Inventory = newInv
 	m.groups.Refresh(newInv)
 	return nil
@@ -1204,3 +1467,58 @@ func (m *appModel) IsQuitting() bool { return m.quitting }
 func (m *appModel) ExecCmd() []string {
 	return m.execCmd
 }
+
+// openExec starts msg.spec on the hosts of a group, or on hosts of the
+// Hosts tab, and shows the exec screen. Errors are reported on the screen
+// the run was started from.
+func (m *appModel) openExec(msg openExecMsg) tea.Cmd {
+	fail := func(text string) tea.Cmd {
+		t := toast{text: text, level: toastErr}
+		switch {
+		case msg.returnTo == screenGroupHosts && m.gh != nil:
+			m.gh.toast = t
+		case msg.returnTo == screenHosts:
+			m.hosts.toast = t
+		default:
+			m.groups.toast = t
+		}
+		return nil
+	}
+	if msg.groupIndex < 0 {
+		if len(msg.hosts) == 0 {
+			return fail("no host selected")
+		}
+		targets, err := execTargets(msg.hosts, func(h string) sshcmd.Settings { return resolveSSH(m.opts, nil, h) }, msg.spec)
+		if err != nil {
+			return fail(err.Error())
+		}
+		return m.showExec("", msg, targets)
+	}
+	if msg.groupIndex >= len(m.opts.Inventory.Groups) {
+		return fail("invalid group")
+	}
+	g := m.opts.Inventory.Groups[msg.groupIndex]
+	hostList := msg.hosts
+	if len(hostList) == 0 {
+		hostList = groupMembers(m.opts, g)
+	}
+	if len(hostList) == 0 {
+		return fail("group has no hosts")
+	}
+	chains := groupChains(m.opts, g)
+	targets, err := execTargets(hostList, func(h string) sshcmd.Settings { return resolveSSH(m.opts, chains.For(g, h), h) }, msg.spec)
+	if err != nil {
+		return fail(err.Error())
+	}
+	return m.showExec(g.Name, msg, targets)
+}
+
+// showExec switches to the exec screen and starts targets.
+func (m *appModel) showExec(group string, msg openExecMsg, targets []runner.Target) tea.Cmd {
+	m.exec = newExecModel(m.opts, group, msg.spec, msg.returnTo, targets)
+	if m.width > 0 && m.height > 0 {
+		_, _ = m.exec.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	m.screen = screenExec
+	return m.exec.start(targets)
+}
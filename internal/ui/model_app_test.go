@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+)
+
+func TestMatchedMembersAtStart(t *testing.T) {
+	inv := config.Inventory{Version: 1, Groups: []config.Group{{Name: "web", Hosts: []string{"db1"}, Match: []string{"web*"}}}}
+	list := []string{"db1", "web1", "web2"}
+	opts := Options{Config: config.DefaultConfig(), Inventory: inv}
+	opts.SetLoadResult(hosts.LoadResult{Hosts: list, Matched: hosts.MatchGroups(inv, list, nil)}, nil)
+
+	m := newAppModel(opts)
+	if got := m.groups.allRows[0].hostCount; got != 3 {
+		t.Fatalf("groups screen host count = %d, want 3", got)
+	}
+	if got := groupMembers(m.groups.opts, inv.Groups[0]); !slices.Contains(got, "web1") {
+		t.Fatalf("members = %q, want the matched web1", got)
+	}
+}
@@ -25,16 +25,26 @@ const (
 	defaultsFieldPort
 	defaultsFieldIdentity
 	defaultsFieldExtraArgs
+	defaultsFieldJump
+	defaultsFieldBackend
 	defaultsFieldAccentColor
 	defaultsFieldLoadKnownHosts
+	defaultsFieldLoadSSHConfig
+	defaultsFieldLoadDocker
+	defaultsFieldLoadKubernetes
+	defaultsFieldMultiplexer
 	defaultsFieldTmux
 	defaultsFieldOpenMode
+	defaultsFieldReuseWindow
 	defaultsFieldTmuxSession
 	defaultsFieldConfirmQuit
 	defaultsFieldConnectThreshold
+	defaultsFieldExecParallel
+	defaultsFieldExecTimeout
 	defaultsFieldPaneSplit
 	defaultsFieldPaneLayout
 	defaultsFieldPaneSync
+	defaultsFieldMaxPanes
 	defaultsFieldPaneBorderStatus
 	defaultsFieldPaneBorderFormat
 )
@@ -52,8 +62,12 @@ type defaultsFormModel struct {
 	inPort      textinput.Model
 	inIdentity  textinput.Model
 	inExtra     textinput.Model
+	inJump      textinput.Model
 	inSession   textinput.Model
 	inThreshold textinput.Model
+	inParallel  textinput.Model
+	inTimeout   textinput.Model
+	inMaxPanes  textinput.Model
 
 	borderPicker *paneBorderFormatsModel
 
@@ -70,8 +84,12 @@ func (m *defaultsFormModel) refreshAccentStyles() {
 	setSearchFocused(&m.inPort, m.focus == defaultsFieldPort)
 	setSearchFocused(&m.inIdentity, m.focus == defaultsFieldIdentity)
 	setSearchFocused(&m.inExtra, m.focus == defaultsFieldExtraArgs)
+	setSearchFocused(&m.inJump, m.focus == defaultsFieldJump)
 	setSearchFocused(&m.inSession, m.focus == defaultsFieldTmuxSession)
 	setSearchFocused(&m.inThreshold, m.focus == defaultsFieldConnectThreshold)
+	setSearchFocused(&m.inParallel, m.focus == defaultsFieldExecParallel)
+	setSearchFocused(&m.inTimeout, m.focus == defaultsFieldExecTimeout)
+	setSearchFocused(&m.inMaxPanes, m.focus == defaultsFieldMaxPanes)
 	if m.borderPicker != nil {
 		m.borderPicker.refreshAccentStyles()
 	}
@@ -108,6 +126,13 @@ func newDefaultsFormModel(d config.Defaults, confirmQuitEnabled bool) *defaultsF
 	extra.Placeholder = "-o Option=value ..."
 	configureSearch(&extra)
 
+	jump := textinput.New()
+	jump.CharLimit = 512
+	jump.Prompt = ""
+	jump.SetValue(strings.Join(d.Jump, ", "))
+	jump.Placeholder = "bastion, admin@gw:2222"
+	configureSearch(&jump)
+
 	session := textinput.New()
 	session.CharLimit = 128
 	session.Prompt = ""
@@ -124,6 +149,31 @@ func newDefaultsFormModel(d config.Defaults, confirmQuitEnabled bool) *defaultsF
 	threshold.Placeholder = "5"
 	configureSearch(&threshold)
 
+	parallel := textinput.New()
+	parallel.CharLimit = 4
+	parallel.Prompt = ""
+	if d.ExecParallel > 0 {
+		parallel.SetValue(strconv.Itoa(d.ExecParallel))
+	}
+	parallel.Placeholder = strconv.Itoa(config.DefaultExecParallel)
+	configureSearch(&parallel)
+
+	timeout := textinput.New()
+	timeout.CharLimit = 16
+	timeout.Prompt = ""
+	timeout.SetValue(strings.TrimSpace(d.ExecTimeout))
+	timeout.Placeholder = config.DefaultExecTimeout
+	configureSearch(&timeout)
+
+	maxPanes := textinput.New()
+	maxPanes.CharLimit = 4
+	maxPanes.Prompt = ""
+	if d.MaxPanesPerWindow > 0 {
+		maxPanes.SetValue(strconv.Itoa(d.MaxPanesPerWindow))
+	}
+	maxPanes.Placeholder = "no limit"
+	configureSearch(&maxPanes)
+
 	m := &defaultsFormModel{
 		defaults:           d,
 		focus:              defaultsFieldUser,
@@ -131,8 +181,12 @@ func newDefaultsFormModel(d config.Defaults, confirmQuitEnabled bool) *defaultsF
 		inPort:             port,
 		inIdentity:         identity,
 		inExtra:            extra,
+		inJump:             jump,
 		inSession:          session,
 		inThreshold:        threshold,
+		inParallel:         parallel,
+		inTimeout:          timeout,
+		inMaxPanes:         maxPanes,
 		keymap:             defaultKeyMap(),
 		confirmQuitEnabled: confirmQuitEnabled,
 	}
@@ -142,8 +196,12 @@ func newDefaultsFormModel(d config.Defaults, confirmQuitEnabled bool) *defaultsF
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
 	setSearchFocused(&m.inSession, false)
 	setSearchFocused(&m.inThreshold, false)
+	setSearchFocused(&m.inParallel, false)
+	setSearchFocused(&m.inTimeout, false)
+	setSearchFocused(&m.inMaxPanes, false)
 	return m
 }
 
@@ -169,8 +227,12 @@ func (m *defaultsFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.inPort.Width = min(12, fieldW)
 		m.inIdentity.Width = fieldW
 		m.inExtra.Width = fieldW
+		m.inJump.Width = fieldW
 		m.inSession.Width = fieldW
 		m.inThreshold.Width = min(12, fieldW)
+		m.inParallel.Width = min(12, fieldW)
+		m.inTimeout.Width = min(12, fieldW)
+		m.inMaxPanes.Width = min(12, fieldW)
 		if m.borderPicker != nil {
 			mw, mh := pickerModalSize(msg.Width, msg.Height)
 			_, _ = m.borderPicker.Update(tea.WindowSizeMsg{Width: mw, Height: mh})
@@ -267,18 +329,45 @@ func (m *defaultsFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				delta = -1
 			}
 			switch m.focus {
+			case defaultsFieldBackend:
+				m.defaults.Backend = cycleChoice(defaultBackend(m.defaults), backendChoices(false), delta)
+				if m.defaults.Backend == config.BackendSSH {
+					m.defaults.Backend = ""
+				}
+				return m, nil
 			case defaultsFieldAccentColor:
 				m.defaults.AccentColor = cycleChoice(m.defaults.AccentColor, []string{"", "blue", "cyan", "green", "amber", "red", "magenta"}, delta)
 				return m, nil
 			case defaultsFieldLoadKnownHosts:
 				m.defaults.LoadKnownHosts = !m.defaults.LoadKnownHosts
 				return m, nil
+			case defaultsFieldLoadSSHConfig:
+				m.defaults.LoadSSHConfig = !m.defaults.LoadSSHConfig
+				return m, nil
+			case defaultsFieldLoadDocker:
+				m.defaults.LoadDocker = !m.defaults.LoadDocker
+				return m, nil
+			case defaultsFieldLoadKubernetes:
+				m.defaults.LoadKubernetes = !m.defaults.LoadKubernetes
+				return m, nil
+			case defaultsFieldMultiplexer:
+				m.defaults.Multiplexer = cycleChoice(defaultMultiplexer(m.defaults), []string{config.MuxAuto, config.MuxTmux, config.MuxZellij, config.MuxScreen}, delta)
+				if m.defaults.Multiplexer == config.MuxAuto {
+					m.defaults.Multiplexer = ""
+				}
+				return m, nil
 			case defaultsFieldTmux:
 				m.defaults.Tmux = cycleChoice(m.defaults.Tmux, []string{"auto", "force", "never"}, delta)
 				return m, nil
 			case defaultsFieldOpenMode:
 				m.defaults.OpenMode = cycleChoice(m.defaults.OpenMode, []string{"auto", "current", "tmux-window", "tmux-pane"}, delta)
 				return m, nil
+			case defaultsFieldReuseWindow:
+				m.defaults.ReuseWindow = cycleChoice(defaultReuseWindow(m.defaults), []string{config.ReuseNew, config.ReuseFocus, config.ReuseAsk}, delta)
+				if m.defaults.ReuseWindow == config.ReuseNew {
+					m.defaults.ReuseWindow = ""
+				}
+				return m, nil
 			case defaultsFieldConfirmQuit:
 				m.defaults.ConfirmQuit = !m.defaults.ConfirmQuit
 				return m, nil
@@ -307,16 +396,26 @@ func (m *defaultsFormModel) moveFocus(delta int) tea.Cmd {
 		defaultsFieldPort,
 		defaultsFieldIdentity,
 		defaultsFieldExtraArgs,
+		defaultsFieldJump,
+		defaultsFieldBackend,
 		defaultsFieldAccentColor,
 		defaultsFieldLoadKnownHosts,
+		defaultsFieldLoadSSHConfig,
+		defaultsFieldLoadDocker,
+		defaultsFieldLoadKubernetes,
+		defaultsFieldMultiplexer,
 		defaultsFieldTmux,
 		defaultsFieldOpenMode,
+		defaultsFieldReuseWindow,
 		defaultsFieldTmuxSession,
 		defaultsFieldConfirmQuit,
 		defaultsFieldConnectThreshold,
+		defaultsFieldExecParallel,
+		defaultsFieldExecTimeout,
 		defaultsFieldPaneSplit,
 		defaultsFieldPaneLayout,
 		defaultsFieldPaneSync,
+		defaultsFieldMaxPanes,
 		defaultsFieldPaneBorderStatus,
 		defaultsFieldPaneBorderFormat,
 	}
@@ -347,14 +446,22 @@ func (m *defaultsFormModel) setFocus(f defaultsField) {
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
 	m.inSession.Blur()
 	m.inThreshold.Blur()
+	m.inParallel.Blur()
+	m.inTimeout.Blur()
+	m.inMaxPanes.Blur()
 	setSearchFocused(&m.inUser, false)
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
 	setSearchFocused(&m.inSession, false)
 	setSearchFocused(&m.inThreshold, false)
+	setSearchFocused(&m.inParallel, false)
+	setSearchFocused(&m.inTimeout, false)
+	setSearchFocused(&m.inMaxPanes, false)
 
 	// Highlight the focused field label (but don't activate text cursor).
 	switch f {
@@ -366,16 +473,24 @@ func (m *defaultsFormModel) setFocus(f defaultsField) {
 		setSearchFocused(&m.inIdentity, true)
 	case defaultsFieldExtraArgs:
 		setSearchFocused(&m.inExtra, true)
+	case defaultsFieldJump:
+		setSearchFocused(&m.inJump, true)
 	case defaultsFieldTmuxSession:
 		setSearchFocused(&m.inSession, true)
 	case defaultsFieldConnectThreshold:
 		setSearchFocused(&m.inThreshold, true)
+	case defaultsFieldExecParallel:
+		setSearchFocused(&m.inParallel, true)
+	case defaultsFieldExecTimeout:
+		setSearchFocused(&m.inTimeout, true)
+	case defaultsFieldMaxPanes:
+		setSearchFocused(&m.inMaxPanes, true)
 	}
 }
 
 func (m *defaultsFormModel) isTextField() bool {
 	switch m.focus {
-	case defaultsFieldUser, defaultsFieldPort, defaultsFieldIdentity, defaultsFieldExtraArgs, defaultsFieldTmuxSession, defaultsFieldConnectThreshold:
+	case defaultsFieldUser, defaultsFieldPort, defaultsFieldIdentity, defaultsFieldExtraArgs, defaultsFieldJump, defaultsFieldTmuxSession, defaultsFieldConnectThreshold, defaultsFieldExecParallel, defaultsFieldExecTimeout, defaultsFieldMaxPanes:
 		return true
 	}
 	return false
@@ -392,10 +507,18 @@ func (m *defaultsFormModel) enterEdit() {
 		_ = m.inIdentity.Focus()
 	case defaultsFieldExtraArgs:
 		_ = m.inExtra.Focus()
+	case defaultsFieldJump:
+		_ = m.inJump.Focus()
 	case defaultsFieldTmuxSession:
 		_ = m.inSession.Focus()
 	case defaultsFieldConnectThreshold:
 		_ = m.inThreshold.Focus()
+	case defaultsFieldExecParallel:
+		_ = m.inParallel.Focus()
+	case defaultsFieldExecTimeout:
+		_ = m.inTimeout.Focus()
+	case defaultsFieldMaxPanes:
+		_ = m.inMaxPanes.Focus()
 	}
 }
 
@@ -405,8 +528,12 @@ func (m *defaultsFormModel) exitEdit() {
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
 	m.inSession.Blur()
 	m.inThreshold.Blur()
+	m.inParallel.Blur()
+	m.inTimeout.Blur()
+	m.inMaxPanes.Blur()
 }
 
 func (m *defaultsFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
@@ -420,10 +547,18 @@ func (m *defaultsFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
 		m.inIdentity, cmd = m.inIdentity.Update(msg)
 	case defaultsFieldExtraArgs:
 		m.inExtra, cmd = m.inExtra.Update(msg)
+	case defaultsFieldJump:
+		m.inJump, cmd = m.inJump.Update(msg)
 	case defaultsFieldTmuxSession:
 		m.inSession, cmd = m.inSession.Update(msg)
 	case defaultsFieldConnectThreshold:
 		m.inThreshold, cmd = m.inThreshold.Update(msg)
+	case defaultsFieldExecParallel:
+		m.inParallel, cmd = m.inParallel.Update(msg)
+	case defaultsFieldExecTimeout:
+		m.inTimeout, cmd = m.inTimeout.Update(msg)
+	case defaultsFieldMaxPanes:
+		m.inMaxPanes, cmd = m.inMaxPanes.Update(msg)
 	}
 	return cmd
 }
@@ -467,6 +602,12 @@ func (m *defaultsFormModel) apply() error {
 		m.defaults.Port = p
 	}
 
+	jump, err := config.ParseJump(m.inJump.Value())
+	if err != nil {
+		return err
+	}
+	m.defaults.Jump = jump
+
 	extra := strings.TrimSpace(m.inExtra.Value())
 	if extra == "" {
 		m.defaults.ExtraArgs = nil
@@ -489,6 +630,32 @@ func (m *defaultsFormModel) apply() error {
 		m.defaults.ConnectConfirmThreshold = t
 	}
 
+	parallelStr := strings.TrimSpace(m.inParallel.Value())
+	if parallelStr == "" {
+		m.defaults.ExecParallel = config.DefaultExecParallel
+	} else {
+		p, err := strconv.Atoi(parallelStr)
+		if err != nil || p < 1 {
+			return fmt.Errorf("exec parallel must be a number >= 1")
+		}
+		m.defaults.ExecParallel = p
+	}
+
+	timeoutStr := strings.TrimSpace(m.inTimeout.Value())
+	if _, err := config.ParseExecTimeout(timeoutStr); err != nil {
+		return err
+	}
+	m.defaults.ExecTimeout = timeoutStr
+
+	m.defaults.MaxPanesPerWindow = 0
+	if maxStr := strings.TrimSpace(m.inMaxPanes.Value()); maxStr != "" {
+		n, err := strconv.Atoi(maxStr)
+		if err != nil || n < 0 {
+			return fmt.Errorf("max panes must be a number >= 0")
+		}
+		m.defaults.MaxPanesPerWindow = n
+	}
+
 	return nil
 }
 
@@ -554,6 +721,14 @@ func (m *defaultsFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Extra args:", m.focus == defaultsFieldExtraArgs)+" "+inputLine(m.inExtra, m.focus == defaultsFieldExtraArgs, fieldW))
+	if m.focus == defaultsFieldJump {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Jump:", m.focus == defaultsFieldJump)+" "+inputLine(m.inJump, m.focus == defaultsFieldJump, fieldW))
+	if m.focus == defaultsFieldBackend {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Backend:", m.focus == defaultsFieldBackend)+" "+backendLine(defaultBackend(m.defaults), backendChoices(false), "", m.focus == defaultsFieldBackend))
 
 	lines = append(lines, formSection("UI", innerW))
 	if m.focus == defaultsFieldAccentColor {
@@ -578,8 +753,46 @@ func (m *defaultsFormModel) View() string {
 	}
 	lines = append(lines, label("Load known_hosts:", loadFocused)+" "+loadLine)
 
+	sshCfgCur := "no"
+	if m.defaults.LoadSSHConfig {
+		sshCfgCur = "yes"
+	}
+	sshCfgFocused := m.focus == defaultsFieldLoadSSHConfig
+	sshCfgLine := seg(sshCfgCur, "yes", "yes", sshCfgFocused) + "  " + seg(sshCfgCur, "no", "no", sshCfgFocused)
+	if sshCfgFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Load ssh_config:", sshCfgFocused)+" "+sshCfgLine)
+
+	for _, t := range []struct {
+		field defaultsField
+		name  string
+		on    bool
+	}{
+		{defaultsFieldLoadDocker, "Load docker:", m.defaults.LoadDocker},
+		{defaultsFieldLoadKubernetes, "Load k8s pods:", m.defaults.LoadKubernetes},
+	} {
+		cur := "no"
+		if t.on {
+			cur = "yes"
+		}
+		focused := m.focus == t.field
+		if focused {
+			focusLine = len(lines)
+		}
+		lines = append(lines, label(t.name, focused)+" "+seg(cur, "yes", "yes", focused)+"  "+seg(cur, "no", "no", focused))
+	}
+
 	lines = append(lines, formSection("Tmux", innerW))
 
+	muxCur := defaultMultiplexer(m.defaults)
+	muxFocused := m.focus == defaultsFieldMultiplexer
+	muxLine := seg(muxCur, config.MuxAuto, "auto", muxFocused) + "  " + seg(muxCur, config.MuxTmux, "tmux", muxFocused) + "  " + seg(muxCur, config.MuxZellij, "zellij", muxFocused) + "  " + seg(muxCur, config.MuxScreen, "screen", muxFocused)
+	if muxFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Multiplexer:", muxFocused)+" "+muxLine)
+
 	tmuxCur := strings.TrimSpace(m.defaults.Tmux)
 	tmuxFocused := m.focus == defaultsFieldTmux
 	tmuxLine := seg(tmuxCur, "auto", "auto", tmuxFocused) + "  " + seg(tmuxCur, "force", "force", tmuxFocused) + "  " + seg(tmuxCur, "never", "never", tmuxFocused)
@@ -598,6 +811,14 @@ func (m *defaultsFormModel) View() string {
 	lines = append(lines, label("Open mode:", openFocused)+" "+open1)
 	lines = append(lines, "  "+open2)
 
+	reuseCur := defaultReuseWindow(m.defaults)
+	reuseFocused := m.focus == defaultsFieldReuseWindow
+	reuseLine := seg(reuseCur, config.ReuseNew, "new", reuseFocused) + "  " + seg(reuseCur, config.ReuseFocus, "focus", reuseFocused) + "  " + seg(reuseCur, config.ReuseAsk, "ask", reuseFocused)
+	if reuseFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Reuse window:", reuseFocused)+" "+reuseLine)
+
 	if m.focus == defaultsFieldTmuxSession {
 		focusLine = len(lines)
 	}
@@ -619,6 +840,16 @@ func (m *defaultsFormModel) View() string {
 	}
 	lines = append(lines, label("Confirm connect at:", m.focus == defaultsFieldConnectThreshold)+" "+inputLine(m.inThreshold, m.focus == defaultsFieldConnectThreshold, min(12, fieldW)))
 
+	lines = append(lines, formSection("Exec", innerW))
+	if m.focus == defaultsFieldExecParallel {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Parallel:", m.focus == defaultsFieldExecParallel)+" "+inputLine(m.inParallel, m.focus == defaultsFieldExecParallel, min(12, fieldW)))
+	if m.focus == defaultsFieldExecTimeout {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Timeout:", m.focus == defaultsFieldExecTimeout)+" "+inputLine(m.inTimeout, m.focus == defaultsFieldExecTimeout, min(12, fieldW)))
+
 	lines = append(lines, formSection("Panes", innerW))
 
 	splitCur := strings.TrimSpace(m.defaults.PaneSplit)
@@ -646,6 +877,10 @@ func (m *defaultsFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Pane sync:", syncFocused)+" "+syncLine)
+	if m.focus == defaultsFieldMaxPanes {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Max panes:", m.focus == defaultsFieldMaxPanes)+" "+inputLine(m.inMaxPanes, m.focus == defaultsFieldMaxPanes, min(12, fieldW)))
 
 	borderCur := strings.TrimSpace(m.defaults.PaneBorderPos)
 	borderFocused := m.focus == defaultsFieldPaneBorderStatus
@@ -706,5 +941,5 @@ func (m *defaultsFormModel) View() string {
 
 	headLeft := headerStyle.Render("Settings")
 	headRight := statusDot(true, false)
-	return renderMainTabBox(m.width, m.height, 2, headLeft, headRight, strings.Join(contentLines, "\n"))
+	return renderMainTabBox(m.width, m.height, 4, headLeft, headRight, strings.Join(contentLines, "\n"))
 }
@@ -0,0 +1,792 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/runner"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openExecMsg starts a command or a copy on hosts of a group (all members
+// when hosts is empty) and shows the exec screen. groupIndex -1 runs on
+// hosts of the Hosts tab, outside any group.
+type openExecMsg struct {
+	groupIndex int
+	hosts      []string
+	spec       execSpec
+	returnTo   screen
+}
+
+// execUpdateMsg is one runner update of the run that owns ch; it is nil
+// when the run has finished.
+type execUpdateMsg struct {
+	ch     <-chan execUpdate
+	update *execUpdate
+}
+
+// execUpdate is either the new state of target index or, when batch is
+// set, a request to confirm the next batch.
+type execUpdate struct {
+	index  int
+	result runner.Result
+	batch  *execBatch
+}
+
+// execBatch asks whether to run the targets [start, end); the answer is
+// sent on reply.
+type execBatch struct {
+	start, end int
+	reply      chan<- bool
+}
+
+// execTargets builds the non-interactive commands of a run on hosts: ssh
+// running spec.command, or scp running spec.transfer. settings returns the
+// resolved ssh settings of a host. A pull from several hosts goes into one
+// subdirectory of the local directory per host, created here.
+func execTargets(hosts []string, settings func(host string) sshcmd.Settings, spec execSpec) ([]runner.Target, error) {
+	targets := make([]runner.Target, 0, len(hosts))
+	for _, h := range hosts {
+		var argv []string
+		var err error
+		if t := spec.transfer; t != nil {
+			if t.Pull && len(hosts) > 1 {
+				dir := filepath.Join(t.Local[0], strings.ReplaceAll(h, "/", "_"))
+				if err := os.MkdirAll(dir, 0o700); err != nil {
+					return nil, err
+				}
+				hostT := *t
+				hostT.Local = []string{dir}
+				t = &hostT
+			}
+			argv, err = runner.CopyCommand(h, settings(h), *t)
+		} else {
+			argv, err = runner.Command(h, settings(h), spec.command)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", h, err)
+		}
+		targets = append(targets, runner.Target{Host: h, Argv: argv})
+	}
+	return targets, nil
+}
+
+// describe is the header of a run: "$ command", or the copy as
+// "push a.conf → :/etc/" / "pull :/var/log/syslog → logs".
+func (s execSpec) describe() string {
+	t := s.transfer
+	switch {
+	case t == nil:
+		return "$ " + s.command
+	case t.Pull:
+		return "pull :" + t.Remote + " → " + t.Local[0]
+	default:
+		return "push " + strings.Join(t.Local, " ") + " → :" + t.Remote
+	}
+}
+
+type execRow struct {
+	result runner.Result
+}
+
+func (r execRow) Title() string       { return r.result.Host }
+func (r execRow) Description() string { return "" }
+func (r execRow) FilterValue() string { return r.result.Host }
+
+type execDelegate struct{}
+
+func (d execDelegate) Height() int                             { return 1 }
+func (d execDelegate) Spacing() int                            { return 0 }
+func (d execDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d execDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	switch row := item.(type) {
+	case execRow:
+		fmt.Fprint(w, renderExecRow(m.Width(), index == m.Index(), row.result))
+	case bucketRow:
+		fmt.Fprint(w, renderBucketRow(m.Width(), index == m.Index(), row))
+	default:
+		fmt.Fprint(w, item.FilterValue())
+	}
+}
+
+// renderExecRow renders "● host  first output line" with the status, exit
+// code and duration on the right.
+func renderExecRow(width int, active bool, r runner.Result) string {
+	cur := " "
+	if active {
+		cur = "▸"
+	}
+	dot := "○"
+	style := dim
+	switch r.Status {
+	case runner.Running:
+		dot = "◌"
+	case runner.OK:
+		dot, style = "●", statusOK
+	case runner.Failed, runner.Unreachable, runner.TimedOut:
+		dot, style = "●", statusErr
+	case runner.Canceled:
+		dot, style = "●", statusWarn
+	case runner.Skipped:
+		dot = "–"
+	}
+	status := execStatusText(r)
+	if !active {
+		dot = style.Render(dot)
+	}
+
+	left := cur + " " + dot + " " + r.Host
+	preview := firstLine(r.Stdout)
+	if preview == "" {
+		preview = firstLine(r.Stderr)
+	}
+	if width <= 0 {
+		line := left + "  " + preview + "  " + status
+		if active {
+			return rowActiveStyle.Render(line)
+		}
+		return line
+	}
+	statusW := min(lipgloss.Width(status), max(0, width/2))
+	status = truncateTail(status, statusW)
+	room := width - lipgloss.Width(left) - statusW - 2
+	if preview != "" && room >= 6 {
+		p := "  " + truncateTail(preview, room-2)
+		if !active {
+			p = dim.Render(p)
+		}
+		left += p
+	} else {
+		left = truncateTail(left, max(0, width-statusW-2))
+	}
+	if !active {
+		if r.Status.Failed() || r.Status == runner.Canceled {
+			status = style.Render(status)
+		} else {
+			status = dim.Render(status)
+		}
+	}
+	pad := max(2, width-lipgloss.Width(left)-lipgloss.Width(status))
+	line := left + strings.Repeat(" ", pad) + status
+	if active {
+		return rowActiveStyle.Render(line)
+	}
+	return line
+}
+
+// execStatusText is "failed  exit 2  1.3s".
+func execStatusText(r runner.Result) string {
+	s := exitStatusText(r.Status, r.ExitCode)
+	if r.Status.Done() && r.Status != runner.Skipped {
+		s += "  " + r.Duration.Round(100*time.Millisecond).String()
+	}
+	return s
+}
+
+// exitStatusText is "failed  exit 2", or just the status when it is ok or
+// has no exit code.
+func exitStatusText(st runner.Status, exitCode int) string {
+	if exitCode >= 0 && st != runner.OK {
+		return fmt.Sprintf("%s  exit %d", st, exitCode)
+	}
+	return st.String()
+}
+
+// bucketRow is a row of the compare mode: hosts with identical output.
+type bucketRow struct {
+	bucket   runner.Bucket
+	majority bool
+}
+
+func (r bucketRow) Title() string       { return strings.Join(r.bucket.Hosts, " ") }
+func (r bucketRow) Description() string { return "" }
+func (r bucketRow) FilterValue() string { return strings.Join(r.bucket.Hosts, " ") }
+
+// renderBucketRow renders "● 8 hosts  first output line" with the status
+// on the right; the largest bucket is marked as the majority.
+func renderBucketRow(width int, active bool, row bucketRow) string {
+	b := row.bucket
+	cur := " "
+	if active {
+		cur = "▸"
+	}
+	dot := "●"
+	if !active {
+		switch {
+		case row.majority:
+			dot = statusOK.Render(dot)
+		case b.Status != runner.OK:
+			dot = statusErr.Render(dot)
+		default:
+			dot = statusWarn.Render(dot)
+		}
+	}
+	noun := "hosts"
+	if len(b.Hosts) == 1 {
+		noun = "host"
+	}
+	left := fmt.Sprintf("%s %s %d %s", cur, dot, len(b.Hosts), noun)
+	preview := firstLine(b.Output())
+	if preview == "" {
+		preview = "(no output)"
+	}
+	status := exitStatusText(b.Status, b.ExitCode)
+	if row.majority {
+		status += "  majority"
+	}
+	if width <= 0 {
+		line := left + "  " + preview + "  " + status
+		if active {
+			return rowActiveStyle.Render(line)
+		}
+		return line
+	}
+	statusW := min(lipgloss.Width(status), max(0, width/2))
+	status = truncateTail(status, statusW)
+	room := width - lipgloss.Width(left) - statusW - 2
+	if room >= 6 {
+		p := "  " + truncateTail(preview, room-2)
+		if !active {
+			p = dim.Render(p)
+		}
+		left += p
+	} else {
+		left = truncateTail(left, max(0, width-statusW-2))
+	}
+	if !active {
+		status = dim.Render(status)
+	}
+	pad := max(2, width-lipgloss.Width(left)-lipgloss.Width(status))
+	line := left + strings.Repeat(" ", pad) + status
+	if active {
+		return rowActiveStyle.Render(line)
+	}
+	return line
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// execModel runs a command or a copy on several hosts and lists their
+// results, with the full output of one host in a detail view.
+type execModel struct {
+	opts Options
+
+	width  int
+	height int
+
+	group    string // empty for hosts of the Hosts tab
+	spec     execSpec
+	returnTo screen
+
+	results []runner.Result
+	ch      <-chan execUpdate
+	cancel  context.CancelFunc
+	running bool
+	batch   *execBatch // waiting for the next batch to be confirmed
+
+	list        list.Model
+	compare     bool // rows are output buckets instead of hosts
+	detail      bool
+	detailTitle string
+	vp          viewport.Model
+
+	keymap   keyMap
+	help     help.Model
+	showHelp bool
+	helpVP   viewport.Model
+	toast    toast
+
+	confirmQuit bool
+	quitting    bool
+	closed      bool // esc: return to returnTo
+}
+
+func newExecModel(opts Options, group string, spec execSpec, returnTo screen, targets []runner.Target) *execModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.SetDelegate(execDelegate{})
+	l.Title = "Exec"
+	configureList(&l)
+
+	results := make([]runner.Result, len(targets))
+	for i, t := range targets {
+		results[i] = runner.Result{Host: t.Host, ExitCode: -1}
+	}
+	m := &execModel{
+		opts:     opts,
+		group:    group,
+		spec:     spec,
+		returnTo: returnTo,
+		results:  results,
+		list:     l,
+		keymap:   defaultKeyMap(),
+		help:     help.New(),
+	}
+	m.refresh()
+	return m
+}
+
+// start runs the targets in the background and returns the command that
+// delivers the first update.
+func (m *execModel) start(targets []runner.Target) tea.Cmd {
+	d := m.opts.Config.Defaults
+	timeout, err := config.ParseExecTimeout(d.ExecTimeout)
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return nil
+	}
+	if m.spec.transfer != nil {
+		// A copy takes as long as the files need; x cancels it.
+		timeout = 0
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan execUpdate, len(targets)*2)
+	m.ch = ch
+	m.cancel = cancel
+	m.running = true
+	ropts := runner.Options{Parallel: d.ExecParallel, Timeout: timeout, Batch: m.spec.batch, HaltOnError: m.spec.halt}
+	if m.spec.pause {
+		ropts.BeforeBatch = func(start, end int) bool {
+			reply := make(chan bool, 1)
+			select {
+			case ch <- execUpdate{batch: &execBatch{start: start, end: end, reply: reply}}:
+			case <-ctx.Done():
+				return false
+			}
+			select {
+			case ok := <-reply:
+				return ok
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+	go func() {
+		defer close(ch)
+		runner.Run(ctx, targets, ropts, func(i int, r runner.Result) {
+			ch <- execUpdate{index: i, result: r}
+		})
+	}()
+	return waitExec(ch)
+}
+
+func waitExec(ch <-chan execUpdate) tea.Cmd {
+	return func() tea.Msg {
+		u, ok := <-ch
+		if !ok {
+			return execUpdateMsg{ch: ch}
+		}
+		return execUpdateMsg{ch: ch, update: &u}
+	}
+}
+
+// stop cancels a run in progress.
+func (m *execModel) stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.batch = nil
+}
+
+// answerBatch runs (or skips, when ok is false) the batch waiting for
+// confirmation.
+func (m *execModel) answerBatch(ok bool) {
+	if m.batch == nil {
+		return
+	}
+	m.batch.reply <- ok
+	m.batch = nil
+	m.toast = toast{}
+}
+
+// batchProgress returns the batch the run is at (1-based) and the number
+// of batches, or zeros when the hosts do not run in batches.
+func (m *execModel) batchProgress() (cur, total int) {
+	n := m.spec.batch
+	if n <= 0 || len(m.results) == 0 {
+		return 0, 0
+	}
+	total = (len(m.results) + n - 1) / n
+	last := 0
+	for i, r := range m.results {
+		if r.Status != runner.Pending && r.Status != runner.Skipped {
+			last = i
+		}
+	}
+	if m.batch != nil {
+		last = m.batch.start
+	}
+	return last/n + 1, total
+}
+
+func (m *execModel) refresh() {
+	var items []list.Item
+	if m.compare {
+		for i, b := range runner.Buckets(m.results) {
+			items = append(items, bucketRow{bucket: b, majority: i == 0})
+		}
+	} else {
+		for _, r := range m.results {
+			items = append(items, execRow{result: r})
+		}
+	}
+	idx := m.list.Index()
+	m.list.SetItems(items)
+	if idx >= 0 && idx < len(items) {
+		m.list.Select(idx)
+	}
+}
+
+func (m *execModel) counts() (done, failed, skipped int) {
+	for _, r := range m.results {
+		switch {
+		case r.Status == runner.Skipped:
+			skipped++
+		case r.Status.Done():
+			done++
+			if r.Status != runner.OK {
+				failed++
+			}
+		}
+	}
+	return done, failed, skipped
+}
+
+func (m *execModel) Init() tea.Cmd { return nil }
+
+func (m *execModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		innerW := max(0, msg.Width-2)
+		innerH := max(0, msg.Height-2)
+		// breadcrumb + sep + header + sep + footer sep + footer
+		m.list.SetSize(innerW, max(1, innerH-6))
+		m.vp.Width = innerW
+		m.vp.Height = max(1, innerH-6)
+		return m, nil
+	case execUpdateMsg:
+		if msg.ch != m.ch {
+			return m, nil
+		}
+		if msg.update == nil {
+			m.running = false
+			m.batch = nil
+			_, failed, skipped := m.counts()
+			switch {
+			case skipped > 0 && failed > 0:
+				m.toast = toast{text: fmt.Sprintf("%d hosts failed, %d skipped", failed, skipped), level: toastWarn}
+			case skipped > 0:
+				m.toast = toast{text: fmt.Sprintf("stopped, %d hosts skipped", skipped), level: toastWarn}
+			case failed > 0:
+				m.toast = toast{text: fmt.Sprintf("%d of %d hosts failed", failed, len(m.results)), level: toastWarn}
+			default:
+				m.toast = toast{text: "done", level: toastOK}
+			}
+			return m, nil
+		}
+		if b := msg.update.batch; b != nil {
+			m.batch = b
+			m.toast = toast{text: fmt.Sprintf("next batch: hosts %d-%d of %d, continue? (y/n)", b.start+1, b.end, len(m.results)), level: toastWarn}
+			return m, waitExec(m.ch)
+		}
+		if i := msg.update.index; i >= 0 && i < len(m.results) {
+			m.results[i] = msg.update.result
+		}
+		m.refresh()
+		return m, waitExec(m.ch)
+	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, m.keymap.Help) || msg.String() == "esc" {
+				m.showHelp = false
+				return m, nil
+			}
+			updateHelpViewport(&m.helpVP, msg)
+			return m, nil
+		}
+		if m.confirmQuit {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.stop()
+				m.quitting = true
+				return m, tea.Quit
+			case "n", "N", "esc":
+				m.confirmQuit = false
+				m.toast = toast{}
+			}
+			return m, nil
+		}
+		if m.batch != nil && !m.detail {
+			switch msg.String() {
+			case "y", "Y":
+				m.answerBatch(true)
+				return m, nil
+			case "n", "N":
+				m.answerBatch(false)
+				return m, nil
+			}
+		}
+		if m.detail {
+			switch msg.String() {
+			case "esc", "backspace", "enter", "q":
+				m.detail = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.vp, cmd = m.vp.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.Quit):
+			if !m.opts.Config.Defaults.ConfirmQuit {
+				m.stop()
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.confirmQuit = true
+			m.toast = toast{text: "quit? (y/n)", level: toastWarn}
+			return m, nil
+		case key.Matches(msg, m.keymap.Help):
+			m.showHelp = true
+			if m.width > 0 && m.height > 0 {
+				m.helpVP = initHelpViewport(m.width, m.height, m.title(), m.help, m.helpKeys())
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.ExecCancel):
+			if !m.running {
+				return m, nil
+			}
+			m.stop()
+			m.toast = toast{text: "canceling…", level: toastWarn}
+			return m, nil
+		case key.Matches(msg, m.keymap.ExecCompare):
+			m.compare = !m.compare
+			m.list.Select(0)
+			m.refresh()
+			return m, nil
+		case key.Matches(msg, m.keymap.ExecSelect):
+			row, ok := m.list.SelectedItem().(bucketRow)
+			if !ok {
+				m.toast = toast{text: "press b to compare outputs first", level: toastWarn}
+				return m, nil
+			}
+			hosts := append([]string(nil), row.bucket.Hosts...)
+			return m, func() tea.Msg { return selectHostsMsg{hosts: hosts} }
+		case key.Matches(msg, m.keymap.Esc), key.Matches(msg, m.keymap.Back):
+			if m.compare {
+				m.compare = false
+				m.list.Select(0)
+				m.refresh()
+				return m, nil
+			}
+			m.stop()
+			m.closed = true
+			return m, nil
+		case key.Matches(msg, m.keymap.Connect):
+			switch row := m.list.SelectedItem().(type) {
+			case execRow:
+				m.vp.SetContent(m.detailContent(row.result))
+				m.detailTitle = row.result.Host
+			case bucketRow:
+				m.vp.SetContent(m.bucketContent(row))
+				m.detailTitle = fmt.Sprintf("%d hosts", len(row.bucket.Hosts))
+			default:
+				return m, nil
+			}
+			m.vp.GotoTop()
+			m.detail = true
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// detailContent is the full output of one host.
+func (m *execModel) detailContent(r runner.Result) string {
+	var b strings.Builder
+	b.WriteString(execStatusText(r))
+	if r.Err != "" {
+		b.WriteString(": " + r.Err)
+	}
+	b.WriteString("\n")
+	if r.Stdout != "" {
+		b.WriteString("\n" + dim.Render("stdout") + "\n")
+		b.WriteString(strings.TrimRight(r.Stdout, "\n") + "\n")
+	}
+	if r.Stderr != "" {
+		b.WriteString("\n" + dim.Render("stderr") + "\n")
+		b.WriteString(statusErr.Render(strings.TrimRight(r.Stderr, "\n")) + "\n")
+	}
+	if r.Stdout == "" && r.Stderr == "" && r.Status.Done() {
+		b.WriteString("\n" + dim.Render("(no output)") + "\n")
+	}
+	return b.String()
+}
+
+// bucketContent lists the hosts of a bucket and its output, as a diff
+// against the majority bucket unless it is the majority.
+func (m *execModel) bucketContent(row bucketRow) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(row.bucket.Hosts, ", ") + "\n")
+	if row.majority {
+		b.WriteString(dim.Render("majority output") + "\n\n")
+		out := strings.TrimRight(row.bucket.Output(), "\n")
+		if out == "" {
+			out = dim.Render("(no output)")
+		}
+		b.WriteString(out + "\n")
+		return b.String()
+	}
+
+	buckets := runner.Buckets(m.results)
+	if len(buckets) == 0 {
+		return b.String()
+	}
+	major := buckets[0]
+	fmt.Fprintf(&b, "%s %s\n", dim.Render("this bucket:"), exitStatusText(row.bucket.Status, row.bucket.ExitCode))
+	fmt.Fprintf(&b, "%s %s (%d hosts)\n\n", dim.Render("majority:   "), exitStatusText(major.Status, major.ExitCode), len(major.Hosts))
+	b.WriteString(statusErr.Render("- majority") + "  " + statusOK.Render("+ this bucket") + "\n")
+	for _, l := range runner.DiffLines(major.Output(), row.bucket.Output()) {
+		line := string(l.Op) + " " + l.Text
+		switch l.Op {
+		case runner.DiffRemove:
+			line = statusErr.Render(line)
+		case runner.DiffAdd:
+			line = statusOK.Render(line)
+		default:
+			line = dim.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// title is "Copy" for a transfer and "Exec" for a command.
+func (m *execModel) title() string {
+	if m.spec.transfer != nil {
+		return "Copy"
+	}
+	return "Exec"
+}
+
+func (m *execModel) View() string {
+	if m.showHelp {
+		return renderHelpModalWithVP(m.width, m.height, m.title(), m.help, m.helpKeys(), &m.helpVP)
+	}
+	if m.confirmQuit {
+		return renderQuitConfirm(m.width, m.height)
+	}
+
+	path := "Hosts"
+	if m.group != "" {
+		path = "Groups > " + m.group
+	}
+	crumb := dim.Render(path+" >") + " " + headerStyle.Render(m.title())
+	left := dim.Render(truncateTail(m.spec.describe(), max(10, m.width/2)))
+
+	if m.detail {
+		crumb = dim.Render(path+" > "+m.title()+" >") + " " + headerStyle.Render(m.detailTitle)
+		right := dim.Render(fmt.Sprintf("%3.f%%", m.vp.ScrollPercent()*100))
+		footer := styledFooter("↑/↓ scroll  ·  esc back")
+		return renderBreadcrumbTabBox(m.width, m.height, crumb, left, right, m.vp.View(), footer)
+	}
+
+	done, failed, skipped := m.counts()
+	right := ""
+	switch {
+	case !m.toast.empty():
+		right = renderToast(m.toast)
+	case m.compare:
+		right = statusDot(len(m.list.Items()) <= 1, false) + dim.Render(fmt.Sprintf(" %d distinct outputs  %d / %d done", len(m.list.Items()), done, len(m.results)))
+	default:
+		right = statusDot(failed == 0, false) + dim.Render(fmt.Sprintf(" %d / %d done", done, len(m.results)))
+		if cur, total := m.batchProgress(); total > 1 {
+			right += dim.Render(fmt.Sprintf("  batch %d / %d", cur, total))
+		}
+		if failed > 0 {
+			right += "   " + statusErr.Render(fmt.Sprintf("%d failed", failed))
+		}
+		if skipped > 0 {
+			right += "   " + statusWarn.Render(fmt.Sprintf("%d skipped", skipped))
+		}
+	}
+	var footer string
+	switch {
+	case m.batch != nil:
+		footer = styledFooter("y next batch  n stop  ·  ↵ output  x cancel  ? help")
+	case m.width < 60 && m.compare:
+		footer = styledFooter("↵ diff  s select  esc hosts  ? help")
+	case m.width < 60:
+		footer = styledFooter("↵ output  b compare  esc back  ? help")
+	case m.compare:
+		footer = styledFooter("↵ diff vs majority  s select hosts  ·  esc host list  ? help")
+	case m.running:
+		footer = styledFooter("↵ output  b compare  ·  x cancel  esc back  ? help")
+	default:
+		footer = styledFooter("↵ output  b compare  ·  esc back  ? help")
+	}
+	return renderBreadcrumbTabBox(m.width, m.height, crumb, left, right, m.list.View(), footer)
+}
+
+func (m *execModel) helpKeys() helpMap {
+	output := key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "show output"),
+	)
+	back := key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	)
+	return helpMap{
+		short: []key.Binding{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			output,
+			m.keymap.ExecCompare,
+			m.keymap.ExecSelect,
+			m.keymap.ExecCancel,
+			back,
+			m.keymap.Help,
+			m.keymap.Quit,
+		},
+		full: [][]key.Binding{{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			m.list.KeyMap.PrevPage,
+			m.list.KeyMap.NextPage,
+		}, {
+			output,
+			m.keymap.ExecCompare,
+			m.keymap.ExecSelect,
+			m.keymap.ExecCancel,
+			back,
+		}, {
+			m.keymap.Help,
+			m.keymap.Quit,
+		}},
+	}
+}
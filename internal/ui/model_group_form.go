@@ -23,16 +23,24 @@ type groupField int
 
 const (
 	groupFieldName groupField = iota
+	groupFieldDescription
+	groupFieldTags
+	groupFieldIncludes
 	groupFieldUser
 	groupFieldPort
 	groupFieldIdentity
 	groupFieldExtraArgs
+	groupFieldJump
+	groupFieldBackend
 	groupFieldRemoteCommand
+	groupFieldPreflight
 	groupFieldOpenMode
 	groupFieldTmux
+	groupFieldReuseWindow
 	groupFieldPaneSplit
 	groupFieldPaneLayout
 	groupFieldPaneSync
+	groupFieldMaxPanes
 	groupFieldPaneBorderStatus
 	groupFieldPaneBorderFormat
 )
@@ -50,11 +58,16 @@ type groupFormModel struct {
 	editing bool // true when editing a text field (insert mode)
 
 	inName     textinput.Model
+	inDesc     textinput.Model
+	inTags     textinput.Model
+	inIncludes textinput.Model
 	inUser     textinput.Model
 	inPort     textinput.Model
 	inIdentity textinput.Model
 	inExtra    textinput.Model
+	inJump     textinput.Model
 	inRemote   textinput.Model
+	inMaxPanes textinput.Model
 
 	borderPicker *paneBorderFormatsModel
 
@@ -68,11 +81,16 @@ type groupFormModel struct {
 
 func (m *groupFormModel) refreshAccentStyles() {
 	setSearchFocused(&m.inName, m.focus == groupFieldName)
+	setSearchFocused(&m.inDesc, m.focus == groupFieldDescription)
+	setSearchFocused(&m.inTags, m.focus == groupFieldTags)
+	setSearchFocused(&m.inIncludes, m.focus == groupFieldIncludes)
 	setSearchFocused(&m.inUser, m.focus == groupFieldUser)
 	setSearchFocused(&m.inPort, m.focus == groupFieldPort)
 	setSearchFocused(&m.inIdentity, m.focus == groupFieldIdentity)
 	setSearchFocused(&m.inExtra, m.focus == groupFieldExtraArgs)
+	setSearchFocused(&m.inJump, m.focus == groupFieldJump)
 	setSearchFocused(&m.inRemote, m.focus == groupFieldRemoteCommand)
+	setSearchFocused(&m.inMaxPanes, m.focus == groupFieldMaxPanes)
 	if m.borderPicker != nil {
 		m.borderPicker.refreshAccentStyles()
 	}
@@ -86,6 +104,27 @@ func newGroupFormModel(index int, g config.Group, defs config.Defaults, confirmQ
 	name.Placeholder = "prod"
 	configureSearch(&name)
 
+	desc := textinput.New()
+	desc.CharLimit = 256
+	desc.Prompt = ""
+	desc.SetValue(strings.TrimSpace(g.Description))
+	desc.Placeholder = "what this group is for"
+	configureSearch(&desc)
+
+	tags := textinput.New()
+	tags.CharLimit = 512
+	tags.Prompt = ""
+	tags.SetValue(strings.Join(g.Tags, ", "))
+	tags.Placeholder = "prod, web"
+	configureSearch(&tags)
+
+	includes := textinput.New()
+	includes.CharLimit = 512
+	includes.Prompt = ""
+	includes.SetValue(strings.Join(g.IncludeGroups, ", "))
+	includes.Placeholder = "prod-web, prod-db"
+	configureSearch(&includes)
+
 	user := textinput.New()
 	user.CharLimit = 128
 	user.Prompt = ""
@@ -126,6 +165,13 @@ func newGroupFormModel(index int, g config.Group, defs config.Defaults, confirmQ
 	}
 	configureSearch(&extra)
 
+	jump := textinput.New()
+	jump.CharLimit = 512
+	jump.Prompt = ""
+	jump.SetValue(strings.Join(g.Jump, ", "))
+	jump.Placeholder = "bastion, admin@gw:2222"
+	configureSearch(&jump)
+
 	remote := textinput.New()
 	remote.CharLimit = 1024
 	remote.Prompt = ""
@@ -133,17 +179,35 @@ func newGroupFormModel(index int, g config.Group, defs config.Defaults, confirmQ
 	remote.Placeholder = "command to run on connect"
 	configureSearch(&remote)
 
+	maxPanes := textinput.New()
+	maxPanes.CharLimit = 4
+	maxPanes.Prompt = ""
+	if g.MaxPanesPerWindow != 0 {
+		maxPanes.SetValue(strconv.Itoa(g.MaxPanesPerWindow))
+	}
+	if defs.MaxPanesPerWindow > 0 {
+		maxPanes.Placeholder = strconv.Itoa(defs.MaxPanesPerWindow)
+	} else {
+		maxPanes.Placeholder = "no limit"
+	}
+	configureSearch(&maxPanes)
+
 	m := &groupFormModel{
 		index:              index,
 		group:              g,
 		defs:               defs,
 		focus:              groupFieldName,
 		inName:             name,
+		inDesc:             desc,
+		inTags:             tags,
+		inIncludes:         includes,
 		inUser:             user,
 		inPort:             port,
 		inIdentity:         identity,
 		inExtra:            extra,
+		inJump:             jump,
 		inRemote:           remote,
+		inMaxPanes:         maxPanes,
 		keymap:             defaultKeyMap(),
 		confirmQuitEnabled: confirmQuitEnabled,
 	}
@@ -155,11 +219,16 @@ func newGroupFormModel(index int, g config.Group, defs config.Defaults, confirmQ
 
 	// Start in normal mode (no text input focused).
 	setSearchFocused(&m.inName, true)
+	setSearchFocused(&m.inDesc, false)
+	setSearchFocused(&m.inTags, false)
+	setSearchFocused(&m.inIncludes, false)
 	setSearchFocused(&m.inUser, false)
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
 	setSearchFocused(&m.inRemote, false)
+	setSearchFocused(&m.inMaxPanes, false)
 	return m
 }
 
@@ -181,11 +250,15 @@ func (m *groupFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		labelW := 14
 		fieldW := max(10, innerW-labelW-1)
 		m.inName.Width = fieldW
+		m.inDesc.Width = fieldW
+		m.inTags.Width = fieldW
 		m.inUser.Width = fieldW
 		m.inPort.Width = min(12, fieldW)
 		m.inIdentity.Width = fieldW
 		m.inExtra.Width = fieldW
+		m.inJump.Width = fieldW
 		m.inRemote.Width = fieldW
+		m.inMaxPanes.Width = min(12, fieldW)
 		if m.borderPicker != nil {
 			mw, mh := pickerModalSize(msg.Width, msg.Height)
 			_, _ = m.borderPicker.Update(tea.WindowSizeMsg{Width: mw, Height: mh})
@@ -283,12 +356,21 @@ func (m *groupFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				delta = -1
 			}
 			switch m.focus {
+			case groupFieldBackend:
+				m.group.Backend = cycleChoice(m.group.Backend, backendChoices(true), delta)
+				return m, nil
+			case groupFieldPreflight:
+				m.cyclePreflight(delta)
+				return m, nil
 			case groupFieldOpenMode:
 				m.cycleOpenMode(delta)
 				return m, nil
 			case groupFieldTmux:
 				m.cycleTmux(delta)
 				return m, nil
+			case groupFieldReuseWindow:
+				m.cycleReuseWindow(delta)
+				return m, nil
 			case groupFieldPaneSplit:
 				m.cyclePaneSplit(delta)
 				return m, nil
@@ -313,6 +395,12 @@ func (m *groupFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
 	switch m.focus {
 	case groupFieldName:
 		m.inName, cmd = m.inName.Update(msg)
+	case groupFieldDescription:
+		m.inDesc, cmd = m.inDesc.Update(msg)
+	case groupFieldTags:
+		m.inTags, cmd = m.inTags.Update(msg)
+	case groupFieldIncludes:
+		m.inIncludes, cmd = m.inIncludes.Update(msg)
 	case groupFieldUser:
 		m.inUser, cmd = m.inUser.Update(msg)
 	case groupFieldPort:
@@ -321,8 +409,12 @@ func (m *groupFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
 		m.inIdentity, cmd = m.inIdentity.Update(msg)
 	case groupFieldExtraArgs:
 		m.inExtra, cmd = m.inExtra.Update(msg)
+	case groupFieldJump:
+		m.inJump, cmd = m.inJump.Update(msg)
 	case groupFieldRemoteCommand:
 		m.inRemote, cmd = m.inRemote.Update(msg)
+	case groupFieldMaxPanes:
+		m.inMaxPanes, cmd = m.inMaxPanes.Update(msg)
 	default:
 		// no-op
 	}
@@ -332,16 +424,24 @@ func (m *groupFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
 func (m *groupFormModel) moveFocus(delta int) tea.Cmd {
 	order := []groupField{
 		groupFieldName,
+		groupFieldDescription,
+		groupFieldTags,
+		groupFieldIncludes,
 		groupFieldUser,
 		groupFieldPort,
 		groupFieldIdentity,
 		groupFieldExtraArgs,
+		groupFieldJump,
+		groupFieldBackend,
 		groupFieldRemoteCommand,
+		groupFieldPreflight,
 		groupFieldOpenMode,
 		groupFieldTmux,
+		groupFieldReuseWindow,
 		groupFieldPaneSplit,
 		groupFieldPaneLayout,
 		groupFieldPaneSync,
+		groupFieldMaxPanes,
 		groupFieldPaneBorderStatus,
 		groupFieldPaneBorderFormat,
 	}
@@ -369,22 +469,38 @@ func (m *groupFormModel) setFocus(f groupField) {
 
 	// Blur all text inputs.
 	m.inName.Blur()
+	m.inDesc.Blur()
+	m.inTags.Blur()
+	m.inIncludes.Blur()
 	m.inUser.Blur()
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
 	m.inRemote.Blur()
+	m.inMaxPanes.Blur()
 	setSearchFocused(&m.inName, false)
+	setSearchFocused(&m.inDesc, false)
+	setSearchFocused(&m.inTags, false)
+	setSearchFocused(&m.inIncludes, false)
 	setSearchFocused(&m.inUser, false)
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
 	setSearchFocused(&m.inRemote, false)
+	setSearchFocused(&m.inMaxPanes, false)
 
 	// Highlight the focused field label (but don't activate text cursor).
 	switch f {
 	case groupFieldName:
 		setSearchFocused(&m.inName, true)
+	case groupFieldDescription:
+		setSearchFocused(&m.inDesc, true)
+	case groupFieldTags:
+		setSearchFocused(&m.inTags, true)
+	case groupFieldIncludes:
+		setSearchFocused(&m.inIncludes, true)
 	case groupFieldUser:
 		setSearchFocused(&m.inUser, true)
 	case groupFieldPort:
@@ -393,14 +509,18 @@ func (m *groupFormModel) setFocus(f groupField) {
 		setSearchFocused(&m.inIdentity, true)
 	case groupFieldExtraArgs:
 		setSearchFocused(&m.inExtra, true)
+	case groupFieldJump:
+		setSearchFocused(&m.inJump, true)
 	case groupFieldRemoteCommand:
 		setSearchFocused(&m.inRemote, true)
+	case groupFieldMaxPanes:
+		setSearchFocused(&m.inMaxPanes, true)
 	}
 }
 
 func (m *groupFormModel) isTextField() bool {
 	switch m.focus {
-	case groupFieldName, groupFieldUser, groupFieldPort, groupFieldIdentity, groupFieldExtraArgs, groupFieldRemoteCommand:
+	case groupFieldName, groupFieldDescription, groupFieldTags, groupFieldIncludes, groupFieldUser, groupFieldPort, groupFieldIdentity, groupFieldExtraArgs, groupFieldJump, groupFieldRemoteCommand, groupFieldMaxPanes:
 		return true
 	}
 	return false
@@ -411,6 +531,12 @@ func (m *groupFormModel) enterEdit() {
 	switch m.focus {
 	case groupFieldName:
 		_ = m.inName.Focus()
+	case groupFieldDescription:
+		_ = m.inDesc.Focus()
+	case groupFieldTags:
+		_ = m.inTags.Focus()
+	case groupFieldIncludes:
+		_ = m.inIncludes.Focus()
 	case groupFieldUser:
 		_ = m.inUser.Focus()
 	case groupFieldPort:
@@ -419,19 +545,33 @@ func (m *groupFormModel) enterEdit() {
 		_ = m.inIdentity.Focus()
 	case groupFieldExtraArgs:
 		_ = m.inExtra.Focus()
+	case groupFieldJump:
+		_ = m.inJump.Focus()
 	case groupFieldRemoteCommand:
 		_ = m.inRemote.Focus()
+	case groupFieldMaxPanes:
+		_ = m.inMaxPanes.Focus()
 	}
 }
 
 func (m *groupFormModel) exitEdit() {
 	m.editing = false
 	m.inName.Blur()
+	m.inDesc.Blur()
+	m.inTags.Blur()
+	m.inIncludes.Blur()
 	m.inUser.Blur()
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
 	m.inRemote.Blur()
+	m.inMaxPanes.Blur()
+}
+
+func (m *groupFormModel) cyclePreflight(delta int) {
+	vals := []string{"", config.PreflightTCP}
+	m.group.Preflight = cycleChoice(m.group.Preflight, vals, delta)
 }
 
 func (m *groupFormModel) cycleOpenMode(delta int) {
@@ -444,6 +584,11 @@ func (m *groupFormModel) cycleTmux(delta int) {
 	m.group.Tmux = cycleChoice(m.group.Tmux, vals, delta)
 }
 
+func (m *groupFormModel) cycleReuseWindow(delta int) {
+	vals := []string{"", config.ReuseNew, config.ReuseFocus, config.ReuseAsk}
+	m.group.ReuseWindow = cycleChoice(m.group.ReuseWindow, vals, delta)
+}
+
 func (m *groupFormModel) cyclePaneSplit(delta int) {
 	vals := []string{"", "horizontal", "vertical"}
 	m.group.PaneSplit = cycleChoice(m.group.PaneSplit, vals, delta)
@@ -485,6 +630,9 @@ func cycleChoice(cur string, vals []string, delta int) string {
 
 func (m *groupFormModel) apply() error {
 	m.group.Name = strings.TrimSpace(m.inName.Value())
+	m.group.Description = strings.TrimSpace(m.inDesc.Value())
+	m.group.Tags = config.ParseTags(m.inTags.Value())
+	m.group.IncludeGroups = parseGroupList(m.inIncludes.Value())
 	m.group.User = strings.TrimSpace(m.inUser.Value())
 	m.group.IdentityFile = strings.TrimSpace(m.inIdentity.Value())
 	m.group.RemoteCommand = strings.TrimSpace(m.inRemote.Value())
@@ -501,6 +649,21 @@ func (m *groupFormModel) apply() error {
 		m.group.Port = p
 	}
 
+	m.group.MaxPanesPerWindow = 0
+	if maxStr := strings.TrimSpace(m.inMaxPanes.Value()); maxStr != "" {
+		n, err := strconv.Atoi(maxStr)
+		if err != nil || n < 0 {
+			return fmt.Errorf("max panes must be a number >= 0")
+		}
+		m.group.MaxPanesPerWindow = n
+	}
+
+	jump, err := config.ParseJump(m.inJump.Value())
+	if err != nil {
+		return err
+	}
+	m.group.Jump = jump
+
 	extra := strings.TrimSpace(m.inExtra.Value())
 	if extra == "" {
 		m.group.ExtraArgs = nil
@@ -555,6 +718,13 @@ func (m *groupFormModel) View() string {
 		return tabInactiveStyle.Render(label)
 	}
 
+	backendFocused := m.focus == groupFieldBackend
+	backendSegs := backendLine(m.group.Backend, backendChoices(true), "inherit", backendFocused)
+
+	preflightCur := strings.TrimSpace(m.group.Preflight)
+	preflightFocused := m.focus == groupFieldPreflight
+	preflightLine := seg(preflightCur, "", "off", preflightFocused) + "  " + seg(preflightCur, config.PreflightTCP, "tcp", preflightFocused)
+
 	openCur := strings.TrimSpace(m.group.OpenMode)
 	openFocused := m.focus == groupFieldOpenMode
 	open1 := seg(openCur, "", "inherit", openFocused) + "  " + seg(openCur, "auto", "auto", openFocused) + "  " + seg(openCur, "current", "current", openFocused)
@@ -564,6 +734,10 @@ func (m *groupFormModel) View() string {
 	tmuxFocused := m.focus == groupFieldTmux
 	tmuxLine := seg(tmuxCur, "", "inherit", tmuxFocused) + "  " + seg(tmuxCur, "auto", "auto", tmuxFocused) + "  " + seg(tmuxCur, "force", "force", tmuxFocused) + "  " + seg(tmuxCur, "never", "never", tmuxFocused)
 
+	reuseCur := strings.TrimSpace(m.group.ReuseWindow)
+	reuseFocused := m.focus == groupFieldReuseWindow
+	reuseLine := seg(reuseCur, "", "inherit", reuseFocused) + "  " + seg(reuseCur, config.ReuseNew, "new", reuseFocused) + "  " + seg(reuseCur, config.ReuseFocus, "focus", reuseFocused) + "  " + seg(reuseCur, config.ReuseAsk, "ask", reuseFocused)
+
 	splitCur := strings.TrimSpace(m.group.PaneSplit)
 	splitFocused := m.focus == groupFieldPaneSplit
 	splitLine := seg(splitCur, "", "inherit", splitFocused) + "  " + seg(splitCur, "horizontal", "horizontal", splitFocused) + "  " + seg(splitCur, "vertical", "vertical", splitFocused)
@@ -588,6 +762,18 @@ func (m *groupFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Name:", m.focus == groupFieldName)+" "+inputLine(m.inName, m.focus == groupFieldName, fieldW))
+	if m.focus == groupFieldDescription {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Description:", m.focus == groupFieldDescription)+" "+inputLine(m.inDesc, m.focus == groupFieldDescription, fieldW))
+	if m.focus == groupFieldTags {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Tags:", m.focus == groupFieldTags)+" "+inputLine(m.inTags, m.focus == groupFieldTags, fieldW))
+	if m.focus == groupFieldIncludes {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Includes:", m.focus == groupFieldIncludes)+" "+inputLine(m.inIncludes, m.focus == groupFieldIncludes, fieldW))
 	lines = append(lines, formSection("SSH", innerW))
 	if m.focus == groupFieldUser {
 		focusLine = len(lines)
@@ -605,10 +791,22 @@ func (m *groupFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Extra args:", m.focus == groupFieldExtraArgs)+" "+inputLine(m.inExtra, m.focus == groupFieldExtraArgs, fieldW))
+	if m.focus == groupFieldJump {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Jump:", m.focus == groupFieldJump)+" "+inputLine(m.inJump, m.focus == groupFieldJump, fieldW))
+	if backendFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Backend:", backendFocused)+" "+backendSegs)
 	if m.focus == groupFieldRemoteCommand {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Remote cmd:", m.focus == groupFieldRemoteCommand)+" "+inputLine(m.inRemote, m.focus == groupFieldRemoteCommand, fieldW))
+	if preflightFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Pre-flight:", preflightFocused)+" "+preflightLine)
 	lines = append(lines, formSection("Tmux", innerW))
 	if openFocused {
 		focusLine = len(lines)
@@ -619,6 +817,10 @@ func (m *groupFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Tmux:", tmuxFocused)+" "+tmuxLine)
+	if reuseFocused {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Reuse window:", reuseFocused)+" "+reuseLine)
 	lines = append(lines, formSection("Panes", innerW))
 	if splitFocused {
 		focusLine = len(lines)
@@ -633,6 +835,10 @@ func (m *groupFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Pane sync:", syncFocused)+" "+syncLine)
+	if m.focus == groupFieldMaxPanes {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Max panes:", m.focus == groupFieldMaxPanes)+" "+inputLine(m.inMaxPanes, m.focus == groupFieldMaxPanes, min(12, fieldW)))
 	if borderPosFocused {
 		focusLine = len(lines)
 	}
@@ -704,3 +910,17 @@ func (m *groupFormModel) View() string {
 	out = append(out, boxBottom(m.width))
 	return strings.Join(out, "\n")
 }
+
+// parseGroupList splits a comma/space separated list of group names,
+// dropping empty and duplicate entries.
+func parseGroupList(s string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if f = strings.TrimSpace(f); f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
 	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
 	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
 
@@ -23,7 +24,7 @@ import (
 type groupHostRow struct {
 	host     string
 	selected bool
-	hasCfg   bool
+	badges   hostBadges
 }
 
 func (i groupHostRow) Title() string       { return i.host }
@@ -42,7 +43,7 @@ func (d groupHostsDelegate) Render(w io.Writer, m list.Model, index int, item li
 		fmt.Fprint(w, item.FilterValue())
 		return
 	}
-	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.hasCfg, false))
+	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.badges))
 }
 
 type groupHostsModel struct {
@@ -62,18 +63,19 @@ type groupHostsModel struct {
 	search textinput.Model
 	focus  focusState
 
-	keymap    keyMap
-	help      help.Model
-	showHelp  bool
-	helpVP    viewport.Model
-	cmdPrompt bool
-	cmdInput  textinput.Model
-	toast     toast
+	keymap   keyMap
+	help     help.Model
+	showHelp bool
+	helpVP   viewport.Model
+	cmdPr    *commandPrompt
+	execPr   runPrompt
+	toast    toast
 
 	confirmQuit         bool
 	confirmRemove       bool
 	removeList          []string
 	confirmConnect      bool
+	explain             *explainView
 	confirmConnectCount int
 	confirmConnectHosts []string
 	pendingConnectFn    func() tea.Cmd
@@ -89,10 +91,11 @@ func newGroupHostsModel(opts Options, groupIndex int) *groupHostsModel {
 		g = opts.Inventory.Groups[groupIndex]
 	}
 
-	items := make([]list.Item, 0, len(g.Hosts))
-	for _, h := range g.Hosts {
-		_, ok := hostConfigFor(opts.Inventory, h)
-		items = append(items, groupHostRow{host: h, hasCfg: ok})
+	members := groupMembers(opts, g)
+	chains := groupChains(opts, g)
+	items := make([]list.Item, 0, len(members))
+	for _, h := range members {
+		items = append(items, groupHostRow{host: h, badges: groupHostBadges(opts, g, chains, h)})
 	}
 
 	l := list.New(items, groupHostsDelegate{}, 0, 0)
@@ -111,8 +114,8 @@ func newGroupHostsModel(opts Options, groupIndex int) *groupHostsModel {
 		opts:       opts,
 		groupIndex: groupIndex,
 		group:      g,
-		allHosts:   append([]string(nil), g.Hosts...),
-		filtered:   append([]string(nil), g.Hosts...),
+		allHosts:   append([]string(nil), members...),
+		filtered:   append([]string(nil), members...),
 		selected:   make(map[string]bool),
 		list:       l,
 		search:     search,
@@ -151,28 +154,34 @@ func (m *groupHostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if m.cmdPrompt {
-			s := msg.String()
-			switch s {
-			case "esc":
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				return m, nil
-			case "enter":
-				cmd := strings.TrimSpace(m.cmdInput.Value())
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				m.cmdInput.SetValue("")
-				if cmd == "" {
-					return m, nil
-				}
-				m.toast = toast{}
-				return m, m.handleConnectWithRemoteCommand(cmd)
-			default:
-				var cmdTea tea.Cmd
-				m.cmdInput, cmdTea = m.cmdInput.Update(msg)
+		if m.execPr != nil {
+			spec, done, cmdTea := m.execPr.update(msg)
+			if !done {
 				return m, cmdTea
 			}
+			m.execPr = nil
+			if spec == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			return m, m.execCmdFor(*spec)
+		}
+
+		if m.cmdPr != nil {
+			choice, done, cmd := m.cmdPr.update(msg)
+			if !done {
+				return m, cmd
+			}
+			m.cmdPr = nil
+			if choice == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			var remember tea.Cmd
+			if choice.adhoc {
+				remember = rememberCommand(&m.opts, choice.command)
+			}
+			return m, tea.Batch(remember, m.handleConnectWithRemoteCommand(choice.command))
 		}
 
 		if m.confirmQuit {
@@ -206,6 +215,13 @@ func (m *groupHostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
+		if m.explain != nil {
+			if msg.String() == "esc" || key.Matches(msg, m.keymap.Explain) || key.Matches(msg, m.keymap.Quit) {
+				m.explain = nil
+			}
+			return m, nil
+		}
+
 		if m.confirmConnect {
 			s := msg.String()
 			switch s {
@@ -255,9 +271,32 @@ func (m *groupHostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.toast = toast{text: "no host selected", level: toastWarn}
 				return m, nil
 			}
+			// Only hosts listed in the group can be removed; dynamic
+			// members come from match patterns or [[sources]], included
+			// ones from another group's list.
+			static := make([]string, 0, len(toRemove))
+			for _, h := range toRemove {
+				if hosts.IsStaticMember(m.group, h) {
+					static = append(static, h)
+				}
+			}
+			if len(static) == 0 {
+				text := "dynamic member: edit the group's match or source"
+				if len(toRemove) == 1 {
+					if b := groupHostBadges(m.opts, m.group, groupChains(m.opts, m.group), toRemove[0]); b.from != "" {
+						text = fmt.Sprintf("included from %q: edit that group", b.from)
+					}
+				}
+				m.toast = toast{text: text, level: toastWarn}
+				return m, nil
+			}
 			m.confirmRemove = true
-			m.removeList = toRemove
-			m.toast = toast{text: fmt.Sprintf("remove %d? (y/n)", len(toRemove)), level: toastWarn}
+			m.removeList = static
+			text := fmt.Sprintf("remove %d? (y/n)", len(static))
+			if skipped := len(toRemove) - len(static); skipped > 0 {
+				text = fmt.Sprintf("remove %d? (%d dynamic or included skipped) (y/n)", len(static), skipped)
+			}
+			m.toast = toast{text: text, level: toastWarn}
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.FocusSearch) {
@@ -322,24 +361,30 @@ func (m *groupHostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.ConnectCmd) && m.focus == focusList {
-			in := textinput.New()
-			in.CharLimit = 512
-			in.Prompt = "cmd: "
-			in.Placeholder = "run on remote, keep session open"
-			mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-			innerW, _ := frameInnerSize(mw, mh)
-			avail := innerW - len(in.Prompt)
-			if avail < 1 {
-				avail = 1
+			m.cmdPr = newCommandPrompt(m.width, m.height, "Groups > "+m.group.Name, "Connect and run a remote command (keeps session open).",
+				offeredSnippets(m.opts, &m.group, m.ghHostsToOpen()), m.opts.CommandHistory)
+			return m, nil
+		}
+		if key.Matches(msg, m.keymap.Exec) && m.focus == focusList {
+			if len(m.execHosts()) == 0 {
+				m.toast = toast{text: "group has no hosts", level: toastWarn}
+				return m, nil
+			}
+			m.execPr = newExecPrompt(m.width, m.height, "Groups > "+m.group.Name, len(m.execHosts()))
+			return m, nil
+		}
+		if key.Matches(msg, m.keymap.Transfer) && m.focus == focusList {
+			if len(m.execHosts()) == 0 {
+				m.toast = toast{text: "group has no hosts", level: toastWarn}
+				return m, nil
 			}
-			in.Width = min(70, avail)
-			in.Focus()
-			configureSearch(&in)
-			setSearchFocused(&in, true)
-			m.cmdInput = in
-			m.cmdPrompt = true
+			m.execPr = newTransferPrompt(m.width, m.height, "Groups > "+m.group.Name, len(m.execHosts()))
 			return m, nil
 		}
+		if key.Matches(msg, m.keymap.SFTP) && m.focus == focusList {
+			m.toast = toast{}
+			return m, m.openSFTP()
+		}
 		if key.Matches(msg, m.keymap.Connect) {
 			if m.focus == focusSearch {
 				if len(m.list.Items()) == 0 && m.search.Value() != "" {
@@ -369,6 +414,15 @@ func (m *groupHostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if key.Matches(msg, m.keymap.CustomHost) && m.focus == focusList {
 			return m, func() tea.Msg { return openCustomHostMsg{returnTo: screenGroupHosts, groupIndex: m.groupIndex} }
 		}
+		if key.Matches(msg, m.keymap.Explain) && m.focus == focusList {
+			row, ok := m.list.SelectedItem().(groupHostRow)
+			if !ok || strings.TrimSpace(row.host) == "" {
+				m.toast = toast{text: "no host selected", level: toastWarn}
+				return m, nil
+			}
+			m.explain = newExplainView(m.opts, groupChains(m.opts, m.group).For(m.group, row.host), row.host)
+			return m, nil
+		}
 		if key.Matches(msg, m.keymap.HostConfig) && m.focus == focusList {
 			row, ok := m.list.SelectedItem().(groupHostRow)
 			if !ok || strings.TrimSpace(row.host) == "" {
@@ -420,16 +474,11 @@ func (m *groupHostsModel) View() string {
 	if m.showHelp {
 		return renderHelpModalWithVP(m.width, m.height, "Group Hosts", m.help, m.helpKeys(), &m.helpVP)
 	}
-	if m.cmdPrompt {
-		mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-		var b strings.Builder
-		b.WriteString("Connect and run a remote command (keeps session open).\n\n")
-		b.WriteString(m.cmdInput.View())
-		b.WriteString("\n")
-		b.WriteString(footerStyle.Render("Enter connect  Esc cancel"))
-		breadcrumb := "Groups > " + m.group.Name
-		box := renderFrame(mw, mh, breadcrumbTitle(breadcrumb, "Command"), "", strings.TrimRight(b.String(), "\n"), "")
-		return placeCentered(m.width, m.height, box)
+	if m.execPr != nil {
+		return m.execPr.View(m.width, m.height)
+	}
+	if m.cmdPr != nil {
+		return m.cmdPr.View(m.width, m.height)
 	}
 	if m.confirmQuit {
 		return renderQuitConfirm(m.width, m.height)
@@ -438,6 +487,9 @@ func (m *groupHostsModel) View() string {
 		modal := connectConfirmBox(max(0, m.width-4), m.confirmConnectCount, m.confirmConnectHosts)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
 	}
+	if m.explain != nil {
+		return placeCentered(m.width, m.height, explainBox(max(0, m.width-4), m.explain))
+	}
 	if m.confirmRemove {
 		innerW := max(0, m.width-2)
 		innerH := max(0, m.height-2)
@@ -470,7 +522,7 @@ func (m *groupHostsModel) View() string {
 	if m.width < 60 {
 		footer = styledFooter("\u21b5 connect  \u2423 select  esc back  ? help")
 	} else {
-		footer = styledFooter("\u21b5 connect  O pane  ·  \u2423 select  o panes  ·  Ctrl+o cmd  a add")
+		footer = styledFooter("\u21b5 connect  O pane  ·  \u2423 select  o panes  ·  Ctrl+o cmd  X exec  a add")
 		if m.height >= 20 {
 			footer += "\n" + styledFooter("e config  c custom  d remove  y copy  ·  tab search  esc back  ? help")
 		}
@@ -503,10 +555,14 @@ func (m *groupHostsModel) helpKeys() helpMap {
 			m.keymap.Connect,
 			m.keymap.ConnectSame,
 			m.keymap.ConnectCmd,
+			m.keymap.Exec,
+			m.keymap.Transfer,
+			m.keymap.SFTP,
 			m.keymap.OneWindow,
 			m.keymap.AddHosts,
 			m.keymap.CustomHost,
 			m.keymap.HostConfig,
+			m.keymap.Explain,
 			m.keymap.Copy,
 			remove,
 			esc,
@@ -529,11 +585,15 @@ func (m *groupHostsModel) helpKeys() helpMap {
 			m.keymap.Connect,
 			m.keymap.ConnectSame,
 			m.keymap.ConnectCmd,
+			m.keymap.Exec,
+			m.keymap.Transfer,
+			m.keymap.SFTP,
 			m.keymap.OneWindow,
 		}, {
 			m.keymap.AddHosts,
 			m.keymap.CustomHost,
 			m.keymap.HostConfig,
+			m.keymap.Explain,
 			m.keymap.Copy,
 			remove,
 		}, {
@@ -610,10 +670,10 @@ func (m *groupHostsModel) applyFilter(query string) {
 }
 
 func (m *groupHostsModel) setListItems(hosts []string) {
+	chains := groupChains(m.opts, m.group)
 	items := make([]list.Item, 0, len(hosts))
 	for _, h := range hosts {
-		_, ok := hostConfigFor(m.opts.Inventory, h)
-		items = append(items, groupHostRow{host: h, selected: m.selected[h], hasCfg: ok})
+		items = append(items, groupHostRow{host: h, selected: m.selected[h], badges: groupHostBadges(m.opts, m.group, chains, h)})
 	}
 	m.list.SetItems(items)
 	if len(items) > 0 {
@@ -636,14 +696,14 @@ func (m *groupHostsModel) refreshVisibleSelection() {
 
 func (m *groupHostsModel) refreshVisibleBadges() {
 	idx := m.list.Index()
+	chains := groupChains(m.opts, m.group)
 	items := m.list.Items()
 	for i := range items {
 		row, ok := items[i].(groupHostRow)
 		if !ok {
 			continue
 		}
-		_, ok = hostConfigFor(m.opts.Inventory, row.host)
-		row.hasCfg = ok
+		row.badges = groupHostBadges(m.opts, m.group, chains, row.host)
 		items[i] = row
 	}
 	m.list.SetItems(items)
@@ -689,36 +749,57 @@ func (m *groupHostsModel) ghHostsToOpen() []string {
 	return nil
 }
 
-func (m *groupHostsModel) resolveGroupMode() (tmx.OpenMode, bool) {
-	defaults := m.opts.Config.Defaults
-	tmuxSetting := defaults.Tmux
-	if strings.TrimSpace(m.group.Tmux) != "" {
-		tmuxSetting = m.group.Tmux
+// execHosts returns the hosts an exec or a copy runs on: the selection, or
+// every member of the group.
+func (m *groupHostsModel) execHosts() []string {
+	if sel := m.selectedHosts(); len(sel) > 0 {
+		return sel
 	}
-	openModeSetting := defaults.OpenMode
-	if strings.TrimSpace(m.group.OpenMode) != "" {
-		openModeSetting = m.group.OpenMode
+	return m.allHosts
+}
+
+func (m *groupHostsModel) execCmdFor(spec execSpec) tea.Cmd {
+	hosts := append([]string(nil), m.execHosts()...)
+	idx := m.groupIndex
+	return func() tea.Msg {
+		return openExecMsg{groupIndex: idx, hosts: hosts, spec: spec, returnTo: screenGroupHosts}
 	}
-	inTmux := tmx.InTmux()
-	return tmx.ResolveOpenMode(tmuxSetting, openModeSetting, inTmux), inTmux
 }
 
-func (m *groupHostsModel) buildGroupSSHCmds(hosts []string, modifySettings func(*sshcmd.Settings)) [][]string {
-	base := sshcmd.FromDefaults(m.opts.Config.Defaults)
-	cmds := make([][]string, 0, len(hosts))
-	for _, h := range hosts {
-		s := base
-		if hc, ok := hostConfigFor(m.opts.Inventory, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
-		}
-		s = sshcmd.ApplyGroup(s, m.group)
-		if modifySettings != nil {
-			modifySettings(&s)
-		}
-		cmd, _ := sshcmd.BuildCommand(h, s)
-		cmds = append(cmds, cmd)
+// openSFTP opens an sftp session to the host under the cursor.
+func (m *groupHostsModel) openSFTP() tea.Cmd {
+	row, ok := m.list.SelectedItem().(groupHostRow)
+	if !ok || row.host == "" {
+		m.toast = toast{text: "no host selected", level: toastWarn}
+		return nil
+	}
+	s := resolveSSH(m.opts, groupChains(m.opts, m.group).For(m.group, row.host), row.host)
+	res, cmd := sftpConnect(m.opts, row.host, s, resolveWindow(m.opts, &m.group))
+	if !res.toast.empty() {
+		m.toast = res.toast
+	}
+	if res.quit {
+		m.execCmd = res.execCmd
+		return tea.Quit
+	}
+	return cmd
+}
+
+func (m *groupHostsModel) resolveGroupMode(hosts int) (tmx.OpenMode, bool) {
+	win := resolveWindow(m.opts, &m.group)
+	inMux := insideMux(m.opts.Config.Defaults)
+	return tmx.ResolveOpenMode(win.Tmux, win.OpenMode, inMux, hosts), inMux
+}
+
+// planConnect expands the connect of hosts through the group, reporting
+// template errors in a toast.
+func (m *groupHostsModel) planConnect(hosts []string, modifySettings func(*sshcmd.Settings)) (connectPlan, bool) {
+	plan, err := planConnect(m.opts, &m.group, hosts, modifySettings)
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return connectPlan{}, false
 	}
-	return cmds
+	return plan, true
 }
 
 func (m *groupHostsModel) handleConnect() tea.Cmd {
@@ -728,11 +809,14 @@ func (m *groupHostsModel) handleConnect() tea.Cmd {
 		return nil
 	}
 
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
+		return nil
+	}
 	doConnect := func() tea.Cmd {
-		mode, inTmux := m.resolveGroupMode()
-		sshCmds := m.buildGroupSSHCmds(hosts, nil)
+		mode, inMux := m.resolveGroupMode(len(plan.cmds))
 
-		res, cmd := dispatchConnect(hosts, sshCmds, m.opts.Config.Defaults, &m.group, mode, inTmux)
+		res, cmd := dispatchConnect(plan, m.opts.Config.Defaults, &m.group, mode, inMux)
 		if !res.toast.empty() {
 			m.toast = res.toast
 		}
@@ -766,14 +850,17 @@ func (m *groupHostsModel) handleConnectWithRemoteCommand(remoteCmd string) tea.C
 		return nil
 	}
 
+	plan, ok := m.planConnect(hosts, func(s *sshcmd.Settings) {
+		s.ForceTTY = true
+		s.RemoteCommand = sshcmd.KeepSessionOpen(remoteCmd)
+	})
+	if !ok {
+		return nil
+	}
 	doConnect := func() tea.Cmd {
-		mode, inTmux := m.resolveGroupMode()
-		sshCmds := m.buildGroupSSHCmds(hosts, func(s *sshcmd.Settings) {
-			s.ExtraArgs = ensureSSHForceTTY(s.ExtraArgs)
-			s.RemoteCommand = keepSessionOpenRemoteCmd(remoteCmd)
-		})
+		mode, inMux := m.resolveGroupMode(len(plan.cmds))
 
-		res, cmd := dispatchConnect(hosts, sshCmds, m.opts.Config.Defaults, &m.group, mode, inTmux)
+		res, cmd := dispatchConnect(plan, m.opts.Config.Defaults, &m.group, mode, inMux)
 		if !res.toast.empty() {
 			m.toast = res.toast
 		}
@@ -804,8 +891,11 @@ func (m *groupHostsModel) handleConnectSame() tea.Cmd {
 		m.toast = toast{text: "select single host for same-window connect", level: toastWarn}
 		return nil
 	}
-	sshCmds := m.buildGroupSSHCmds(hosts, nil)
-	m.execCmd = sshCmds[0]
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
+		return nil
+	}
+	m.execCmd = plan.cmds[0]
 	return tea.Quit
 }
 
@@ -815,32 +905,21 @@ func (m *groupHostsModel) openOneWindow() tea.Cmd {
 		m.toast = toast{text: "no host selected", level: toastWarn}
 		return nil
 	}
-	if !tmx.InTmux() {
-		m.toast = toast{text: "requires an active tmux session", level: toastWarn}
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
 		return nil
 	}
-
 	doConnect := func() tea.Cmd {
-		sshCmds := m.buildGroupSSHCmds(hosts, nil)
-		defaults := m.opts.Config.Defaults
-		group := m.group
-		return func() tea.Msg {
-			ps := resolvePaneSettings(defaults, &group, len(sshCmds))
-			name := tmuxWindowName(hosts, &group)
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       name,
-				PaneTitles:       hosts,
-				SplitFlag:        ps.SplitFlag,
-				Layout:           ps.Layout,
-				SyncPanes:        ps.SyncPanes,
-				PaneBorderFormat: ps.BorderFormat,
-				PaneBorderStatus: ps.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg{text: err.Error(), level: toastErr}
-			}
-			return toastMsg{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}
+		// One window, whatever the open mode: tmux-pane.
+		res, cmd := dispatchConnect(plan, m.opts.Config.Defaults, &m.group, tmx.OpenPane, insideMux(m.opts.Config.Defaults))
+		if !res.toast.empty() {
+			m.toast = res.toast
 		}
+		if res.quit {
+			m.execCmd = res.execCmd
+			return tea.Quit
+		}
+		return cmd
 	}
 
 	if len(hosts) > connectThreshold(m.opts.Config.Defaults) {
@@ -3,7 +3,6 @@ package ui
 import (
 	"fmt"
 	"io"
-	"os/exec"
 	"strings"
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
@@ -33,12 +32,13 @@ func (d groupsDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		fmt.Fprint(w, item.FilterValue())
 		return
 	}
-	fmt.Fprint(w, renderGroupRow(m.Width(), index == m.Index(), row.name, row.hostCount, row.hasCfg))
+	fmt.Fprint(w, renderGroupRow(m.Width(), index == m.Index(), row.tree+row.name, row.hostCount, row.hasCfg))
 }
 
 type groupRow struct {
 	index     int
 	name      string
+	tree      string // "├─ "/"└─ " prefix for included groups; empty when filtered
 	hostCount int
 	hasCfg    bool
 }
@@ -60,13 +60,13 @@ type groupsModel struct {
 	search textinput.Model
 	focus  focusState
 
-	keymap    keyMap
-	help      help.Model
-	showHelp  bool
-	helpVP    viewport.Model
-	cmdPrompt bool
-	cmdInput  textinput.Model
-	toast     toast
+	keymap   keyMap
+	help     help.Model
+	showHelp bool
+	helpVP   viewport.Model
+	cmdPr    *commandPrompt
+	execPr   runPrompt
+	toast    toast
 
 	confirmQuit         bool
 	confirmDelete       bool
@@ -75,6 +75,7 @@ type groupsModel struct {
 	confirmConnectCount int
 	confirmConnectHosts []string
 	pendingConnectFn    func() tea.Cmd
+	preflight           *preflightDoneMsg // pre-flight summary waiting for a choice
 
 	prevSearch string
 
@@ -83,7 +84,7 @@ type groupsModel struct {
 }
 
 func newGroupsModel(opts Options) *groupsModel {
-	rows := groupsRows(opts.Inventory)
+	rows := groupsRows(opts)
 	items := make([]list.Item, 0, len(rows))
 	for _, r := range rows {
 		items = append(items, r)
@@ -116,10 +117,53 @@ func newGroupsModel(opts Options) *groupsModel {
 	return m
 }
 
-func groupsRows(inv config.Inventory) []groupRow {
-	rows := make([]groupRow, 0, len(inv.Groups))
-	for i, g := range inv.Groups {
-		rows = append(rows, groupRow{index: i, name: g.Name, hostCount: len(g.Hosts), hasCfg: groupHasCfg(g)})
+// groupsRows lists the groups as a tree: groups that no other group
+// includes are roots, each followed by its include_groups (recursively).
+// A group included from several parents is listed under each of them.
+func groupsRows(opts Options) []groupRow {
+	groups := opts.Inventory.Groups
+	included := make(map[string]bool)
+	for _, g := range groups {
+		for _, name := range g.IncludeGroups {
+			included[name] = true
+		}
+	}
+
+	rows := make([]groupRow, 0, len(groups))
+	onPath := make(map[int]bool)
+	var walk func(i int, indent, branch string)
+	walk = func(i int, indent, branch string) {
+		if onPath[i] {
+			return
+		}
+		onPath[i] = true
+		defer delete(onPath, i)
+
+		g := groups[i]
+		rows = append(rows, groupRow{index: i, name: g.Name, tree: indent + branch, hostCount: len(groupMembers(opts, g)), hasCfg: groupHasCfg(g)})
+		var children []int
+		for _, name := range g.IncludeGroups {
+			if j := config.FindGroup(opts.Inventory, name); j >= 0 {
+				children = append(children, j)
+			}
+		}
+		if branch == "├─ " {
+			indent += "│  "
+		} else if branch == "└─ " {
+			indent += "   "
+		}
+		for k, j := range children {
+			if k == len(children)-1 {
+				walk(j, indent, "└─ ")
+			} else {
+				walk(j, indent, "├─ ")
+			}
+		}
+	}
+	for i, g := range groups {
+		if !included[g.Name] {
+			walk(i, "", "")
+		}
 	}
 	return rows
 }
@@ -129,6 +173,8 @@ func groupHasCfg(g config.Group) bool {
 		g.Port != 0 ||
 		strings.TrimSpace(g.IdentityFile) != "" ||
 		len(g.ExtraArgs) > 0 ||
+		len(g.Jump) > 0 ||
+		strings.TrimSpace(g.Backend) != "" ||
 		strings.TrimSpace(g.RemoteCommand) != "" ||
 		strings.TrimSpace(g.Tmux) != "" ||
 		strings.TrimSpace(g.OpenMode) != "" ||
@@ -153,7 +199,7 @@ func (m *groupsModel) setRows(rows []groupRow) {
 
 func (m *groupsModel) Refresh(inv config.Inventory) {
 	m.opts.Inventory = inv
-	m.allRows = groupsRows(inv)
+	m.allRows = groupsRows(m.opts)
 	m.applyFilter(m.search.Value())
 }
 
@@ -184,28 +230,34 @@ func (m *groupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if m.cmdPrompt {
-			s := msg.String()
-			switch s {
-			case "esc":
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				return m, nil
-			case "enter":
-				cmd := strings.TrimSpace(m.cmdInput.Value())
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				m.cmdInput.SetValue("")
-				if cmd == "" {
-					return m, nil
-				}
-				m.toast = toast{}
-				return m, m.connectAllCmd(false, cmd)
-			default:
-				var cmdTea tea.Cmd
-				m.cmdInput, cmdTea = m.cmdInput.Update(msg)
+		if m.execPr != nil {
+			spec, done, cmdTea := m.execPr.update(msg)
+			if !done {
 				return m, cmdTea
 			}
+			m.execPr = nil
+			if spec == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			return m, m.execAllCmd(*spec)
+		}
+
+		if m.cmdPr != nil {
+			choice, done, cmd := m.cmdPr.update(msg)
+			if !done {
+				return m, cmd
+			}
+			m.cmdPr = nil
+			if choice == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			var remember tea.Cmd
+			if choice.adhoc {
+				remember = rememberCommand(&m.opts, choice.command)
+			}
+			return m, tea.Batch(remember, m.connectAllCmd(false, choice.command))
 		}
 
 		if m.confirmQuit {
@@ -237,6 +289,35 @@ func (m *groupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
+		if m.preflight != nil {
+			pf := m.preflight
+			g := pf.group
+			switch msg.String() {
+			case "o", "O", "enter":
+				if len(pf.report.Up) == 0 {
+					m.toast = toast{text: "no reachable hosts", level: toastWarn}
+					return m, nil
+				}
+				m.preflight = nil
+				g.Hosts = pf.report.Up
+				plan, ok := m.planConnectAll(g, pf.remoteCmd)
+				if !ok {
+					return m, nil
+				}
+				return m, m.doConnectAll(g, pf.oneWindow, plan)
+			case "a", "A":
+				m.preflight = nil
+				plan, ok := m.planConnectAll(g, pf.remoteCmd)
+				if !ok {
+					return m, nil
+				}
+				return m, m.doConnectAll(g, pf.oneWindow, plan)
+			case "n", "N", "esc":
+				m.preflight = nil
+				m.toast = toast{}
+			}
+			return m, nil
+		}
 		if m.confirmConnect {
 			s := msg.String()
 			switch s {
@@ -296,7 +377,7 @@ func (m *groupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.SwitchTab) && m.focus != focusSearch {
-			return m, func() tea.Msg { return switchScreenMsg{to: screenHosts} }
+			return m, func() tea.Msg { return switchScreenMsg{to: screenTunnels} }
 		}
 		if key.Matches(msg, m.keymap.Esc) {
 			if m.focus == focusSearch && m.search.Value() == "" {
@@ -371,22 +452,35 @@ func (m *groupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg { return openGroupHostsMsg{index: row.index} }
 		}
 		if key.Matches(msg, m.keymap.ConnectCmd) && m.focus == focusList {
-			in := textinput.New()
-			in.CharLimit = 512
-			in.Prompt = "cmd: "
-			in.Placeholder = "run on remote, keep session open"
-			mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-			innerW, _ := frameInnerSize(mw, mh)
-			avail := innerW - len(in.Prompt)
-			if avail < 1 {
-				avail = 1
+			var group *config.Group
+			var members []string
+			if row, ok := m.list.SelectedItem().(groupRow); ok && row.index >= 0 && row.index < len(m.opts.Inventory.Groups) {
+				g := m.opts.Inventory.Groups[row.index]
+				group = &g
+				members = groupMembers(m.opts, g)
+			}
+			m.cmdPr = newCommandPrompt(m.width, m.height, "Groups", "Connect and run a remote command for all hosts (keeps sessions open).",
+				offeredSnippets(m.opts, group, members), m.opts.CommandHistory)
+			return m, nil
+		}
+		if key.Matches(msg, m.keymap.Exec) && m.focus == focusList {
+			row, ok := m.list.SelectedItem().(groupRow)
+			if !ok || row.index < 0 || row.index >= len(m.opts.Inventory.Groups) {
+				m.toast = toast{text: "no group selected", level: toastWarn}
+				return m, nil
+			}
+			g := m.opts.Inventory.Groups[row.index]
+			m.execPr = newExecPrompt(m.width, m.height, "Groups > "+g.Name, len(groupMembers(m.opts, g)))
+			return m, nil
+		}
+		if key.Matches(msg, m.keymap.Transfer) && m.focus == focusList {
+			row, ok := m.list.SelectedItem().(groupRow)
+			if !ok || row.index < 0 || row.index >= len(m.opts.Inventory.Groups) {
+				m.toast = toast{text: "no group selected", level: toastWarn}
+				return m, nil
 			}
-			in.Width = min(70, avail)
-			in.Focus()
-			configureSearch(&in)
-			setSearchFocused(&in, true)
-			m.cmdInput = in
-			m.cmdPrompt = true
+			g := m.opts.Inventory.Groups[row.index]
+			m.execPr = newTransferPrompt(m.width, m.height, "Groups > "+g.Name, len(groupMembers(m.opts, g)))
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.ConnectAll) && m.focus == focusList {
@@ -414,6 +508,13 @@ func (m *groupsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 		return m, nil
+	case preflightDoneMsg:
+		m.toast = toast{}
+		if len(msg.report.Down) == 0 {
+			return m, m.confirmConnectAll(msg.group, msg.oneWindow, msg.remoteCmd)
+		}
+		m.preflight = &msg
+		return m, nil
 	}
 
 	var cmd tea.Cmd
@@ -435,15 +536,11 @@ func (m *groupsModel) View() string {
 	if m.showHelp {
 		return renderHelpModalWithVP(m.width, m.height, "Groups", m.help, m.helpKeys(), &m.helpVP)
 	}
-	if m.cmdPrompt {
-		mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-		var b strings.Builder
-		b.WriteString("Connect and run a remote command for all hosts (keeps sessions open).\n\n")
-		b.WriteString(m.cmdInput.View())
-		b.WriteString("\n")
-		b.WriteString(footerStyle.Render("Enter connect  Esc cancel"))
-		box := renderFrame(mw, mh, breadcrumbTitle("Groups", "Command"), "", strings.TrimRight(b.String(), "\n"), "")
-		return placeCentered(m.width, m.height, box)
+	if m.execPr != nil {
+		return m.execPr.View(m.width, m.height)
+	}
+	if m.cmdPr != nil {
+		return m.cmdPr.View(m.width, m.height)
 	}
 	if m.confirmQuit {
 		return renderQuitConfirm(m.width, m.height)
@@ -452,6 +549,10 @@ func (m *groupsModel) View() string {
 		modal := connectConfirmBox(max(0, m.width-4), m.confirmConnectCount, m.confirmConnectHosts)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
 	}
+	if m.preflight != nil {
+		modal := preflightConfirmBox(max(0, m.width-4), m.preflight.report)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+	}
 	if m.confirmDelete {
 		name := ""
 		hostCount := 0
@@ -464,7 +565,7 @@ func (m *groupsModel) View() string {
 		contentH := max(0, innerH-4)
 		modal := deleteGroupConfirmBox(innerW, name, hostCount)
 		placed := lipgloss.Place(innerW, contentH, lipgloss.Center, lipgloss.Center, modal)
-		right := statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d groups", len(m.allRows)))
+		right := statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d groups", len(m.opts.Inventory.Groups)))
 		return renderMainTabBox(m.width, m.height, 1, m.search.View(), right, placed)
 	}
 
@@ -472,15 +573,15 @@ func (m *groupsModel) View() string {
 	if !m.toast.empty() {
 		right = renderToast(m.toast)
 	} else {
-		right = statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d groups", len(m.allRows)))
+		right = statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d groups", len(m.opts.Inventory.Groups)))
 	}
 	var footer string
 	if m.width < 60 {
 		footer = styledFooter("\u21b5 open  C connect  ? help")
 	} else {
-		footer = styledFooter("\u21b5 open  C connect  ·  o panes  Ctrl+o cmd  ·  n new")
+		footer = styledFooter("\u21b5 open  C connect  ·  o panes  Ctrl+o cmd  X exec  ·  n new")
 		if m.height >= 20 {
-			footer += "\n" + styledFooter("e edit  d delete  y copy  a add hosts  c custom  ·  g hosts  tab search  ? help")
+			footer += "\n" + styledFooter("e edit  d delete  y copy  a add hosts  c custom  ·  g tunnels  tab search  ? help")
 		}
 	}
 
@@ -505,6 +606,8 @@ func (m *groupsModel) helpKeys() helpMap {
 			openGroup,
 			m.keymap.ConnectAll,
 			m.keymap.ConnectCmd,
+			m.keymap.Exec,
+			m.keymap.Transfer,
 			m.keymap.OneWindow,
 			m.keymap.CustomHost,
 			m.keymap.NewGroup,
@@ -535,6 +638,8 @@ func (m *groupsModel) helpKeys() helpMap {
 			openGroup,
 			m.keymap.ConnectAll,
 			m.keymap.ConnectCmd,
+			m.keymap.Exec,
+			m.keymap.Transfer,
 			m.keymap.OneWindow,
 			m.keymap.CustomHost,
 			m.keymap.AddHosts,
@@ -600,14 +705,25 @@ func (m *groupsModel) applyFilter(query string) {
 		return
 	}
 
-	names := make([]string, 0, len(m.allRows))
+	// Filtered results are flat: one row per group, without tree prefixes.
+	flat := make([]groupRow, 0, len(m.opts.Inventory.Groups))
+	seen := make(map[int]bool, len(m.opts.Inventory.Groups))
 	for _, r := range m.allRows {
+		if seen[r.index] {
+			continue
+		}
+		seen[r.index] = true
+		r.tree = ""
+		flat = append(flat, r)
+	}
+	names := make([]string, 0, len(flat))
+	for _, r := range flat {
 		names = append(names, r.name)
 	}
 	matches := fuzzy.Find(query, names)
 	rows := make([]groupRow, 0, len(matches))
 	for _, mt := range matches {
-		rows = append(rows, m.allRows[mt.Index])
+		rows = append(rows, flat[mt.Index])
 	}
 	m.setRows(rows)
 }
@@ -623,154 +739,85 @@ func (m *groupsModel) connectAllCmd(oneWindow bool, remoteCmd string) tea.Cmd {
 		return nil
 	}
 	g := m.opts.Inventory.Groups[row.index]
+	g.Hosts = groupMembers(m.opts, g)
 	if len(g.Hosts) == 0 {
 		m.toast = toast{text: "group has no hosts", level: toastWarn}
 		return nil
 	}
-	if oneWindow && !tmx.InTmux() {
-		m.toast = toast{text: "requires an active tmux session", level: toastWarn}
-		return nil
+	if strings.TrimSpace(g.Preflight) == config.PreflightTCP {
+		chains := groupChains(m.opts, m.opts.Inventory.Groups[row.index])
+		m.toast = toast{text: fmt.Sprintf("checking %d hosts…", len(g.Hosts)), level: toastInfo}
+		return preflightCmd(m.opts, g, chains, oneWindow, remoteCmd)
 	}
+	return m.confirmConnectAll(g, oneWindow, remoteCmd)
+}
 
+// confirmConnectAll connects to the hosts of g, asking first when they are
+// more than connect_confirm_threshold. Template errors are reported before.
+func (m *groupsModel) confirmConnectAll(g config.Group, oneWindow bool, remoteCmd string) tea.Cmd {
+	plan, ok := m.planConnectAll(g, remoteCmd)
+	if !ok {
+		return nil
+	}
 	if len(g.Hosts) > connectThreshold(m.opts.Config.Defaults) {
 		m.confirmConnect = true
 		m.confirmConnectCount = len(g.Hosts)
 		m.confirmConnectHosts = append([]string(nil), g.Hosts...)
 		m.pendingConnectFn = func() tea.Cmd {
-			return m.doConnectAll(g, oneWindow, remoteCmd)
+			return m.doConnectAll(g, oneWindow, plan)
 		}
 		return nil
 	}
-	return m.doConnectAll(g, oneWindow, remoteCmd)
+	return m.doConnectAll(g, oneWindow, plan)
 }
 
-func (m *groupsModel) doConnectAll(g config.Group, oneWindow bool, remoteCmd string) tea.Cmd {
-	defaults := m.opts.Config.Defaults
-	base := sshcmd.FromDefaults(defaults)
-	rc := strings.TrimSpace(remoteCmd)
-
-	tmuxSetting := defaults.Tmux
-	if strings.TrimSpace(g.Tmux) != "" {
-		tmuxSetting = g.Tmux
+// planConnectAll expands the connect of the hosts of g, with remoteCmd when
+// set, reporting template errors in a toast.
+func (m *groupsModel) planConnectAll(g config.Group, remoteCmd string) (connectPlan, bool) {
+	var modify func(*sshcmd.Settings)
+	if rc := strings.TrimSpace(remoteCmd); rc != "" {
+		modify = func(s *sshcmd.Settings) {
+			s.ForceTTY = true
+			s.RemoteCommand = sshcmd.KeepSessionOpen(rc)
+		}
 	}
-	openModeSetting := defaults.OpenMode
-	if strings.TrimSpace(g.OpenMode) != "" {
-		openModeSetting = g.OpenMode
+	plan, err := planConnect(m.opts, &g, g.Hosts, modify)
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return connectPlan{}, false
 	}
+	return plan, true
+}
 
-	inTmux := tmx.InTmux()
-	mode := tmx.ResolveOpenMode(tmuxSetting, openModeSetting, inTmux)
-
-	sshCmds := make([][]string, 0, len(g.Hosts))
-	for _, h := range g.Hosts {
-		s := base
-		if hc, ok := hostConfigFor(m.opts.Inventory, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
-		}
-		s = sshcmd.ApplyGroup(s, g)
-		if rc != "" {
-			s.ExtraArgs = ensureSSHForceTTY(s.ExtraArgs)
-			s.RemoteCommand = keepSessionOpenRemoteCmd(rc)
-		}
-		cmd, _ := sshcmd.BuildCommand(h, s)
-		sshCmds = append(sshCmds, cmd)
+// execAllCmd runs spec (a command or a copy) on every member of the
+// selected group.
+func (m *groupsModel) execAllCmd(spec execSpec) tea.Cmd {
+	row, ok := m.list.SelectedItem().(groupRow)
+	if !ok || row.index < 0 || row.index >= len(m.opts.Inventory.Groups) {
+		m.toast = toast{text: "no group selected", level: toastWarn}
+		return nil
+	}
+	return func() tea.Msg {
+		return openExecMsg{groupIndex: row.index, spec: spec, returnTo: screenGroups}
 	}
+}
 
+func (m *groupsModel) doConnectAll(g config.Group, oneWindow bool, plan connectPlan) tea.Cmd {
+	win := resolveWindow(m.opts, &g)
+	inMux := insideMux(m.opts.Config.Defaults)
+	mode := tmx.ResolveOpenMode(win.Tmux, win.OpenMode, inMux, len(plan.cmds))
 	if oneWindow {
-		return func() tea.Msg {
-			name := strings.TrimSpace(g.Name)
-			if name == "" {
-				name = windowName(g.Hosts[0])
-			}
-			psOne := resolvePaneSettings(defaults, &g, len(sshCmds))
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       name,
-				PaneTitles:       g.Hosts,
-				SplitFlag:        psOne.SplitFlag,
-				Layout:           psOne.Layout,
-				SyncPanes:        psOne.SyncPanes,
-				PaneBorderFormat: psOne.BorderFormat,
-				PaneBorderStatus: psOne.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg{text: err.Error(), level: toastErr}
-			}
-			return toastMsg{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}
-		}
+		mode = tmx.OpenPane
 	}
-
-	if mode == tmx.OpenCurrent {
-		if len(sshCmds) > 1 {
-			m.toast = toast{text: "multi-host requires tmux (window or pane mode)", level: toastWarn}
-			return nil
-		}
-		m.execCmd = sshCmds[0]
-		return tea.Quit
+	res, cmd := dispatchConnect(plan, m.opts.Config.Defaults, &g, mode, inMux)
+	if !res.toast.empty() {
+		m.toast = res.toast
 	}
-	if !inTmux {
-		if len(sshCmds) > 1 {
-			m.toast = toast{text: "multi-host requires an active tmux session", level: toastWarn}
-			return nil
-		}
-		m.execCmd = tmx.NewSessionCmd(defaults.TmuxSession, sshCmds[0])
+	if res.quit {
+		m.execCmd = res.execCmd
 		return tea.Quit
 	}
-
-	return func() tea.Msg {
-		if mode == tmx.OpenPane {
-			name := strings.TrimSpace(g.Name)
-			if name == "" {
-				name = windowName(g.Hosts[0])
-			}
-			ps := resolvePaneSettings(defaults, &g, len(sshCmds))
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       name,
-				PaneTitles:       g.Hosts,
-				SplitFlag:        ps.SplitFlag,
-				Layout:           ps.Layout,
-				SyncPanes:        ps.SyncPanes,
-				PaneBorderFormat: ps.BorderFormat,
-				PaneBorderStatus: ps.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg{text: err.Error(), level: toastErr}
-			}
-			return toastMsg{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}
-		}
-		if mode == tmx.OpenWindow && len(sshCmds) > 1 {
-			name := strings.TrimSpace(g.Name)
-			if name == "" {
-				name = windowName(g.Hosts[0])
-			}
-			ps := resolvePaneSettings(defaults, &g, len(sshCmds))
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       name,
-				PaneTitles:       g.Hosts,
-				SplitFlag:        ps.SplitFlag,
-				Layout:           ps.Layout,
-				SyncPanes:        ps.SyncPanes,
-				PaneBorderFormat: ps.BorderFormat,
-				PaneBorderStatus: ps.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg{text: err.Error(), level: toastErr}
-			}
-			return toastMsg{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo}
-		}
-
-		for i, sshCmd := range sshCmds {
-			name := strings.TrimSpace(g.Name)
-			if name == "" {
-				name = windowName(g.Hosts[i])
-			}
-			tmuxCmd := tmx.NewWindowCmd(name, sshCmd)
-			// #nosec G204 -- tmux argv is constructed (no shell) from known host/group settings.
-			if err := exec.Command(tmuxCmd[0], tmuxCmd[1:]...).Run(); err != nil {
-				return toastMsg{text: "tmux error: " + err.Error(), level: toastErr}
-			}
-		}
-		return toastMsg{text: fmt.Sprintf("opened %d", len(sshCmds)), level: toastInfo}
-	}
+	return cmd
 }
 
 func (m *groupsModel) IsQuitting() bool  { return m.quitting }
@@ -20,6 +20,10 @@ const (
 	hostFieldPort
 	hostFieldIdentity
 	hostFieldExtraArgs
+	hostFieldJump
+	hostFieldBackend
+	hostFieldDescription
+	hostFieldTags
 )
 
 type hostFormModel struct {
@@ -39,6 +43,9 @@ type hostFormModel struct {
 	inPort     textinput.Model
 	inIdentity textinput.Model
 	inExtra    textinput.Model
+	inJump     textinput.Model
+	inDesc     textinput.Model
+	inTags     textinput.Model
 
 	toast toast
 
@@ -54,6 +61,9 @@ func (m *hostFormModel) refreshAccentStyles() {
 	setSearchFocused(&m.inPort, m.focus == hostFieldPort)
 	setSearchFocused(&m.inIdentity, m.focus == hostFieldIdentity)
 	setSearchFocused(&m.inExtra, m.focus == hostFieldExtraArgs)
+	setSearchFocused(&m.inJump, m.focus == hostFieldJump)
+	setSearchFocused(&m.inDesc, m.focus == hostFieldDescription)
+	setSearchFocused(&m.inTags, m.focus == hostFieldTags)
 }
 
 func newHostFormModel(index int, h config.Host, defs config.Defaults, confirmQuitEnabled bool) *hostFormModel {
@@ -104,6 +114,27 @@ func newHostFormModel(index int, h config.Host, defs config.Defaults, confirmQui
 	}
 	configureSearch(&inExtra)
 
+	inJump := textinput.New()
+	inJump.CharLimit = 512
+	inJump.Prompt = ""
+	inJump.SetValue(strings.Join(h.Jump, ", "))
+	inJump.Placeholder = "bastion, admin@gw:2222"
+	configureSearch(&inJump)
+
+	inDesc := textinput.New()
+	inDesc.CharLimit = 256
+	inDesc.Prompt = ""
+	inDesc.SetValue(strings.TrimSpace(h.Description))
+	inDesc.Placeholder = "what this host is for"
+	configureSearch(&inDesc)
+
+	inTags := textinput.New()
+	inTags.CharLimit = 512
+	inTags.Prompt = ""
+	inTags.SetValue(strings.Join(h.Tags, ", "))
+	inTags.Placeholder = "db, prod"
+	configureSearch(&inTags)
+
 	m := &hostFormModel{
 		index:              index,
 		host:               h,
@@ -114,6 +145,9 @@ func newHostFormModel(index int, h config.Host, defs config.Defaults, confirmQui
 		inPort:             inPort,
 		inIdentity:         inIdentity,
 		inExtra:            inExtra,
+		inJump:             inJump,
+		inDesc:             inDesc,
+		inTags:             inTags,
 		keymap:             defaultKeyMap(),
 		confirmQuitEnabled: confirmQuitEnabled,
 	}
@@ -124,6 +158,9 @@ func newHostFormModel(index int, h config.Host, defs config.Defaults, confirmQui
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
+	setSearchFocused(&m.inDesc, false)
+	setSearchFocused(&m.inTags, false)
 	return m
 }
 
@@ -142,6 +179,9 @@ func (m *hostFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.inPort.Width = min(12, fieldW)
 		m.inIdentity.Width = fieldW
 		m.inExtra.Width = fieldW
+		m.inJump.Width = fieldW
+		m.inDesc.Width = fieldW
+		m.inTags.Width = fieldW
 		return m, nil
 	case tea.KeyMsg:
 		if m.confirmQuit {
@@ -207,7 +247,18 @@ func (m *hostFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "k", "up", "shift+tab":
 			return m, m.moveFocus(-1)
 		case "i":
-			m.enterEdit()
+			if m.focus != hostFieldBackend {
+				m.enterEdit()
+			}
+			return m, nil
+		case "h", "l", "left", "right", " ":
+			if m.focus == hostFieldBackend {
+				delta := 1
+				if s == "h" || s == "left" {
+					delta = -1
+				}
+				m.host.Backend = cycleChoice(m.host.Backend, backendChoices(true), delta)
+			}
 			return m, nil
 		}
 	}
@@ -228,6 +279,12 @@ func (m *hostFormModel) updateFocusedInput(msg tea.Msg) tea.Cmd {
 		m.inIdentity, cmd = m.inIdentity.Update(msg)
 	case hostFieldExtraArgs:
 		m.inExtra, cmd = m.inExtra.Update(msg)
+	case hostFieldJump:
+		m.inJump, cmd = m.inJump.Update(msg)
+	case hostFieldDescription:
+		m.inDesc, cmd = m.inDesc.Update(msg)
+	case hostFieldTags:
+		m.inTags, cmd = m.inTags.Update(msg)
 	default:
 		// no-op
 	}
@@ -241,6 +298,10 @@ func (m *hostFormModel) moveFocus(delta int) tea.Cmd {
 		hostFieldPort,
 		hostFieldIdentity,
 		hostFieldExtraArgs,
+		hostFieldJump,
+		hostFieldBackend,
+		hostFieldDescription,
+		hostFieldTags,
 	}
 	pos := 0
 	for i := range order {
@@ -281,11 +342,17 @@ func (m *hostFormModel) setFocus(f hostField) {
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
+	m.inDesc.Blur()
+	m.inTags.Blur()
 	setSearchFocused(&m.inHost, false)
 	setSearchFocused(&m.inUser, false)
 	setSearchFocused(&m.inPort, false)
 	setSearchFocused(&m.inIdentity, false)
 	setSearchFocused(&m.inExtra, false)
+	setSearchFocused(&m.inJump, false)
+	setSearchFocused(&m.inDesc, false)
+	setSearchFocused(&m.inTags, false)
 
 	// Highlight the focused field label.
 	switch f {
@@ -299,6 +366,12 @@ func (m *hostFormModel) setFocus(f hostField) {
 		setSearchFocused(&m.inIdentity, true)
 	case hostFieldExtraArgs:
 		setSearchFocused(&m.inExtra, true)
+	case hostFieldJump:
+		setSearchFocused(&m.inJump, true)
+	case hostFieldDescription:
+		setSearchFocused(&m.inDesc, true)
+	case hostFieldTags:
+		setSearchFocused(&m.inTags, true)
 	}
 }
 
@@ -315,6 +388,12 @@ func (m *hostFormModel) enterEdit() {
 		_ = m.inIdentity.Focus()
 	case hostFieldExtraArgs:
 		_ = m.inExtra.Focus()
+	case hostFieldJump:
+		_ = m.inJump.Focus()
+	case hostFieldDescription:
+		_ = m.inDesc.Focus()
+	case hostFieldTags:
+		_ = m.inTags.Focus()
 	}
 }
 
@@ -325,12 +404,17 @@ func (m *hostFormModel) exitEdit() {
 	m.inPort.Blur()
 	m.inIdentity.Blur()
 	m.inExtra.Blur()
+	m.inJump.Blur()
+	m.inDesc.Blur()
+	m.inTags.Blur()
 }
 
 func (m *hostFormModel) apply() error {
 	m.host.Host = strings.TrimSpace(m.inHost.Value())
 	m.host.User = strings.TrimSpace(m.inUser.Value())
 	m.host.IdentityFile = strings.TrimSpace(m.inIdentity.Value())
+	m.host.Description = strings.TrimSpace(m.inDesc.Value())
+	m.host.Tags = config.ParseTags(m.inTags.Value())
 
 	portStr := strings.TrimSpace(m.inPort.Value())
 	if portStr == "" {
@@ -343,6 +427,12 @@ func (m *hostFormModel) apply() error {
 		m.host.Port = p
 	}
 
+	jump, err := config.ParseJump(m.inJump.Value())
+	if err != nil {
+		return err
+	}
+	m.host.Jump = jump
+
 	extra := strings.TrimSpace(m.inExtra.Value())
 	if extra == "" {
 		m.host.ExtraArgs = nil
@@ -408,9 +498,26 @@ func (m *hostFormModel) View() string {
 		focusLine = len(lines)
 	}
 	lines = append(lines, label("Extra args:", m.focus == hostFieldExtraArgs)+" "+inputLine(m.inExtra, m.focus == hostFieldExtraArgs, fieldW))
+	if m.focus == hostFieldJump {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Jump:", m.focus == hostFieldJump)+" "+inputLine(m.inJump, m.focus == hostFieldJump, fieldW))
+	if m.focus == hostFieldBackend {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Backend:", m.focus == hostFieldBackend)+" "+backendLine(m.host.Backend, backendChoices(true), "inherit", m.focus == hostFieldBackend))
+	lines = append(lines, formSection("Metadata", innerW))
+	if m.focus == hostFieldDescription {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Description:", m.focus == hostFieldDescription)+" "+inputLine(m.inDesc, m.focus == hostFieldDescription, fieldW))
+	if m.focus == hostFieldTags {
+		focusLine = len(lines)
+	}
+	lines = append(lines, label("Tags:", m.focus == hostFieldTags)+" "+inputLine(m.inTags, m.focus == hostFieldTags, fieldW))
 
-	fieldPos := fmt.Sprintf("%d/%d", int(m.focus)+1, int(hostFieldExtraArgs)+1)
-	footer := fieldPos + "  Ctrl+S save   j/k move   i edit   Esc cancel"
+	fieldPos := fmt.Sprintf("%d/%d", int(m.focus)+1, int(hostFieldTags)+1)
+	footer := fieldPos + "  Ctrl+S save   j/k move   h/l option   i edit   Esc cancel"
 	if m.editing {
 		footer = footerStyle.Render(fieldPos) + "  " + headerStyle.Render("INSERT") + "  " + footerStyle.Render("Ctrl+S save   Esc done")
 	}
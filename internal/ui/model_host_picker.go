@@ -23,7 +23,7 @@ type hostPickerDoneMsg struct {
 type pickerRow struct {
 	host     string
 	selected bool
-	hasCfg   bool
+	badges   hostBadges
 }
 
 func (i pickerRow) Title() string       { return i.host }
@@ -42,7 +42,7 @@ func (d pickerDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		fmt.Fprint(w, item.FilterValue())
 		return
 	}
-	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.hasCfg, false))
+	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.badges))
 }
 
 type hostPickerModel struct {
@@ -73,8 +73,7 @@ func newHostPickerModel(opts Options, _groupIndex int) *hostPickerModel {
 	all := append([]string(nil), opts.Hosts...)
 	items := make([]list.Item, 0, len(all))
 	for _, h := range all {
-		_, ok := hostConfigFor(opts.Inventory, h)
-		items = append(items, pickerRow{host: h, hasCfg: ok})
+		items = append(items, pickerRow{host: h, badges: hostBadgesFor(opts, h)})
 	}
 
 	l := list.New(items, pickerDelegate{}, 0, 0)
@@ -345,8 +344,7 @@ func (m *hostPickerModel) applyFilter(query string) {
 func (m *hostPickerModel) setListItems(hosts []string) {
 	items := make([]list.Item, 0, len(hosts))
 	for _, h := range hosts {
-		_, ok := hostConfigFor(m.opts.Inventory, h)
-		items = append(items, pickerRow{host: h, selected: m.selected[h], hasCfg: ok})
+		items = append(items, pickerRow{host: h, selected: m.selected[h], badges: hostBadgesFor(m.opts, h)})
 	}
 	m.list.SetItems(items)
 	if len(items) > 0 {
@@ -375,8 +373,7 @@ func (m *hostPickerModel) refreshVisibleBadges() {
 		if !ok {
 			continue
 		}
-		_, ok = hostConfigFor(m.opts.Inventory, row.host)
-		row.hasCfg = ok
+		row.badges = hostBadgesFor(m.opts, row.host)
 		items[i] = row
 	}
 	m.list.SetItems(items)
@@ -31,8 +31,7 @@ const (
 type hostRow struct {
 	host     string
 	selected bool
-	hasCfg   bool
-	hidden   bool
+	badges   hostBadges
 }
 
 func (i hostRow) Title() string       { return i.host }
@@ -51,7 +50,7 @@ func (d hostDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		fmt.Fprint(w, item.FilterValue())
 		return
 	}
-	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.hasCfg, row.hidden))
+	fmt.Fprint(w, renderHostLikeRow(m.Width(), index == m.Index(), row.selected, row.host, row.badges))
 }
 
 type knownHostsReloadMsg struct {
@@ -82,23 +81,23 @@ type hostsModel struct {
 	confirmQuit bool
 
 	confirmConnect      bool
+	explain             *explainView
 	confirmConnectCount int
 	confirmConnectHosts []string
 	pendingConnectFn    func() tea.Cmd
 
-	quitting  bool
-	showHelp  bool
-	helpVP    viewport.Model
-	cmdPrompt bool
-	cmdInput  textinput.Model
-	execCmd   []string
+	quitting bool
+	showHelp bool
+	helpVP   viewport.Model
+	cmdPr    *commandPrompt
+	execPr   runPrompt
+	execCmd  []string
 }
 
 func newHostsModel(opts Options) *hostsModel {
 	items := make([]list.Item, 0, len(opts.Hosts))
 	for _, h := range opts.Hosts {
-		_, ok := hostConfigFor(opts.Inventory, h)
-		items = append(items, hostRow{host: h, hasCfg: ok})
+		items = append(items, hostRow{host: h, badges: hostBadgesFor(opts, h)})
 	}
 
 	delegate := hostDelegate{}
@@ -125,9 +124,7 @@ func newHostsModel(opts Options) *hostsModel {
 		search:   search,
 		focus:    focusList,
 	}
-	if !opts.Config.Defaults.LoadKnownHosts {
-		m.keymap.Reload.SetEnabled(false)
-	}
+	m.keymap.Reload.SetEnabled(hostReloadEnabled(opts.Config))
 	m.applyFilter("")
 	return m
 }
@@ -155,9 +152,7 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case knownHostsReloadMsg:
 		spinnerStop()
 		m.reloading = false
-		m.opts.Hosts = msg.res.Hosts
-		m.opts.SkippedLines = msg.res.SkippedLines
-		m.opts.LoadErrors = msg.errs
+		m.opts.SetLoadResult(msg.res, msg.errs)
 		m.allHosts = append([]string(nil), msg.res.Hosts...)
 		present := make(map[string]struct{}, len(m.allHosts))
 		for _, h := range m.allHosts {
@@ -170,6 +165,9 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.applyFilter(m.search.Value())
 		m.toast = toast{text: fmt.Sprintf("%d hosts loaded", len(m.allHosts)), level: toastInfo}
+		if len(msg.errs) > 0 {
+			m.toast = loadErrorsToast(msg.errs)
+		}
 		return m, nil
 
 	case tea.KeyMsg:
@@ -182,28 +180,37 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if m.cmdPrompt {
-			s := msg.String()
-			switch s {
-			case "esc":
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				return m, nil
-			case "enter":
-				cmd := strings.TrimSpace(m.cmdInput.Value())
-				m.cmdPrompt = false
-				m.cmdInput.Blur()
-				m.cmdInput.SetValue("")
-				if cmd == "" {
-					return m, nil
-				}
-				m.toast = toast{}
-				return m, m.handleConnectWithRemoteCommand(cmd)
-			default:
-				var cmdTea tea.Cmd
-				m.cmdInput, cmdTea = m.cmdInput.Update(msg)
+		if m.execPr != nil {
+			spec, done, cmdTea := m.execPr.update(msg)
+			if !done {
 				return m, cmdTea
 			}
+			m.execPr = nil
+			if spec == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			hosts := m.hostsToOpen()
+			return m, func() tea.Msg {
+				return openExecMsg{groupIndex: -1, hosts: hosts, spec: *spec, returnTo: screenHosts}
+			}
+		}
+
+		if m.cmdPr != nil {
+			choice, done, cmd := m.cmdPr.update(msg)
+			if !done {
+				return m, cmd
+			}
+			m.cmdPr = nil
+			if choice == nil {
+				return m, nil
+			}
+			m.toast = toast{}
+			var remember tea.Cmd
+			if choice.adhoc {
+				remember = rememberCommand(&m.opts, choice.command)
+			}
+			return m, tea.Batch(remember, m.handleConnectWithRemoteCommand(choice.command))
 		}
 
 		if m.confirmQuit {
@@ -221,6 +228,13 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if m.explain != nil {
+			if msg.String() == "esc" || key.Matches(msg, m.keymap.Explain) || key.Matches(msg, m.keymap.Quit) {
+				m.explain = nil
+			}
+			return m, nil
+		}
+
 		if m.confirmConnect {
 			s := msg.String()
 			switch s {
@@ -307,15 +321,15 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if key.Matches(msg, m.keymap.Reload) && m.focus != focusSearch {
-			if !m.opts.Config.Defaults.LoadKnownHosts {
-				m.toast = toast{text: "known_hosts disabled", level: toastWarn}
+			if !hostReloadEnabled(m.opts.Config) {
+				m.toast = toast{text: "no host sources enabled", level: toastWarn}
 				return m, nil
 			}
 			m.toast = toast{text: "reloading", level: toastInfo}
 			m.reloading = true
 			spinnerStart()
 			return m, tea.Batch(
-				reloadKnownHostsCmd(m.opts.KnownHosts),
+				reloadKnownHostsCmd(m.opts),
 				tea.Tick(spinnerTickInterval, func(time.Time) tea.Msg { return spinnerTickMsg{} }),
 			)
 		}
@@ -336,22 +350,8 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.ConnectCmd) && m.focus == focusList {
-			in := textinput.New()
-			in.CharLimit = 512
-			in.Prompt = "cmd: "
-			in.Placeholder = "run on remote, keep session open"
-			mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-			innerW, _ := frameInnerSize(mw, mh)
-			avail := innerW - len(in.Prompt)
-			if avail < 1 {
-				avail = 1
-			}
-			in.Width = min(70, avail)
-			in.Focus()
-			configureSearch(&in)
-			setSearchFocused(&in, true)
-			m.cmdInput = in
-			m.cmdPrompt = true
+			m.cmdPr = newCommandPrompt(m.width, m.height, "Hosts", "Connect and run a remote command (keeps session open).",
+				offeredSnippets(m.opts, nil, m.hostsToOpen()), m.opts.CommandHistory)
 			return m, nil
 		}
 		if key.Matches(msg, m.keymap.Connect) {
@@ -378,6 +378,19 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.toast = toast{}
 			return m, m.handleConnectSame()
 		}
+		if key.Matches(msg, m.keymap.Transfer) && m.focus == focusList {
+			n := len(m.hostsToOpen())
+			if n == 0 {
+				m.toast = toast{text: "no host selected", level: toastWarn}
+				return m, nil
+			}
+			m.execPr = newTransferPrompt(m.width, m.height, "Hosts", n)
+			return m, nil
+		}
+		if key.Matches(msg, m.keymap.SFTP) && m.focus == focusList {
+			m.toast = toast{}
+			return m, m.openSFTP()
+		}
 		if key.Matches(msg, m.keymap.AddHosts) && m.focus == focusList {
 			hostsToAdd := m.selectedHosts()
 			if len(hostsToAdd) == 0 {
@@ -395,6 +408,15 @@ func (m *hostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if key.Matches(msg, m.keymap.CustomHost) && m.focus == focusList {
 			return m, func() tea.Msg { return openCustomHostMsg{returnTo: screenHosts, groupIndex: -1} }
 		}
+		if key.Matches(msg, m.keymap.Explain) && m.focus == focusList {
+			row, ok := m.list.SelectedItem().(hostRow)
+			if !ok || strings.TrimSpace(row.host) == "" {
+				m.toast = toast{text: "no host selected", level: toastWarn}
+				return m, nil
+			}
+			m.explain = newExplainView(m.opts, nil, row.host)
+			return m, nil
+		}
 		if key.Matches(msg, m.keymap.HostConfig) && m.focus == focusList {
 			row, ok := m.list.SelectedItem().(hostRow)
 			if !ok || strings.TrimSpace(row.host) == "" {
@@ -482,9 +504,9 @@ func (m *hostsModel) applyFilter(query string) {
 func (m *hostsModel) setListItems(hosts []string) {
 	items := make([]list.Item, 0, len(hosts))
 	for _, h := range hosts {
-		_, ok := hostConfigFor(m.opts.Inventory, h)
-		hidden := isHostHidden(m.opts.Inventory, h)
-		items = append(items, hostRow{host: h, selected: m.selected[h], hasCfg: ok, hidden: hidden})
+		b := hostBadgesFor(m.opts, h)
+		b.hidden = isHostHidden(m.opts.Inventory, h)
+		items = append(items, hostRow{host: h, selected: m.selected[h], badges: b})
 	}
 	m.list.SetItems(items)
 	if len(items) > 0 {
@@ -515,8 +537,9 @@ func (m *hostsModel) refreshVisibleBadges() {
 		if !ok {
 			continue
 		}
-		_, ok = hostConfigFor(m.opts.Inventory, row.host)
-		row.hasCfg = ok
+		hidden := row.badges.hidden
+		row.badges = hostBadgesFor(m.opts, row.host)
+		row.badges.hidden = hidden
 		items[i] = row
 	}
 	m.list.SetItems(items)
@@ -561,6 +584,34 @@ func (m *hostsModel) toggleCurrentSelection() {
 	m.refreshVisibleSelection()
 }
 
+// selectOnly clears the search and makes hosts the selection. Hosts that
+// are not in the list are reported in the toast.
+func (m *hostsModel) selectOnly(hosts []string) {
+	present := make(map[string]bool, len(m.allHosts))
+	for _, h := range m.allHosts {
+		present[h] = true
+	}
+	m.selected = make(map[string]bool)
+	missing := 0
+	for _, h := range hosts {
+		if present[h] {
+			m.selected[h] = true
+		} else {
+			missing++
+		}
+	}
+	m.search.SetValue("")
+	m.prevSearch = ""
+	m.applyFilter("")
+	m.focus = focusList
+	m.search.Blur()
+	setSearchBarFocused(&m.search, false)
+	m.toast = toast{text: fmt.Sprintf("%d hosts selected", len(m.selected)), level: toastInfo}
+	if missing > 0 {
+		m.toast = toast{text: fmt.Sprintf("%d hosts selected, %d not in the host list", len(m.selected), missing), level: toastWarn}
+	}
+}
+
 func (m *hostsModel) selectedHosts() []string {
 	if len(m.selected) == 0 {
 		return nil
@@ -585,21 +636,33 @@ func (m *hostsModel) hostsToOpen() []string {
 	return nil
 }
 
-func (m *hostsModel) buildSSHCmds(hosts []string, modifySettings func(*sshcmd.Settings)) [][]string {
-	base := sshcmd.FromDefaults(m.opts.Config.Defaults)
-	cmds := make([][]string, 0, len(hosts))
-	for _, h := range hosts {
-		s := base
-		if hc, ok := hostConfigFor(m.opts.Inventory, h); ok {
-			s = sshcmd.ApplyHost(s, hc)
-		}
-		if modifySettings != nil {
-			modifySettings(&s)
-		}
-		cmd, _ := sshcmd.BuildCommand(h, s)
-		cmds = append(cmds, cmd)
+// planConnect expands the connect of hosts, reporting template and command
+// errors in a toast.
+func (m *hostsModel) planConnect(hosts []string, modifySettings func(*sshcmd.Settings)) (connectPlan, bool) {
+	plan, err := planConnect(m.opts, nil, hosts, modifySettings)
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return connectPlan{}, false
 	}
-	return cmds
+	return plan, true
+}
+
+// openSFTP opens an sftp session to the host under the cursor.
+func (m *hostsModel) openSFTP() tea.Cmd {
+	row, ok := m.list.SelectedItem().(hostRow)
+	if !ok || row.host == "" {
+		m.toast = toast{text: "no host selected", level: toastWarn}
+		return nil
+	}
+	res, cmd := sftpConnect(m.opts, row.host, resolveSSH(m.opts, nil, row.host), resolveWindow(m.opts, nil))
+	if !res.toast.empty() {
+		m.toast = res.toast
+	}
+	if res.quit {
+		m.execCmd = res.execCmd
+		return tea.Quit
+	}
+	return cmd
 }
 
 func connectThreshold(d config.Defaults) int {
@@ -616,13 +679,16 @@ func (m *hostsModel) handleConnect() tea.Cmd {
 		return nil
 	}
 
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
+		return nil
+	}
 	doConnect := func() tea.Cmd {
 		defaults := m.opts.Config.Defaults
-		inTmux := tmx.InTmux()
-		mode := tmx.ResolveOpenMode(defaults.Tmux, defaults.OpenMode, inTmux)
-		sshCmds := m.buildSSHCmds(hosts, nil)
+		inMux := insideMux(defaults)
+		mode := tmx.ResolveOpenMode(defaults.Tmux, defaults.OpenMode, inMux, len(plan.cmds))
 
-		res, cmd := dispatchConnect(hosts, sshCmds, defaults, nil, mode, inTmux)
+		res, cmd := dispatchConnect(plan, defaults, nil, mode, inMux)
 		if !res.toast.empty() {
 			m.toast = res.toast
 		}
@@ -656,16 +722,19 @@ func (m *hostsModel) handleConnectWithRemoteCommand(remoteCmd string) tea.Cmd {
 		return nil
 	}
 
+	plan, ok := m.planConnect(hosts, func(s *sshcmd.Settings) {
+		s.ForceTTY = true
+		s.RemoteCommand = sshcmd.KeepSessionOpen(remoteCmd)
+	})
+	if !ok {
+		return nil
+	}
 	doConnect := func() tea.Cmd {
 		defaults := m.opts.Config.Defaults
-		inTmux := tmx.InTmux()
-		mode := tmx.ResolveOpenMode(defaults.Tmux, defaults.OpenMode, inTmux)
-		sshCmds := m.buildSSHCmds(hosts, func(s *sshcmd.Settings) {
-			s.ExtraArgs = ensureSSHForceTTY(s.ExtraArgs)
-			s.RemoteCommand = keepSessionOpenRemoteCmd(remoteCmd)
-		})
-
-		res, cmd := dispatchConnect(hosts, sshCmds, defaults, nil, mode, inTmux)
+		inMux := insideMux(defaults)
+		mode := tmx.ResolveOpenMode(defaults.Tmux, defaults.OpenMode, inMux, len(plan.cmds))
+
+		res, cmd := dispatchConnect(plan, defaults, nil, mode, inMux)
 		if !res.toast.empty() {
 			m.toast = res.toast
 		}
@@ -696,8 +765,11 @@ func (m *hostsModel) handleConnectSame() tea.Cmd {
 		m.toast = toast{text: "select single host for same-window connect", level: toastWarn}
 		return nil
 	}
-	sshCmds := m.buildSSHCmds(hosts, nil)
-	m.execCmd = sshCmds[0]
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
+		return nil
+	}
+	m.execCmd = plan.cmds[0]
 	return tea.Quit
 }
 
@@ -707,30 +779,21 @@ func (m *hostsModel) openOneWindow() tea.Cmd {
 		m.toast = toast{text: "no host selected", level: toastWarn}
 		return nil
 	}
-	if !tmx.InTmux() {
-		m.toast = toast{text: "requires an active tmux session", level: toastWarn}
+	plan, ok := m.planConnect(hosts, nil)
+	if !ok {
 		return nil
 	}
-
 	doConnect := func() tea.Cmd {
-		sshCmds := m.buildSSHCmds(hosts, nil)
-		defaults := m.opts.Config.Defaults
-		return func() tea.Msg {
-			ps := resolvePaneSettings(defaults, nil, len(sshCmds))
-			err := tmuxOpenOneWindow(sshCmds, tmuxOneWindowOpts{
-				WindowName:       windowName(hosts[0]),
-				PaneTitles:       hosts,
-				SplitFlag:        ps.SplitFlag,
-				Layout:           ps.Layout,
-				SyncPanes:        ps.SyncPanes,
-				PaneBorderFormat: ps.BorderFormat,
-				PaneBorderStatus: ps.BorderStatus,
-			})
-			if err != nil {
-				return toastMsg(toast{text: err.Error(), level: toastErr})
-			}
-			return toastMsg(toast{text: fmt.Sprintf("opened %d in one window", len(sshCmds)), level: toastInfo})
+		// One window, whatever the open mode: tmux-pane.
+		res, cmd := dispatchConnect(plan, m.opts.Config.Defaults, nil, tmx.OpenPane, insideMux(m.opts.Config.Defaults))
+		if !res.toast.empty() {
+			m.toast = res.toast
+		}
+		if res.quit {
+			m.execCmd = res.execCmd
+			return tea.Quit
 		}
+		return cmd
 	}
 
 	if len(hosts) > connectThreshold(m.opts.Config.Defaults) {
@@ -743,10 +806,6 @@ func (m *hostsModel) openOneWindow() tea.Cmd {
 	return doConnect()
 }
 
-func windowName(host string) string {
-	return tmx.WindowName(host)
-}
-
 func (m *hostsModel) helpKeys() helpMap {
 	return helpMap{
 		short: []key.Binding{
@@ -758,9 +817,12 @@ func (m *hostsModel) helpKeys() helpMap {
 			m.keymap.ConnectSame,
 			m.keymap.ConnectCmd,
 			m.keymap.OneWindow,
+			m.keymap.Transfer,
+			m.keymap.SFTP,
 			m.keymap.AddHosts,
 			m.keymap.CustomHost,
 			m.keymap.HostConfig,
+			m.keymap.Explain,
 			m.keymap.Copy,
 			m.keymap.Settings,
 			m.keymap.Reload,
@@ -786,9 +848,12 @@ func (m *hostsModel) helpKeys() helpMap {
 			m.keymap.ConnectSame,
 			m.keymap.ConnectCmd,
 			m.keymap.OneWindow,
+			m.keymap.Transfer,
+			m.keymap.SFTP,
 			m.keymap.AddHosts,
 			m.keymap.CustomHost,
 			m.keymap.HostConfig,
+			m.keymap.Explain,
 			m.keymap.Copy,
 			m.keymap.Settings,
 			m.keymap.Reload,
@@ -798,9 +863,9 @@ func (m *hostsModel) helpKeys() helpMap {
 	}
 }
 
-func reloadKnownHostsCmd(paths []string) tea.Cmd {
+func reloadKnownHostsCmd(opts Options) tea.Cmd {
 	return func() tea.Msg {
-		res, errs := hosts.LoadKnownHosts(paths)
+		res, errs := loadHosts(opts, true)
 		return knownHostsReloadMsg{res: res, errs: errs}
 	}
 }
@@ -0,0 +1,424 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sessionsRefreshInterval is how often the Sessions tab re-lists the tmux
+// panes while it is shown.
+const sessionsRefreshInterval = 2 * time.Second
+
+type sessionsTickMsg struct{}
+
+type sessionRow struct {
+	pane tmx.Pane
+}
+
+func (r sessionRow) Title() string       { return r.pane.Host }
+func (r sessionRow) Description() string { return "" }
+func (r sessionRow) FilterValue() string { return r.pane.Host }
+
+type sessionsDelegate struct{}
+
+func (d sessionsDelegate) Height() int                             { return 1 }
+func (d sessionsDelegate) Spacing() int                            { return 0 }
+func (d sessionsDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d sessionsDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	row, ok := item.(sessionRow)
+	if !ok {
+		fmt.Fprint(w, item.FilterValue())
+		return
+	}
+	fmt.Fprint(w, renderSessionRow(m.Width(), index == m.Index(), row))
+}
+
+// renderSessionRow renders "● host  group  window" with the pane target and
+// state on the right. Active rows are plain text so the highlight stays
+// uniform.
+func renderSessionRow(width int, active bool, row sessionRow) string {
+	p := row.pane
+	cur := " "
+	if active {
+		cur = "▸"
+	}
+	dot := "●"
+	status := p.Target()
+	if p.Dead {
+		status += fmt.Sprintf("  exited %d", p.DeadStatus)
+	}
+	if !active {
+		if p.Dead {
+			dot = statusErr.Render(dot)
+		} else {
+			dot = statusOK.Render(dot)
+		}
+	}
+
+	detail := "window " + p.WindowName
+	if p.Group != "" {
+		detail = p.Group + "  " + detail
+	}
+	left := cur + " " + dot + " " + p.Host
+	if width > 0 {
+		statusW := min(lipgloss.Width(status), max(0, width/2))
+		status = truncateTail(status, statusW)
+		room := width - lipgloss.Width(left) - statusW - 2
+		if room >= 6 {
+			d := "  " + truncateTail(detail, room-2)
+			if !active {
+				d = dim.Render(d)
+			}
+			left += d
+		} else {
+			left = truncateTail(left, max(0, width-statusW-2))
+		}
+		if !active && p.Dead {
+			status = statusErr.Render(status)
+		} else if !active {
+			status = dim.Render(status)
+		}
+		pad := max(2, width-lipgloss.Width(left)-lipgloss.Width(status))
+		line := left + strings.Repeat(" ", pad) + status
+		if active {
+			return rowActiveStyle.Render(line)
+		}
+		return line
+	}
+	line := left + "  " + detail + "  " + status
+	if active {
+		return rowActiveStyle.Render(line)
+	}
+	return line
+}
+
+// sessionsModel is the Sessions tab: the live tmux panes opened by ssh-tui,
+// found by their @ssh_tui_host option.
+type sessionsModel struct {
+	opts Options
+
+	width  int
+	height int
+
+	list list.Model
+
+	keymap   keyMap
+	help     help.Model
+	showHelp bool
+	helpVP   viewport.Model
+	toast    toast
+
+	// confirmKill is set while asking before killing the pane (or its
+	// window, killWindow) under the cursor.
+	confirmKill bool
+	killWindow  bool
+	killPane    tmx.Pane
+
+	confirmQuit bool
+	quitting    bool
+	execCmd     []string
+}
+
+func newSessionsModel(opts Options) *sessionsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.SetDelegate(sessionsDelegate{})
+	l.Title = "Sessions"
+	configureList(&l)
+
+	return &sessionsModel{
+		opts:   opts,
+		list:   l,
+		keymap: defaultKeyMap(),
+		help:   help.New(),
+	}
+}
+
+// refresh re-lists the tmux panes, keeping the cursor on the same pane.
+func (m *sessionsModel) refresh() {
+	panes, err := tmx.ListPanes()
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return
+	}
+	prev := ""
+	if row, ok := m.list.SelectedItem().(sessionRow); ok {
+		prev = row.pane.PaneID
+	}
+	items := make([]list.Item, 0, len(panes))
+	idx := m.list.Index()
+	for i, p := range panes {
+		if p.PaneID == prev {
+			idx = i
+		}
+		items = append(items, sessionRow{pane: p})
+	}
+	m.list.SetItems(items)
+	if idx >= len(items) {
+		idx = len(items) - 1
+	}
+	if idx >= 0 {
+		m.list.Select(idx)
+	}
+}
+
+// tick schedules the next refresh.
+func (m *sessionsModel) tick() tea.Cmd {
+	return tea.Tick(sessionsRefreshInterval, func(time.Time) tea.Msg { return sessionsTickMsg{} })
+}
+
+func (m *sessionsModel) Init() tea.Cmd { return nil }
+
+func (m *sessionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		innerW := max(0, msg.Width-2)
+		innerH := max(0, msg.Height-2)
+		// tabs + sep + header + sep + footer sep + footer
+		m.list.SetSize(innerW, max(1, innerH-6))
+		return m, nil
+	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, m.keymap.Help) || msg.String() == "esc" {
+				m.showHelp = false
+				return m, nil
+			}
+			updateHelpViewport(&m.helpVP, msg)
+			return m, nil
+		}
+		if m.confirmQuit {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.quitting = true
+				return m, tea.Quit
+			case "n", "N", "esc":
+				m.confirmQuit = false
+				m.toast = toast{}
+			}
+			return m, nil
+		}
+		if m.confirmKill {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.confirmKill = false
+				m.kill(m.killPane, m.killWindow)
+			case "n", "N", "esc":
+				m.confirmKill = false
+				m.toast = toast{}
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.Quit):
+			if !m.opts.Config.Defaults.ConfirmQuit {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.confirmQuit = true
+			m.toast = toast{text: "quit? (y/n)", level: toastWarn}
+			return m, nil
+		case key.Matches(msg, m.keymap.Help):
+			m.showHelp = true
+			if m.width > 0 && m.height > 0 {
+				m.helpVP = initHelpViewport(m.width, m.height, "Sessions", m.help, m.helpKeys())
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.Settings):
+			return m, func() tea.Msg { return openDefaultsFormMsg{returnTo: screenSessions} }
+		case key.Matches(msg, m.keymap.SwitchTab):
+			return m, func() tea.Msg { return switchScreenMsg{to: screenHosts} }
+		case key.Matches(msg, m.keymap.Reload):
+			m.refresh()
+			return m, nil
+		case key.Matches(msg, m.keymap.PaneJump):
+			row, ok := m.list.SelectedItem().(sessionRow)
+			if !ok {
+				return m, nil
+			}
+			return m, m.jump(row.pane)
+		case key.Matches(msg, m.keymap.PaneKill), key.Matches(msg, m.keymap.WindowKill):
+			row, ok := m.list.SelectedItem().(sessionRow)
+			if !ok {
+				return m, nil
+			}
+			m.confirmKill = true
+			m.killWindow = key.Matches(msg, m.keymap.WindowKill)
+			m.killPane = row.pane
+			what := "pane " + row.pane.Target()
+			if m.killWindow {
+				what = fmt.Sprintf("window %s:%d (%s)", row.pane.Session, row.pane.WindowIndex, row.pane.WindowName)
+			}
+			m.toast = toast{text: "kill " + what + "? (y/n)", level: toastWarn}
+			return m, nil
+		case key.Matches(msg, m.keymap.PaneRespawn):
+			row, ok := m.list.SelectedItem().(sessionRow)
+			if !ok {
+				return m, nil
+			}
+			if !row.pane.Dead {
+				m.toast = toast{text: row.pane.Host + " is still running", level: toastWarn}
+				return m, nil
+			}
+			if err := tmx.RespawnPane(row.pane); err != nil {
+				m.toast = toast{text: err.Error(), level: toastErr}
+				return m, nil
+			}
+			m.toast = toast{text: "respawned " + row.pane.Host, level: toastOK}
+			m.refresh()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// jump selects p in its window and shows it: inside tmux by switching the
+// client, outside by quitting and attaching to its session.
+func (m *sessionsModel) jump(p tmx.Pane) tea.Cmd {
+	if err := tmx.SelectPane(p); err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		m.refresh()
+		return nil
+	}
+	if !tmx.InTmux() {
+		m.execCmd = tmx.AttachCmd(p)
+		return tea.Quit
+	}
+	if err := tmx.SwitchClient(p); err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return nil
+	}
+	if m.opts.Popup {
+		m.quitting = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *sessionsModel) kill(p tmx.Pane, window bool) {
+	var err error
+	if window {
+		err = tmx.KillWindow(p)
+	} else {
+		err = tmx.KillPane(p)
+	}
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+	} else {
+		m.toast = toast{text: "killed " + p.Host, level: toastOK}
+	}
+	m.refresh()
+}
+
+func (m *sessionsModel) View() string {
+	if m.showHelp {
+		return renderHelpModalWithVP(m.width, m.height, "Sessions", m.help, m.helpKeys(), &m.helpVP)
+	}
+	if m.confirmQuit {
+		return renderQuitConfirm(m.width, m.height)
+	}
+
+	open := make(map[string]bool)
+	dead := 0
+	for _, it := range m.list.Items() {
+		if row, ok := it.(sessionRow); ok {
+			open[row.pane.Host] = true
+			if row.pane.Dead {
+				dead++
+			}
+		}
+	}
+	leftText := fmt.Sprintf("%d hosts open", len(open))
+	if dead > 0 {
+		leftText += fmt.Sprintf(", %d exited", dead)
+	}
+	left := dim.Render(leftText)
+	right := ""
+	if !m.toast.empty() {
+		right = renderToast(m.toast)
+	} else {
+		right = statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d panes", len(m.list.Items())))
+	}
+
+	// R only does something for panes kept dead by remain-on-exit.
+	respawn := ""
+	if m.anyDead() {
+		respawn = "  R respawn"
+	}
+	var footer string
+	if m.width < 60 {
+		footer = styledFooter("↵ jump  x kill" + respawn + "  ? help")
+	} else {
+		footer = styledFooter("↵ jump  x kill pane  X kill window" + respawn + "  r refresh  ·  g hosts  ? help")
+	}
+
+	content := m.list.View()
+	if len(m.list.Items()) == 0 {
+		innerW := max(0, m.width-2)
+		contentH := max(0, m.height-2-6)
+		msg := dim.Render("·  ·  ·") + "\n\n" + dim.Render("No open sessions.") + "\n" + dim.Render("Windows and panes opened through tmux show up here")
+		content = lipgloss.Place(innerW, contentH, lipgloss.Center, lipgloss.Center, msg)
+	}
+	return renderMainTabBoxWithFooter(m.width, m.height, 3, left, right, content, footer)
+}
+
+func (m *sessionsModel) helpKeys() helpMap {
+	return helpMap{
+		short: []key.Binding{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			m.keymap.PaneJump,
+			m.keymap.PaneKill,
+			m.keymap.WindowKill,
+			m.keymap.PaneRespawn,
+			m.keymap.Reload,
+			m.keymap.SwitchTab,
+			m.keymap.Settings,
+			m.keymap.Help,
+			m.keymap.Quit,
+		},
+		full: [][]key.Binding{{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			m.list.KeyMap.PrevPage,
+			m.list.KeyMap.NextPage,
+		}, {
+			m.keymap.PaneJump,
+			m.keymap.PaneKill,
+			m.keymap.WindowKill,
+			m.keymap.PaneRespawn,
+			m.keymap.Reload,
+		}, {
+			m.keymap.SwitchTab,
+			m.keymap.Settings,
+			m.keymap.Help,
+			m.keymap.Quit,
+		}},
+	}
+}
+
+// anyDead reports whether a listed pane has exited.
+func (m *sessionsModel) anyDead() bool {
+	for _, it := range m.list.Items() {
+		if row, ok := it.(sessionRow); ok && row.pane.Dead {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/tunnel"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tunnelsRefreshInterval is how often the Tunnels tab re-checks the tunnel
+// processes while it is shown.
+const tunnelsRefreshInterval = 2 * time.Second
+
+type tunnelsTickMsg struct{}
+
+// tunnelDoneMsg reports a finished start or stop of a tunnel.
+type tunnelDoneMsg struct {
+	name    string
+	started bool
+	pid     int
+	err     error
+}
+
+type tunnelRow struct {
+	spec tunnel.Spec
+	info tunnel.Info
+	busy bool // start or stop in flight
+}
+
+func (r tunnelRow) Title() string       { return r.spec.Name }
+func (r tunnelRow) Description() string { return "" }
+func (r tunnelRow) FilterValue() string { return r.spec.Name }
+
+type tunnelsDelegate struct{}
+
+func (d tunnelsDelegate) Height() int                             { return 1 }
+func (d tunnelsDelegate) Spacing() int                            { return 0 }
+func (d tunnelsDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d tunnelsDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	row, ok := item.(tunnelRow)
+	if !ok {
+		fmt.Fprint(w, item.FilterValue())
+		return
+	}
+	fmt.Fprint(w, renderTunnelRow(m.Width(), index == m.Index(), row))
+}
+
+// renderTunnelRow renders "● name  forward  via host" with the status on the
+// right. Active rows are plain text so the highlight stays uniform.
+func renderTunnelRow(width int, active bool, row tunnelRow) string {
+	cur := " "
+	if active {
+		cur = "▸"
+	}
+	dot := "○"
+	status := row.info.Status.String()
+	switch {
+	case row.busy:
+		dot = "◌"
+		status = "…"
+	case row.info.Status == tunnel.Running:
+		dot = "●"
+		status += fmt.Sprintf(" pid %d", row.info.State.PID)
+	case row.info.Status == tunnel.Failed:
+		dot = "●"
+		status += ": " + row.info.Err
+	}
+	if !active {
+		switch {
+		case row.busy:
+			dot = dim.Render(dot)
+		case row.info.Status == tunnel.Running:
+			dot = statusOK.Render(dot)
+		case row.info.Status == tunnel.Failed:
+			dot = statusErr.Render(dot)
+		default:
+			dot = dim.Render(dot)
+		}
+	}
+
+	via := "via " + row.spec.Host
+	if row.spec.Group != "" {
+		via += " (" + row.spec.Group + ")"
+	}
+	left := cur + " " + dot + " " + row.spec.Name + "  " + row.spec.Describe()
+	if width > 0 {
+		statusW := min(lipgloss.Width(status), max(0, width/2))
+		status = truncateTail(status, statusW)
+		room := width - lipgloss.Width(left) - statusW - 2
+		if room >= 6 {
+			v := "  " + truncateTail(via, room-2)
+			if !active {
+				v = dim.Render(v)
+			}
+			left += v
+		} else {
+			left = truncateTail(left, max(0, width-statusW-2))
+		}
+		if !active && row.info.Status == tunnel.Failed && !row.busy {
+			status = statusErr.Render(status)
+		} else if !active {
+			status = dim.Render(status)
+		}
+		pad := max(2, width-lipgloss.Width(left)-lipgloss.Width(status))
+		line := left + strings.Repeat(" ", pad) + status
+		if active {
+			return rowActiveStyle.Render(line)
+		}
+		return line
+	}
+	line := left + "  " + via + "  " + status
+	if active {
+		return rowActiveStyle.Render(line)
+	}
+	return line
+}
+
+// tunnelsModel is the Tunnels tab: the [[hosts.tunnels]] and
+// [[groups.tunnels]] of hosts.toml and the state of their ssh processes.
+type tunnelsModel struct {
+	opts Options
+	dir  string // tunnel state dir (pid files)
+
+	width  int
+	height int
+
+	list list.Model
+	busy map[string]bool
+
+	keymap   keyMap
+	help     help.Model
+	showHelp bool
+	helpVP   viewport.Model
+	toast    toast
+
+	confirmQuit bool
+	quitting    bool
+}
+
+func newTunnelsModel(opts Options) *tunnelsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.SetDelegate(tunnelsDelegate{})
+	l.Title = "Tunnels"
+	configureList(&l)
+
+	dir, _ := config.DefaultTunnelStateDir()
+	m := &tunnelsModel{
+		opts:   opts,
+		dir:    dir,
+		list:   l,
+		busy:   make(map[string]bool),
+		keymap: defaultKeyMap(),
+		help:   help.New(),
+	}
+	m.refresh()
+	return m
+}
+
+// Refresh reloads the tunnel list after hosts.toml changed.
+func (m *tunnelsModel) Refresh(inv config.Inventory) {
+	m.opts.Inventory = inv
+	m.refresh()
+}
+
+// refresh rebuilds the rows from the inventory and the state dir, keeping
+// the cursor on the same row.
+func (m *tunnelsModel) refresh() {
+	specs := tunnel.List(m.opts.Inventory, m.opts.loadResult())
+	items := make([]list.Item, 0, len(specs))
+	for _, s := range specs {
+		row := tunnelRow{spec: s, busy: m.busy[s.Name]}
+		if m.dir != "" {
+			row.info = tunnel.Check(m.dir, s.Name)
+		}
+		items = append(items, row)
+	}
+	idx := m.list.Index()
+	m.list.SetItems(items)
+	if idx >= len(items) {
+		idx = len(items) - 1
+	}
+	if idx >= 0 {
+		m.list.Select(idx)
+	}
+}
+
+// tick schedules the next status refresh.
+func (m *tunnelsModel) tick() tea.Cmd {
+	return tea.Tick(tunnelsRefreshInterval, func(time.Time) tea.Msg { return tunnelsTickMsg{} })
+}
+
+func (m *tunnelsModel) Init() tea.Cmd { return nil }
+
+func (m *tunnelsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		innerW := max(0, msg.Width-2)
+		innerH := max(0, msg.Height-2)
+		// tabs + sep + header + sep + footer sep + footer
+		m.list.SetSize(innerW, max(1, innerH-6))
+		return m, nil
+	case tunnelDoneMsg:
+		delete(m.busy, msg.name)
+		switch {
+		case msg.err != nil:
+			m.toast = toast{text: msg.err.Error(), level: toastErr}
+		case msg.started:
+			m.toast = toast{text: fmt.Sprintf("started %s (pid %d)", msg.name, msg.pid), level: toastOK}
+		default:
+			m.toast = toast{text: "stopped " + msg.name, level: toastOK}
+		}
+		m.refresh()
+		return m, nil
+	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, m.keymap.Help) || msg.String() == "esc" {
+				m.showHelp = false
+				return m, nil
+			}
+			updateHelpViewport(&m.helpVP, msg)
+			return m, nil
+		}
+		if m.confirmQuit {
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.quitting = true
+				return m, tea.Quit
+			case "n", "N", "esc":
+				m.confirmQuit = false
+				m.toast = toast{}
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.keymap.Quit):
+			if !m.opts.Config.Defaults.ConfirmQuit {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.confirmQuit = true
+			m.toast = toast{text: "quit? (y/n)", level: toastWarn}
+			return m, nil
+		case key.Matches(msg, m.keymap.Help):
+			m.showHelp = true
+			if m.width > 0 && m.height > 0 {
+				m.helpVP = initHelpViewport(m.width, m.height, "Tunnels", m.help, m.helpKeys())
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.Settings):
+			return m, func() tea.Msg { return openDefaultsFormMsg{returnTo: screenTunnels} }
+		case key.Matches(msg, m.keymap.SwitchTab):
+			return m, func() tea.Msg { return switchScreenMsg{to: screenSessions} }
+		case key.Matches(msg, m.keymap.Reload):
+			m.refresh()
+			return m, nil
+		case key.Matches(msg, m.keymap.TunnelStart):
+			row, ok := m.list.SelectedItem().(tunnelRow)
+			if !ok || row.busy {
+				return m, nil
+			}
+			if row.info.Status == tunnel.Running {
+				return m, m.stopCmd(row.spec)
+			}
+			return m, m.startCmd(row.spec)
+		case key.Matches(msg, m.keymap.TunnelStop):
+			row, ok := m.list.SelectedItem().(tunnelRow)
+			if !ok || row.busy {
+				return m, nil
+			}
+			if row.info.Status == tunnel.Stopped {
+				m.toast = toast{text: row.spec.Name + " is not running", level: toastWarn}
+				return m, nil
+			}
+			return m, m.stopCmd(row.spec)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *tunnelsModel) startCmd(spec tunnel.Spec) tea.Cmd {
+	if m.dir == "" {
+		m.toast = toast{text: "tunnel state dir not found", level: toastErr}
+		return nil
+	}
+	argv, err := tunnel.Command(spec, resolveSSH(m.opts, spec.Chain(m.opts.Inventory), spec.Host))
+	if err != nil {
+		m.toast = toast{text: err.Error(), level: toastErr}
+		return nil
+	}
+	m.busy[spec.Name] = true
+	m.refresh()
+	dir := m.dir
+	return func() tea.Msg {
+		st, err := tunnel.Start(dir, spec.Name, argv)
+		return tunnelDoneMsg{name: spec.Name, started: true, pid: st.PID, err: err}
+	}
+}
+
+func (m *tunnelsModel) stopCmd(spec tunnel.Spec) tea.Cmd {
+	m.busy[spec.Name] = true
+	m.refresh()
+	dir := m.dir
+	return func() tea.Msg {
+		return tunnelDoneMsg{name: spec.Name, err: tunnel.Stop(dir, spec.Name)}
+	}
+}
+
+func (m *tunnelsModel) View() string {
+	if m.showHelp {
+		return renderHelpModalWithVP(m.width, m.height, "Tunnels", m.help, m.helpKeys(), &m.helpVP)
+	}
+	if m.confirmQuit {
+		return renderQuitConfirm(m.width, m.height)
+	}
+
+	running := 0
+	for _, it := range m.list.Items() {
+		if row, ok := it.(tunnelRow); ok && row.info.Status == tunnel.Running {
+			running++
+		}
+	}
+	left := dim.Render(fmt.Sprintf("%d running", running))
+	right := ""
+	if !m.toast.empty() {
+		right = renderToast(m.toast)
+	} else {
+		right = statusDot(true, false) + "   " + dim.Render(fmt.Sprintf("%d tunnels", len(m.list.Items())))
+	}
+
+	var footer string
+	if m.width < 60 {
+		footer = styledFooter("↵ start/stop  x stop  ? help")
+	} else {
+		footer = styledFooter("↵ start/stop  x stop/clear  r refresh  ·  g sessions  ? help")
+	}
+
+	content := m.list.View()
+	if len(m.list.Items()) == 0 {
+		innerW := max(0, m.width-2)
+		contentH := max(0, m.height-2-6)
+		msg := dim.Render("·  ·  ·") + "\n\n" + dim.Render("No tunnels yet.") + "\n" + dim.Render("Add [[hosts.tunnels]] or [[groups.tunnels]] to hosts.toml")
+		content = lipgloss.Place(innerW, contentH, lipgloss.Center, lipgloss.Center, msg)
+	}
+	return renderMainTabBoxWithFooter(m.width, m.height, 2, left, right, content, footer)
+}
+
+func (m *tunnelsModel) helpKeys() helpMap {
+	return helpMap{
+		short: []key.Binding{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			m.keymap.TunnelStart,
+			m.keymap.TunnelStop,
+			m.keymap.Reload,
+			m.keymap.SwitchTab,
+			m.keymap.Settings,
+			m.keymap.Help,
+			m.keymap.Quit,
+		},
+		full: [][]key.Binding{{
+			m.list.KeyMap.CursorUp,
+			m.list.KeyMap.CursorDown,
+			m.list.KeyMap.PrevPage,
+			m.list.KeyMap.NextPage,
+		}, {
+			m.keymap.TunnelStart,
+			m.keymap.TunnelStop,
+			m.keymap.Reload,
+		}, {
+			m.keymap.SwitchTab,
+			m.keymap.Settings,
+			m.keymap.Help,
+			m.keymap.Quit,
+		}},
+	}
+}
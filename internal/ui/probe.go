@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/al-bashkir/ssh-tui/internal/config"
+	"github.com/al-bashkir/ssh-tui/internal/container"
+	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// probeTickInterval is how often the visible page is checked for hosts
+// without a fresh probe result.
+const probeTickInterval = time.Second
+
+type probeTickMsg struct{}
+
+// probeDoneMsg reports that a batch of probes finished.
+type probeDoneMsg struct{}
+
+func probeTick() tea.Cmd {
+	return tea.Tick(probeTickInterval, func(time.Time) tea.Msg { return probeTickMsg{} })
+}
+
+// setProbe fills the probe fields of b when probing is on. Containers get
+// no reachability dot.
+func (b *hostBadges) setProbe(opts Options, s sshcmd.Settings, host string) {
+	if opts.Probes == nil || container.IsTarget(host) {
+		return
+	}
+	b.probeAddr, _ = probe.Target(host, s, opts.SSHConfig)
+	r := probe.Result{}
+	if b.probeAddr != "" {
+		r = opts.Probes.Get(b.probeAddr)
+	}
+	b.probe = &r
+}
+
+// probeLatency is "12ms", "<1ms" or "1.2s".
+func probeLatency(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return "<1ms"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return d.Round(100 * time.Millisecond).String()
+	}
+}
+
+// probedRow is a list row carrying host badges.
+type probedRow interface {
+	list.Item
+	rowBadges() hostBadges
+	withBadges(hostBadges) list.Item
+}
+
+func (i hostRow) rowBadges() hostBadges                  { return i.badges }
+func (i hostRow) withBadges(b hostBadges) list.Item      { i.badges = b; return i }
+func (i groupHostRow) rowBadges() hostBadges             { return i.badges }
+func (i groupHostRow) withBadges(b hostBadges) list.Item { i.badges = b; return i }
+func (i pickerRow) rowBadges() hostBadges                { return i.badges }
+func (i pickerRow) withBadges(b hostBadges) list.Item    { i.badges = b; return i }
+
+// probePage refreshes the probe results of the rows on the visible page of
+// l from cache and returns their addresses. Only the page is touched so
+// that lists of thousands of hosts stay cheap.
+func probePage(l *list.Model, cache *probe.Cache) []string {
+	items := l.Items()
+	start, end := l.Paginator.GetSliceBounds(len(items))
+	var addrs []string
+	for i := start; i < end; i++ {
+		row, ok := items[i].(probedRow)
+		if !ok {
+			continue
+		}
+		b := row.rowBadges()
+		if b.probe == nil || b.probeAddr == "" {
+			continue
+		}
+		r := cache.Get(b.probeAddr)
+		if r != *b.probe {
+			b.probe = &r
+			l.SetItem(i, row.withBadges(b))
+		}
+		addrs = append(addrs, b.probeAddr)
+	}
+	return addrs
+}
+
+// probeVisible updates the rows of the list shown on the current screen
+// and starts probing the addresses without a fresh result.
+func (m *appModel) probeVisible() tea.Cmd {
+	cache := m.opts.Probes
+	if cache == nil {
+		return nil
+	}
+	var l *list.Model
+	switch {
+	case m.screen == screenHosts && m.hosts != nil:
+		l = &m.hosts.list
+	case m.screen == screenGroupHosts && m.gh != nil:
+		l = &m.gh.list
+	case m.screen == screenHostPicker && m.picker != nil:
+		l = &m.picker.list
+	default:
+		return nil
+	}
+	stale := cache.Claim(probePage(l, cache), time.Now())
+	if len(stale) == 0 {
+		return nil
+	}
+	timeout, _, err := config.ParseProbe(m.opts.Config.Defaults)
+	if err != nil {
+		timeout, _ = time.ParseDuration(config.DefaultProbeTimeout)
+	}
+	return func() tea.Msg {
+		probe.Run(context.Background(), cache, stale, probe.DefaultParallel, timeout)
+		return probeDoneMsg{}
+	}
+}
+
+// preflightDoneMsg carries the pre-flight check of a group connect.
+type preflightDoneMsg struct {
+	group     config.Group // Hosts holds the flattened members that were checked
+	report    probe.Report
+	oneWindow bool
+	remoteCmd string
+}
+
+// preflightCmd TCP-probes the members of g in the background; g.Hosts holds
+// the flattened members and chains the chains of the stored group.
+func preflightCmd(opts Options, g config.Group, chains hosts.Chains, oneWindow bool, remoteCmd string) tea.Cmd {
+	addrs := make([]string, len(g.Hosts))
+	for i, h := range g.Hosts {
+		addrs[i], _ = probe.Target(h, resolveSSH(opts, chains.For(g, h), h), opts.SSHConfig)
+	}
+	timeout, _, err := config.ParseProbe(opts.Config.Defaults)
+	if err != nil {
+		timeout, _ = time.ParseDuration(config.DefaultProbeTimeout)
+	}
+	return func() tea.Msg {
+		rep := probe.Preflight(context.Background(), g.Hosts, addrs, timeout)
+		return preflightDoneMsg{group: g, report: rep, oneWindow: oneWindow, remoteCmd: remoteCmd}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -59,7 +62,103 @@ func badgePlain(text string) string {
 	return " " + text + " "
 }
 
-func renderHostLikeRow(width int, active bool, selected bool, host string, hasCfg bool, hidden bool) string {
+// hostBadges carries the per-row flags rendered around a host name.
+type hostBadges struct {
+	hasCfg bool
+	hidden bool
+	hashed bool   // only known through a hashed known_hosts entry
+	kind   string // container.KindDocker or KindKube; empty for ssh hosts
+
+	sshConfig bool   // declared as a Host alias in ~/.ssh/config
+	source    string // name of the [[sources]] entry that listed the host
+
+	dynamic bool     // group member through match patterns or [[sources]]
+	from    string   // included group the member comes from (include_groups)
+	jump    []string // effective ProxyJump hops
+	backend string   // effective backend when it is not ssh
+
+	tags        []string // shown as #tag pills (at most maxTagPills)
+	description string   // dimmed text after the badges when there is room
+
+	probe     *probe.Result // reachability; nil when probing is off
+	probeAddr string        // address probed; empty behind jump hosts
+}
+
+// maxTagPills caps the tag pills per row; the rest collapse into "+N".
+const maxTagPills = 3
+
+// rowBadge is a pill rendered to the right of a row's name.
+type rowBadge struct {
+	text  string
+	style lipgloss.Style
+}
+
+func (b hostBadges) pills() []rowBadge {
+	var out []rowBadge
+	if b.probe != nil && b.probe.State == probe.Up {
+		out = append(out, rowBadge{text: probeLatency(b.probe.Latency), style: badgeCountStyle})
+	}
+	if b.kind != "" {
+		out = append(out, rowBadge{text: b.kind, style: badgeCfgStyle})
+	}
+	for i, t := range b.tags {
+		if i == maxTagPills {
+			out = append(out, rowBadge{text: fmt.Sprintf("+%d", len(b.tags)-maxTagPills), style: badgeTagStyle})
+			break
+		}
+		out = append(out, rowBadge{text: "#" + t, style: badgeTagStyle})
+	}
+	if b.backend != "" {
+		out = append(out, rowBadge{text: b.backend, style: badgeCountStyle})
+	}
+	if len(b.jump) > 0 {
+		_, host, _ := sshcmd.SplitJumpHop(b.jump[0])
+		text := "via " + host
+		if len(b.jump) > 1 {
+			text += fmt.Sprintf(" +%d", len(b.jump)-1)
+		}
+		out = append(out, rowBadge{text: text, style: badgeCountStyle})
+	}
+	if b.from != "" {
+		out = append(out, rowBadge{text: "from " + b.from, style: badgeCountStyle})
+	}
+	if b.dynamic {
+		out = append(out, rowBadge{text: "dyn", style: badgeCountStyle})
+	}
+	if b.source != "" {
+		out = append(out, rowBadge{text: b.source, style: badgeCountStyle})
+	}
+	if b.sshConfig {
+		out = append(out, rowBadge{text: "sshcfg", style: badgeCountStyle})
+	}
+	if b.hashed {
+		out = append(out, rowBadge{text: "hashed", style: badgeCountStyle})
+	}
+	if b.hasCfg {
+		out = append(out, rowBadge{text: "⚙", style: badgeCfgStyle})
+	}
+	return out
+}
+
+// renderBadges renders pills separated by a space. Active rows get plain text
+// of the same width so the row highlight stays uniform.
+func renderBadges(pills []rowBadge, active bool) (string, int) {
+	var b strings.Builder
+	for _, p := range pills {
+		styled := " " + p.style.Render(p.text)
+		if active {
+			// Same visual width as the styled badge (padding 0,1 = 1 space each side).
+			b.WriteString(strings.Repeat(" ", lipgloss.Width(styled)-lipgloss.Width(p.text)-1))
+			b.WriteString(p.text + " ")
+			continue
+		}
+		b.WriteString(styled)
+	}
+	out := b.String()
+	return out, lipgloss.Width(out)
+}
+
+func renderHostLikeRow(width int, active bool, selected bool, host string, badges hostBadges) string {
 	cur := " "
 	if active {
 		// Plain cursor — no inner ANSI so rowActiveStyle background fills uniformly.
@@ -80,21 +179,13 @@ func renderHostLikeRow(width int, active bool, selected bool, host string, hasCf
 	}
 
 	prefix := cur + " " + checked + " "
+	if badges.probe != nil {
+		prefix += probeDot(badges.probe.State, active) + " "
+	}
 
-	// Always reserve the same width for the cfg badge regardless of active
+	// Always reserve the same width for the badges regardless of active
 	// state so the host name column does not shift when the cursor moves.
-	suffix := ""
-	suffixW := 0
-	if hasCfg {
-		styledCfg := " " + badgeCfgStyle.Render("⚙")
-		suffixW = lipgloss.Width(styledCfg)
-		if active {
-			// Same visual width as the styled badge (padding 0,1 = 1 space each side).
-			suffix = "  ⚙ "
-		} else {
-			suffix = styledCfg
-		}
-	}
+	suffix, suffixW := renderBadges(badges.pills(), active)
 
 	// Compute host width budget.
 	hostAvail := 0
@@ -109,7 +200,7 @@ func renderHostLikeRow(width int, active bool, selected bool, host string, hasCf
 
 	// For hidden hosts, prepend ⊘ prefix to the display string.
 	displayHost := host
-	if hidden {
+	if badges.hidden {
 		displayHost = "⊘ " + host
 	}
 
@@ -122,11 +213,23 @@ func renderHostLikeRow(width int, active bool, selected bool, host string, hasCf
 		}
 	}
 
-	if !active && hidden {
+	if !active && badges.hidden {
 		hostStr = dim.Render(hostStr)
 	}
 
-	line := prefix + hostStr + suffix
+	// Description fills whatever is left after the host name.
+	desc := ""
+	if d := strings.TrimSpace(badges.description); d != "" && width > 0 {
+		room := hostAvail - lipgloss.Width(displayHost) - 2
+		if room >= 4 {
+			desc = "  " + truncateTail(d, room)
+			if !active {
+				desc = dim.Render(desc)
+			}
+		}
+	}
+
+	line := prefix + hostStr + suffix + desc
 	if width > 0 && active {
 		// Fill to width for a full-row highlight.
 		need := width - lipgloss.Width(line)
@@ -141,6 +244,26 @@ func renderHostLikeRow(width int, active bool, selected bool, host string, hasCf
 	return line
 }
 
+// probeDot is a green (up), red (down) or dim hollow (unknown) dot. Active
+// rows get it unstyled.
+func probeDot(s probe.State, active bool) string {
+	dot := "○"
+	if s != probe.Unknown {
+		dot = "●"
+	}
+	if active {
+		return dot
+	}
+	switch s {
+	case probe.Up:
+		return statusOK.Render(dot)
+	case probe.Down:
+		return statusErr.Render(dot)
+	default:
+		return dim.Render(dot)
+	}
+}
+
 func renderSimpleRow(width int, active bool, text string) string {
 	cur := " "
 	if active {
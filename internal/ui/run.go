@@ -5,6 +5,9 @@ import (
 
 	"github.com/al-bashkir/ssh-tui/internal/config"
 	"github.com/al-bashkir/ssh-tui/internal/hosts"
+	"github.com/al-bashkir/ssh-tui/internal/probe"
+	"github.com/al-bashkir/ssh-tui/internal/sources"
+	"github.com/al-bashkir/ssh-tui/internal/sshconfig"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -28,6 +31,53 @@ type Options struct {
 	LoadErrors    []hosts.PathError
 	Debug         bool
 	Popup         bool // quit after any tmux connect (for tmux popup use)
+
+	// HashedHosts lists hosts only known through hashed known_hosts entries
+	// (sorted); UnresolvedHashed counts hashed entries no candidate matched.
+	HashedHosts      []string
+	UnresolvedHashed int
+	// SSHConfig holds the ~/.ssh/config aliases merged into Hosts (sorted).
+	SSHConfig []sshconfig.Host
+	// Sourced holds the [[sources]] hosts merged into Hosts (sorted by name).
+	Sourced []sources.Host
+	// Matched maps a group name to the hosts its match patterns resolved to.
+	Matched map[string][]string
+	// CustomHostHistory holds hosts typed into the custom host prompt; they
+	// are candidates for hashed known_hosts entries.
+	CustomHostHistory []string
+	// Snippets holds the named commands of snippets.toml.
+	Snippets []config.Snippet
+	// CommandHistory holds commands typed into the connect-with-command
+	// prompt, oldest first.
+	CommandHistory []string
+	// Probes caches the reachability of hosts; nil unless defaults.probe.
+	Probes *probe.Cache
+}
+
+// SetLoadResult stores a host source load result: the hosts, their sources
+// and the members matched by group match patterns.
+func (o *Options) SetLoadResult(res hosts.LoadResult, errs []hosts.PathError) {
+	o.Hosts = res.Hosts
+	o.SkippedLines = res.SkippedLines
+	o.HashedHosts = res.HashedHosts
+	o.UnresolvedHashed = res.UnresolvedHashed
+	o.SSHConfig = res.SSHConfig
+	o.Sourced = res.Sourced
+	o.Matched = res.Matched
+	o.LoadErrors = errs
+}
+
+// loadResult returns the host source state stored in o.
+func (o Options) loadResult() hosts.LoadResult {
+	return hosts.LoadResult{
+		Hosts:            o.Hosts,
+		SkippedLines:     o.SkippedLines,
+		HashedHosts:      o.HashedHosts,
+		UnresolvedHashed: o.UnresolvedHashed,
+		SSHConfig:        o.SSHConfig,
+		Sourced:          o.Sourced,
+		Matched:          o.Matched,
+	}
 }
 
 type exitState interface {
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"github.com/al-bashkir/ssh-tui/internal/resolve"
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+	tmx "github.com/al-bashkir/ssh-tui/internal/tmux"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sftpConnect opens an interactive sftp session to host, with its resolved
+// ssh settings s, in a multiplexer window of its own: the open mode is
+// always tmux-window, so outside tmux it starts a new session and with
+// tmux = "never" it replaces the TUI.
+func sftpConnect(opts Options, host string, s sshcmd.Settings, win resolve.Window) (dispatchResult, tea.Cmd) {
+	argv, err := sshcmd.SFTPCommand(host, s)
+	if err != nil {
+		return dispatchResult{toast: toast{text: err.Error(), level: toastErr}}, nil
+	}
+	inMux := insideMux(opts.Config.Defaults)
+	mode := tmx.ResolveOpenMode(win.Tmux, string(tmx.OpenWindow), inMux, 1)
+	return dispatchConnect(singlePlan(host, argv), opts.Config.Defaults, nil, mode, inMux)
+}
@@ -77,6 +77,9 @@ var (
 	badgeCfgStyle   = lipgloss.NewStyle().Foreground(cAccent).Background(lipgloss.AdaptiveColor{Light: "254", Dark: "235"}).Padding(0, 1).Bold(true)
 	badgeCountStyle = lipgloss.NewStyle().Foreground(cMuted).Background(lipgloss.AdaptiveColor{Light: "254", Dark: "236"}).Padding(0, 1)
 
+	// Host tag pill (#tag) — fixed color, independent of the accent.
+	badgeTagStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "24", Dark: "110"}).Background(lipgloss.AdaptiveColor{Light: "254", Dark: "236"}).Padding(0, 1)
+
 	// Selection count pill badge — inverted accent.
 	badgeSelStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.AdaptiveColor{Light: "255", Dark: "16"}).
@@ -6,7 +6,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var mainTabs = []string{"Hosts", "Groups", "Settings"}
+var mainTabs = []string{"Hosts", "Groups", "Tunnels", "Sessions", "Settings"}
 
 func boxTop(w int) string {
 	if w <= 1 {
@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/al-bashkir/ssh-tui/internal/sshcmd"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type transferPromptField int
+
+const (
+	transferFieldDirection transferPromptField = iota
+	transferFieldLocal
+	transferFieldRemote
+	transferFieldCount
+)
+
+const (
+	transferPush = "push"
+	transferPull = "pull"
+)
+
+// transferPrompt is the modal of the copy action (`F`): the direction and
+// the local and remote paths of an scp run.
+type transferPrompt struct {
+	crumb string
+	hosts int
+
+	focus     transferPromptField
+	direction string
+	inLocal   textinput.Model
+	inRemote  textinput.Model
+	err       string
+}
+
+func newTransferPrompt(width, height int, crumb string, hosts int) *transferPrompt {
+	mw, mh := transferPromptSize(width, height)
+	innerW, _ := frameInnerSize(mw, mh)
+
+	newInput := func() textinput.Model {
+		in := textinput.New()
+		in.CharLimit = 1024
+		in.Prompt = ""
+		in.Width = max(1, min(70, innerW-14))
+		configureSearch(&in)
+		setSearchFocused(&in, false)
+		return in
+	}
+	p := &transferPrompt{crumb: crumb, hosts: hosts, direction: transferPush, inLocal: newInput(), inRemote: newInput()}
+	p.setPlaceholders()
+	p.setFocus(transferFieldLocal)
+	return p
+}
+
+func transferPromptSize(width, height int) (int, int) {
+	return modalSize(width, height, 88, 13, 6, 6)
+}
+
+func (p *transferPrompt) setPlaceholders() {
+	if p.direction == transferPull {
+		p.inLocal.Placeholder = "destination directory on this machine"
+		p.inRemote.Placeholder = "file or directory on the host"
+		return
+	}
+	p.inLocal.Placeholder = "files or directories, space-separated"
+	p.inRemote.Placeholder = "destination on the host (empty: home directory)"
+}
+
+func (p *transferPrompt) refreshAccentStyles() {
+	setSearchFocused(&p.inLocal, p.focus == transferFieldLocal)
+	setSearchFocused(&p.inRemote, p.focus == transferFieldRemote)
+}
+
+func (p *transferPrompt) setFocus(f transferPromptField) {
+	p.focus = f
+	p.inLocal.Blur()
+	p.inRemote.Blur()
+	switch f {
+	case transferFieldLocal:
+		p.inLocal.Focus()
+	case transferFieldRemote:
+		p.inRemote.Focus()
+	}
+	p.refreshAccentStyles()
+}
+
+// update handles a key. It returns done when the prompt closes, with the
+// spec to run on Enter (nil on Esc).
+func (p *transferPrompt) update(msg tea.KeyMsg) (spec *execSpec, done bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return nil, true, nil
+	case "enter":
+		s, err := p.spec()
+		if err != nil {
+			p.err = err.Error()
+			return nil, false, nil
+		}
+		return &s, true, nil
+	case "tab", "down":
+		p.setFocus((p.focus + 1) % transferFieldCount)
+		return nil, false, nil
+	case "shift+tab", "up":
+		p.setFocus((p.focus + transferFieldCount - 1) % transferFieldCount)
+		return nil, false, nil
+	}
+
+	switch p.focus {
+	case transferFieldLocal:
+		p.inLocal, cmd = p.inLocal.Update(msg)
+		return nil, false, cmd
+	case transferFieldRemote:
+		p.inRemote, cmd = p.inRemote.Update(msg)
+		return nil, false, cmd
+	}
+
+	delta := 0
+	switch msg.String() {
+	case "h", "left":
+		delta = -1
+	case "l", "right", " ":
+		delta = 1
+	}
+	if delta != 0 {
+		p.direction = cycleChoice(p.direction, []string{transferPush, transferPull}, delta)
+		p.setPlaceholders()
+	}
+	return nil, false, nil
+}
+
+func (p *transferPrompt) spec() (execSpec, error) {
+	t := sshcmd.Transfer{Pull: p.direction == transferPull, Remote: strings.TrimSpace(p.inRemote.Value())}
+	for _, f := range strings.Fields(p.inLocal.Value()) {
+		t.Local = append(t.Local, expandLocalPath(f))
+	}
+	switch {
+	case len(t.Local) == 0 && t.Pull:
+		return execSpec{}, fmt.Errorf("local directory required")
+	case len(t.Local) == 0:
+		return execSpec{}, fmt.Errorf("local path required")
+	case t.Pull && len(t.Local) > 1:
+		return execSpec{}, fmt.Errorf("pull takes one local directory")
+	case t.Pull && t.Remote == "":
+		return execSpec{}, fmt.Errorf("remote path required")
+	}
+	if !t.Pull {
+		for _, f := range t.Local {
+			if _, err := os.Stat(f); err != nil {
+				return execSpec{}, fmt.Errorf("%s: no such file or directory", f)
+			}
+		}
+	}
+	return execSpec{transfer: &t}, nil
+}
+
+// expandLocalPath expands a leading ~ to the home directory; scp runs
+// without a shell.
+func expandLocalPath(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
+
+func (p *transferPrompt) View(width, height int) string {
+	mw, mh := transferPromptSize(width, height)
+	labelW := 12
+	label := func(s string, focused bool) string {
+		padded := s + strings.Repeat(" ", max(0, labelW-len(s)))
+		if focused {
+			return headerStyle.Render(padded)
+		}
+		return padded
+	}
+	seg := func(on bool, text string, focused bool) string {
+		if on {
+			box := "[" + text + "]"
+			if focused {
+				return segFocusedStyle.Render(box)
+			}
+			return checkedStyle.Render(box)
+		}
+		return tabInactiveStyle.Render(text)
+	}
+
+	intro := fmt.Sprintf("Copy files to %d hosts with scp.", p.hosts)
+	if p.direction == transferPull {
+		intro = fmt.Sprintf("Copy files from %d hosts with scp.", p.hosts)
+		if p.hosts > 1 {
+			intro += " Each host gets its own subdirectory."
+		}
+	}
+	var lines []string
+	lines = append(lines, intro, "")
+	dirFocused := p.focus == transferFieldDirection
+	lines = append(lines, label("Direction:", dirFocused)+" "+
+		seg(p.direction == transferPush, "push", dirFocused)+"  "+seg(p.direction == transferPull, "pull", dirFocused))
+	lines = append(lines, label("Local:", p.focus == transferFieldLocal)+" "+p.inLocal.View())
+	lines = append(lines, label("Remote:", p.focus == transferFieldRemote)+" "+p.inRemote.View())
+	if p.err != "" {
+		lines = append(lines, "", statusErr.Render(p.err))
+	}
+	footer := footerStyle.Render("Enter copy  Tab next  ←/→ change  Esc cancel")
+	box := renderFrame(mw, mh, breadcrumbTitle(p.crumb, "Copy"), "", strings.Join(lines, "\n"), footer)
+	return placeCentered(width, height, box)
+}
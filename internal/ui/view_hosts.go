@@ -11,15 +11,11 @@ func (m *hostsModel) View() string {
 	if m.showHelp {
 		return renderHelpModalWithVP(m.width, m.height, "Hosts", m.help, m.helpKeys(), &m.helpVP)
 	}
-	if m.cmdPrompt {
-		mw, mh := modalSize(m.width, m.height, 88, 9, 6, 10)
-		var b strings.Builder
-		b.WriteString("Connect and run a remote command (keeps session open).\n\n")
-		b.WriteString(m.cmdInput.View())
-		b.WriteString("\n")
-		b.WriteString(footerStyle.Render("Enter connect  Esc cancel"))
-		box := renderFrame(mw, mh, breadcrumbTitle("Hosts", "Command"), "", strings.TrimRight(b.String(), "\n"), "")
-		return placeCentered(m.width, m.height, box)
+	if m.execPr != nil {
+		return m.execPr.View(m.width, m.height)
+	}
+	if m.cmdPr != nil {
+		return m.cmdPr.View(m.width, m.height)
 	}
 	if m.confirmQuit {
 		return renderQuitConfirm(m.width, m.height)
@@ -28,6 +24,9 @@ func (m *hostsModel) View() string {
 		modal := connectConfirmBox(max(0, m.width-4), m.confirmConnectCount, m.confirmConnectHosts)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
 	}
+	if m.explain != nil {
+		return placeCentered(m.width, m.height, explainBox(max(0, m.width-4), m.explain))
+	}
 
 	hasWarn := m.opts.SkippedLines > 0 || len(m.opts.LoadErrors) > 0
 	right := ""
@@ -58,6 +57,9 @@ func (m *hostsModel) View() string {
 				right += "  " + dim.Render(fmt.Sprintf("%d hidden", hc))
 			}
 		}
+		if n := m.opts.UnresolvedHashed; n > 0 {
+			right += "  " + dim.Render(fmt.Sprintf("%d hashed unresolved", n))
+		}
 	}
 	var footer string
 	hasSel := len(m.selected) > 0
@@ -139,6 +141,9 @@ func (m *hostsModel) statusLine() string {
 	if m.opts.SkippedLines > 0 {
 		loadBits = append(loadBits, fmt.Sprintf("skipped:%d", m.opts.SkippedLines))
 	}
+	if m.opts.UnresolvedHashed > 0 {
+		loadBits = append(loadBits, fmt.Sprintf("hashed:%d", m.opts.UnresolvedHashed))
+	}
 	if len(m.opts.LoadErrors) > 0 {
 		loadBits = append(loadBits, fmt.Sprintf("errors:%d", len(m.opts.LoadErrors)))
 	}